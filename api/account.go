@@ -1,11 +1,14 @@
 package api
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"net/http"
 
+	"github.com/October-9th/simple-bank/authz"
 	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/money"
 	"github.com/October-9th/simple-bank/token"
 	"github.com/gin-gonic/gin"
 	"github.com/lib/pq"
@@ -71,8 +74,7 @@ func (server *Server) getAccount(ctx *gin.Context) {
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	if account.Owner != authPayload.Username {
-		err := errors.New("account doesn't belong to the authenticated user")
+	if err := authz.Authorize(authPayload, authz.ReadAccount, account.Owner); err != nil {
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
@@ -83,8 +85,10 @@ func (server *Server) getAccount(ctx *gin.Context) {
 // API to get list of account using pagination
 
 type listAccountRequest struct {
-	PageID   int32 `form:"page_id" binding:"required,min=1"`
-	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+	// Owner defaults to the authenticated user, listing another user's accounts requires a staff role
+	Owner    string `form:"owner" binding:"omitempty,alphanum"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=10"`
 }
 
 func (server *Server) getListAccount(ctx *gin.Context) {
@@ -96,8 +100,17 @@ func (server *Server) getListAccount(ctx *gin.Context) {
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	owner := authPayload.Username
+	if req.Owner != "" {
+		owner = req.Owner
+	}
+	if err := authz.Authorize(authPayload, authz.ReadAccount, owner); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
 	arg := sqlc.ListAccountsParams{
-		Owner:  authPayload.Username,
+		Owner:  owner,
 		Limit:  req.PageSize,
 		Offset: (req.PageID - 1) * req.PageSize,
 	}
@@ -109,58 +122,227 @@ func (server *Server) getListAccount(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, listAccounts)
 }
 
-type updateAccountRequest struct {
-	ID      int64 `json:"id" binding:"required,min=1"`
-	Balance int64 `json:"balance" binding:"required,min=1"`
+type adjustAccountBalanceRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type adjustAccountBalanceBody struct {
+	// Amount is added to the balance in minor units of the account currency, a negative amount takes money out
+	Amount int64  `json:"amount" binding:"required"`
+	Reason string `json:"reason" binding:"required,max=200"`
 }
 
-func (server *Server) updateAccount(ctx *gin.Context) {
-	req := &updateAccountRequest{}
+// adjustAccountBalance replaces the old balance overwrite. Only admins may call it,
+// and every adjustment is recorded as an entry together with who made it and why
+func (server *Server) adjustAccountBalance(ctx *gin.Context) {
+	req := &adjustAccountBalanceRequest{}
+	if err := ctx.ShouldBindUri(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	body := &adjustAccountBalanceBody{}
+	if err := ctx.ShouldBindJSON(body); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
 
-	if err := ctx.ShouldBind(req); err != nil {
+	account, err := server.store.GetAccount(ctx, req.ID)
+	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
 			return
 		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if err := authz.Authorize(authPayload, authz.AdjustBalance, account.Owner); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	amount, err := money.New(body.Amount, account.Currency)
+	if err != nil {
+		if errors.Is(err, money.ErrOverflow) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.AdjustBalanceTx(ctx, sqlc.AdjustBalanceTxParams{
+		AccountID:  req.ID,
+		Amount:     amount,
+		Reason:     body.Reason,
+		AdjustedBy: authPayload.Username,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sqlc.ErrAccountClosed):
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+		case errors.Is(err, sqlc.ErrInsufficientFunds), errors.Is(err, money.ErrOverflow), errors.Is(err, money.ErrCurrencyMismatch):
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type updateOverdraftLimitRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type updateOverdraftLimitBody struct {
+	// OverdraftLimit is how far below zero the balance may go, in minor units of the account currency
+	OverdraftLimit *int64 `json:"overdraft_limit" binding:"required,min=0"`
+	Reason         string `json:"reason" binding:"required,max=200"`
+}
+
+// updateOverdraftLimit lets bankers and admins set how far below zero an account may go.
+// Every change is recorded together with the previous limit, who made it and why
+func (server *Server) updateOverdraftLimit(ctx *gin.Context) {
+	req := &updateOverdraftLimitRequest{}
+	if err := ctx.ShouldBindUri(req); err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
-	arg := sqlc.UpdateAccountParams{
-		ID:      req.ID,
-		Balance: req.Balance,
+	body := &updateOverdraftLimitBody{}
+	if err := ctx.ShouldBindJSON(body); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if err := authz.Authorize(authPayload, authz.SetOverdraftLimit, account.Owner); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.UpdateOverdraftLimitTx(ctx, sqlc.UpdateOverdraftLimitTxParams{
+		AccountID:      req.ID,
+		OverdraftLimit: *body.OverdraftLimit,
+		Reason:         body.Reason,
+		ChangedBy:      authPayload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, sqlc.ErrAccountClosed) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type closeAccountRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// closeAccount closes an active account instead of deleting it, so its entries and transfers are kept.
+// The balance has to be zero, and a closed account takes no more transfers.
+// Owners close their own accounts, admins can close any account
+func (server *Server) closeAccount(ctx *gin.Context) {
+	req := &closeAccountRequest{}
+	if err := ctx.ShouldBindUri(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
 	}
-	account, err := server.store.UpdateAccount(ctx, arg)
+
+	account, err := server.store.GetAccount(ctx, req.ID)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if err := authz.Authorize(authPayload, authz.ManageAccount, account.Owner); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	account, err = server.store.CloseAccountTx(ctx, req.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, sqlc.ErrAccountClosed), errors.Is(err, sqlc.ErrInvalidStatusTransition):
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+		case errors.Is(err, sqlc.ErrNonZeroBalance):
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
 	ctx.JSON(http.StatusOK, account)
 }
 
-type deleteAccountRequest struct {
+type accountStatusRequest struct {
 	ID int64 `uri:"id" binding:"required,min=1"`
 }
 
-func (server *Server) deleteAccount(ctx *gin.Context) {
-	req := &deleteAccountRequest{}
+// freezeAccount stops an account under investigation from sending or receiving money
+func (server *Server) freezeAccount(ctx *gin.Context) {
+	server.changeAccountStatus(ctx, server.store.FreezeAccountTx)
+}
+
+// unfreezeAccount makes a frozen account active again
+func (server *Server) unfreezeAccount(ctx *gin.Context) {
+	server.changeAccountStatus(ctx, server.store.UnfreezeAccountTx)
+}
 
+func (server *Server) changeAccountStatus(ctx *gin.Context, change func(context.Context, int64) (sqlc.Account, error)) {
+	req := &accountStatusRequest{}
 	if err := ctx.ShouldBindUri(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, req.ID)
+	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
 			return
 		}
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if err := authz.Authorize(authPayload, authz.FreezeAccount, account.Owner); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
 
-	err := server.store.DeleteAccount(ctx, req.ID)
+	account, err = change(ctx, req.ID)
 	if err != nil {
+		if errors.Is(err, sqlc.ErrAccountClosed) || errors.Is(err, sqlc.ErrInvalidStatusTransition) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"message": "Request deleted successfully",
-	})
+	ctx.JSON(http.StatusOK, account)
 }
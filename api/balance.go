@@ -0,0 +1,117 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/October-9th/simple-bank/authz"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/money"
+	"github.com/October-9th/simple-bank/token"
+	"github.com/gin-gonic/gin"
+)
+
+type balanceResponse struct {
+	AccountID int64        `json:"account_id"`
+	Balance   money.Amount `json:"balance"`
+	AsOf      time.Time    `json:"as_of"`
+}
+
+func newBalanceResponse(balance sqlc.AccountBalance) balanceResponse {
+	return balanceResponse{
+		AccountID: balance.AccountID,
+		Balance:   money.Amount{Minor: balance.Balance, Currency: balance.Currency},
+		AsOf:      balance.AsOf,
+	}
+}
+
+type getBalanceAsOfRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type getBalanceAsOfQuery struct {
+	// AsOf is an RFC 3339 timestamp, entries created at that instant are included
+	AsOf time.Time `form:"as_of" binding:"required"`
+}
+
+// getBalanceAsOf returns the balance an account had at a point in time, computed from its entries
+func (server *Server) getBalanceAsOf(ctx *gin.Context) {
+	req := &getBalanceAsOfRequest{}
+	if err := ctx.ShouldBindUri(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	query := &getBalanceAsOfQuery{}
+	if err := ctx.ShouldBindQuery(query); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if err := authz.Authorize(authPayload, authz.ReadAccount, account.Owner); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	balance, err := server.store.GetBalanceAsOf(ctx, sqlc.GetBalanceAsOfParams{
+		AccountID: req.ID,
+		AsOf:      query.AsOf,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newBalanceResponse(balance))
+}
+
+type listBalancesAsOfRequest struct {
+	// Owner defaults to the authenticated user, another user's balances require a staff role
+	Owner string    `form:"owner" binding:"omitempty,alphanum"`
+	AsOf  time.Time `form:"as_of" binding:"required"`
+}
+
+// listBalancesAsOf returns the balance at a point in time of every account a user had by then
+func (server *Server) listBalancesAsOf(ctx *gin.Context) {
+	req := &listBalancesAsOfRequest{}
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	owner := authPayload.Username
+	if req.Owner != "" {
+		owner = req.Owner
+	}
+	if err := authz.Authorize(authPayload, authz.ReadAccount, owner); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	balances, err := server.store.ListBalancesAsOf(ctx, sqlc.ListBalancesAsOfParams{
+		Owner: owner,
+		AsOf:  req.AsOf,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]balanceResponse, 0, len(balances))
+	for _, balance := range balances {
+		rsp = append(rsp, newBalanceResponse(balance))
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/October-9th/simple-bank/authz"
+	"github.com/October-9th/simple-bank/token"
+	"github.com/gin-gonic/gin"
+)
+
+type verifyEntryChainRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// verifyEntryChain lets admins check that no entry of an account, nor the transfer behind it, was edited,
+// removed or slipped in after it was written. A broken chain is reported in the body, not as an error
+func (server *Server) verifyEntryChain(ctx *gin.Context) {
+	req := &verifyEntryChainRequest{}
+	if err := ctx.ShouldBindUri(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if err := authz.Authorize(authPayload, authz.Reconcile, ""); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.VerifyEntryChain(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/October-9th/simple-bank/authz"
+	"github.com/October-9th/simple-bank/fx"
+	"github.com/October-9th/simple-bank/token"
+	"github.com/gin-gonic/gin"
+)
+
+type loadExchangeRatesRequest struct {
+	Rates []fx.Rate `json:"rates" binding:"required,min=1,max=1000"`
+}
+
+// loadExchangeRates lets admins add or replace exchange rates. The body is either JSON
+// or, with a text/csv content type, a rates file in the format read by fx.ReadCSV
+func (server *Server) loadExchangeRates(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if err := authz.Authorize(authPayload, authz.ManageExchangeRates, ""); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	req := &loadExchangeRatesRequest{}
+	if ctx.ContentType() == "text/csv" {
+		rates, err := fx.ReadCSV(ctx.Request.Body)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		req.Rates = rates
+	} else if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	for i, rate := range req.Rates {
+		if err := rate.Validate(); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("rates[%d]: %w", i, err)))
+			return
+		}
+	}
+
+	rates, err := server.store.LoadExchangeRatesTx(ctx, req.Rates)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rates)
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/October-9th/simple-bank/database/mock"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/fx"
+	"github.com/October-9th/simple-bank/token"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadExchangeRatesAPI(t *testing.T) {
+	rate := fx.Rate{
+		Base:        util.USD,
+		Quote:       util.EUR,
+		Rate:        "0.92",
+		EffectiveAt: time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC),
+	}
+	csvBody := "base_currency,quote_currency,rate,effective_at\nUSD,EUR,0.92,2023-07-01T00:00:00Z\n"
+
+	jsonBody := func(t *testing.T, rates ...fx.Rate) io.Reader {
+		data, err := json.Marshal(gin.H{"rates": rates})
+		require.NoError(t, err)
+		return bytes.NewReader(data)
+	}
+
+	testCases := []struct {
+		name          string
+		contentType   string
+		body          func(t *testing.T) io.Reader
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "OK",
+			contentType: "application/json",
+			body:        func(t *testing.T) io.Reader { return jsonBody(t, rate) },
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "admin", util.AdminRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					LoadExchangeRatesTx(gomock.Any(), gomock.Eq([]fx.Rate{rate})).
+					Times(1).
+					Return([]sqlc.ExchangeRate{{ID: 1, BaseCurrency: rate.Base, QuoteCurrency: rate.Quote, Rate: rate.Rate}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:        "CSV",
+			contentType: "text/csv",
+			body:        func(t *testing.T) io.Reader { return strings.NewReader(csvBody) },
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "admin", util.AdminRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					LoadExchangeRatesTx(gomock.Any(), gomock.Eq([]fx.Rate{rate})).
+					Times(1).
+					Return([]sqlc.ExchangeRate{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:        "NotAdmin",
+			contentType: "application/json",
+			body:        func(t *testing.T) io.Reader { return jsonBody(t, rate) },
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "banker", util.BankerRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().LoadExchangeRatesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:        "InvalidRate",
+			contentType: "application/json",
+			body: func(t *testing.T) io.Reader {
+				invalid := rate
+				invalid.Rate = "-1"
+				return jsonBody(t, invalid)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "admin", util.AdminRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().LoadExchangeRatesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "InvalidCSV",
+			contentType: "text/csv",
+			body:        func(t *testing.T) io.Reader { return strings.NewReader("USD,EUR,0.92\n") },
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "admin", util.AdminRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().LoadExchangeRatesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestSever(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/exchange_rates", tc.body(t))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", tc.contentType)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
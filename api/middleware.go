@@ -6,8 +6,10 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/October-9th/simple-bank/database/sqlc"
 	"github.com/October-9th/simple-bank/token"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 const (
@@ -19,7 +21,10 @@ const (
 	authorizationPayloadKey = "authorization_payload"
 )
 
-func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+// errSessionRevoked is returned for an access token of a session that was logged out or revoked
+var errSessionRevoked = errors.New("session has been revoked")
+
+func authMiddleware(tokenMaker token.Maker, store sqlc.Store) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		// Get the authorization header
 		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
@@ -47,14 +52,32 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 			return
 		}
 
-		// Get the access token and use it to verify the payload
+		// Get the access token and use it to verify the payload,
+		// reading needs the read scope and anything else the write scope
 		accessToken := fields[1]
-		payload, err := tokenMaker.VerifyToken(accessToken)
+		scope := token.ScopeWrite
+		if ctx.Request.Method == http.MethodGet || ctx.Request.Method == http.MethodHead {
+			scope = token.ScopeRead
+		}
+		payload, err := tokenMaker.VerifyToken(accessToken, token.TokenTypeAccess, scope)
 		if err != nil {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
 			return
 		}
 
+		// Revoking a session blocks its refresh tokens, its access tokens stop working right away too
+		if payload.SessionID != uuid.Nil {
+			blocked, err := store.IsSessionFamilyBlocked(ctx, payload.SessionID)
+			if err != nil {
+				ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+				return
+			}
+			if blocked {
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(errSessionRevoked))
+				return
+			}
+		}
+
 		// If the payload is valid, store it in the context before parsing it to the next handler
 		ctx.Set(authorizationPayloadKey, payload)
 		// Forward the request to the next handler
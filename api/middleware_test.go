@@ -1,14 +1,19 @@
 package api
 
 import (
+	"database/sql"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	mockdb "github.com/October-9th/simple-bank/database/mock"
 	"github.com/October-9th/simple-bank/token"
+	"github.com/October-9th/simple-bank/util"
 	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
@@ -18,15 +23,19 @@ func addAuthorization(
 	tokenMaker token.Maker,
 	authorizationType string,
 	username string,
+	role string,
 	duration time.Duration,
+	options ...token.Option,
 ) {
-	token, payload, err := tokenMaker.CreateToken(username, duration)
+	// The access token of a logged in user unless options say otherwise
+	options = append([]token.Option{token.WithScopes(token.DefaultScopes...)}, options...)
+	accessToken, payload, err := tokenMaker.CreateToken(username, role, duration, options...)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
-	require.NotEmpty(t, token)
+	require.NotEmpty(t, accessToken)
 
 	// Create authorization header value
-	authorizationHeader := fmt.Sprintf("%s %s", authorizationType, token)
+	authorizationHeader := fmt.Sprintf("%s %s", authorizationType, accessToken)
 	// Set the header of the request
 	request.Header.Set(authorizationHeaderKey, authorizationHeader)
 }
@@ -40,7 +49,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "Ok",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", util.DepositorRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -57,7 +66,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "UnsupportedAuthorization",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "unsupported ", "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, "unsupported ", "user", util.DepositorRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -66,7 +75,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "UnsupportedAuthorization",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "unsupported", "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, "unsupported", "user", util.DepositorRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -75,7 +84,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "InvalidAuthorization",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "", "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, "", "user", util.DepositorRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -84,12 +93,42 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "ExpiredToken",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "unsupported ", "user", -time.Minute)
+				addAuthorization(t, request, tokenMaker, "unsupported ", "user", util.DepositorRole, -time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 		},
+		{
+			name: "RefreshToken",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", util.DepositorRole, time.Minute,
+					token.WithType(token.TokenTypeRefresh))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "MissingReadScope",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", util.DepositorRole, time.Minute,
+					token.WithScopes(token.ScopeWrite))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "ReadOnly",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", util.DepositorRole, time.Minute,
+					token.WithScopes(token.ScopeRead))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
 	}
 
 	for i := range testCases {
@@ -105,7 +144,7 @@ func TestAuthMiddleware(t *testing.T) {
 				// Path
 				authPath,
 				// Middleware
-				authMiddleware(server.tokenMaker),
+				authMiddleware(server.tokenMaker, server.store),
 				// Handler
 				func(ctx *gin.Context) {
 					ctx.JSON(http.StatusOK, gin.H{})
@@ -124,3 +163,94 @@ func TestAuthMiddleware(t *testing.T) {
 
 	}
 }
+
+func TestAuthMiddlewareWriteScope(t *testing.T) {
+	server := newTestSever(t, nil)
+
+	authPath := "/api/v1/auth"
+	server.router.POST(authPath, authMiddleware(server.tokenMaker, server.store), func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{})
+	})
+
+	// A read-only token can't change anything
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, authPath, nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "user", util.DepositorRole, time.Minute,
+		token.WithScopes(token.ScopeRead))
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodPost, authPath, nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "user", util.DepositorRole, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestAuthMiddlewareRevokedSession(t *testing.T) {
+	familyID := uuid.New()
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Active",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().IsSessionFamilyBlocked(gomock.Any(), gomock.Eq(familyID)).Times(1).Return(false, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Revoked",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().IsSessionFamilyBlocked(gomock.Any(), gomock.Eq(familyID)).Times(1).Return(true, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().IsSessionFamilyBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestSever(t, store)
+			authPath := "/api/v1/auth"
+			server.router.GET(authPath, authMiddleware(server.tokenMaker, server.store), func(ctx *gin.Context) {
+				ctx.JSON(http.StatusOK, gin.H{})
+			})
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, authPath, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "user", util.DepositorRole, time.Minute,
+				token.WithSessionID(familyID))
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/October-9th/simple-bank/authz"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/token"
+	"github.com/gin-gonic/gin"
+)
+
+type reconciliationRunResponse struct {
+	ID               int64     `json:"id"`
+	StartedBy        string    `json:"started_by"`
+	AccountsChecked  int64     `json:"accounts_checked"`
+	TransfersChecked int64     `json:"transfers_checked"`
+	EntriesChecked   int64     `json:"entries_checked"`
+	DiscrepancyCount int64     `json:"discrepancy_count"`
+	StartedAt        time.Time `json:"started_at"`
+	FinishedAt       time.Time `json:"finished_at"`
+}
+
+type discrepancyResponse struct {
+	Kind       string `json:"kind"`
+	AccountID  *int64 `json:"account_id,omitempty"`
+	TransferID *int64 `json:"transfer_id,omitempty"`
+	EntryID    *int64 `json:"entry_id,omitempty"`
+	Expected   int64  `json:"expected"`
+	Actual     int64  `json:"actual"`
+	Detail     string `json:"detail"`
+}
+
+type reconciliationResponse struct {
+	Run           reconciliationRunResponse `json:"run"`
+	Discrepancies []discrepancyResponse     `json:"discrepancies"`
+}
+
+func newReconciliationRunResponse(run sqlc.ReconciliationRun) reconciliationRunResponse {
+	return reconciliationRunResponse{
+		ID:               run.ID,
+		StartedBy:        run.StartedBy,
+		AccountsChecked:  run.AccountsChecked,
+		TransfersChecked: run.TransfersChecked,
+		EntriesChecked:   run.EntriesChecked,
+		DiscrepancyCount: run.DiscrepancyCount,
+		StartedAt:        run.StartedAt,
+		FinishedAt:       run.FinishedAt,
+	}
+}
+
+func newReconciliationResponse(run sqlc.ReconciliationRun, discrepancies []sqlc.ReconciliationDiscrepancy) reconciliationResponse {
+	rsp := reconciliationResponse{
+		Run:           newReconciliationRunResponse(run),
+		Discrepancies: make([]discrepancyResponse, 0, len(discrepancies)),
+	}
+	for _, discrepancy := range discrepancies {
+		rsp.Discrepancies = append(rsp.Discrepancies, discrepancyResponse{
+			Kind:       discrepancy.Kind,
+			AccountID:  nullableID(discrepancy.AccountID),
+			TransferID: nullableID(discrepancy.TransferID),
+			EntryID:    nullableID(discrepancy.EntryID),
+			Expected:   discrepancy.Expected,
+			Actual:     discrepancy.Actual,
+			Detail:     discrepancy.Detail,
+		})
+	}
+	return rsp
+}
+
+func nullableID(id sql.NullInt64) *int64 {
+	if !id.Valid {
+		return nil
+	}
+	return &id.Int64
+}
+
+// runReconciliation lets admins check right away that the ledger balances, the report is kept for later
+func (server *Server) runReconciliation(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if err := authz.Authorize(authPayload, authz.Reconcile, ""); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.ReconcileTx(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newReconciliationResponse(result.Run, result.Discrepancies))
+}
+
+type getReconciliationRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getReconciliation returns a past reconciliation run with every discrepancy it found
+func (server *Server) getReconciliation(ctx *gin.Context) {
+	req := &getReconciliationRequest{}
+	if err := ctx.ShouldBindUri(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if err := authz.Authorize(authPayload, authz.Reconcile, ""); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	run, err := server.store.GetReconciliationRun(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	discrepancies, err := server.store.ListReconciliationDiscrepancies(ctx, run.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newReconciliationResponse(run, discrepancies))
+}
+
+type listReconciliationsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+// listReconciliations returns past reconciliation runs, latest first, without their discrepancies
+func (server *Server) listReconciliations(ctx *gin.Context) {
+	req := &listReconciliationsRequest{}
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if err := authz.Authorize(authPayload, authz.Reconcile, ""); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	runs, err := server.store.ListReconciliationRuns(ctx, sqlc.ListReconciliationRunsParams{
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]reconciliationRunResponse, 0, len(runs))
+	for _, run := range runs {
+		rsp = append(rsp, newReconciliationRunResponse(run))
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
@@ -22,7 +22,7 @@ type Server struct {
 // NewServer create a new HTTP server and setup routing.
 func NewServer(config util.Config, store sqlc.Store) (*Server, error) {
 
-	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmectricKey)
+	tokenMaker, err := token.NewMaker(config)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't create token maker: %v", err)
 	}
@@ -35,6 +35,7 @@ func NewServer(config util.Config, store sqlc.Store) (*Server, error) {
 	// Register custom validator with Gin
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		v.RegisterValidation("currency", validCurrency)
+		v.RegisterValidation("role", validRole)
 	}
 	// Add routes to router
 	server.setupRouter()
@@ -49,16 +50,38 @@ func (server *Server) setupRouter() {
 	router.POST("/api/v1/users", server.CreateUser)
 	router.POST("/api/v1/users/login", server.loginUser)
 	router.POST("/api/v1/users/renew_access", server.renewAccessToken)
-	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker))
+	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker, server.store))
+
+	authRoutes.PUT("/api/v1/users/:username/role", server.updateUserRole)
+	authRoutes.POST("/api/v1/users/logout", server.logoutUser)
+	authRoutes.POST("/api/v1/users/logout_all", server.logoutAllSessions)
+	authRoutes.GET("/api/v1/sessions", server.listSessions)
+	authRoutes.DELETE("/api/v1/sessions/:id", server.revokeSession)
+	authRoutes.POST("/api/v1/sessions/revoke_others", server.revokeOtherSessions)
 
 	authRoutes.POST("/api/v1/accounts", server.createAccount)
 	authRoutes.GET("/api/v1/accounts/:id", server.getAccount)
 	authRoutes.GET("/api/v1/accounts", server.getListAccount)
-	authRoutes.PUT("/api/v1/accounts", server.updateAccount)
-	authRoutes.DELETE("/api/v1/accounts/:id", server.deleteAccount)
+	authRoutes.POST("/api/v1/accounts/:id/adjustments", server.adjustAccountBalance)
+	authRoutes.PUT("/api/v1/accounts/:id/overdraft_limit", server.updateOverdraftLimit)
+	authRoutes.DELETE("/api/v1/accounts/:id", server.closeAccount)
+	authRoutes.POST("/api/v1/accounts/:id/freeze", server.freezeAccount)
+	authRoutes.POST("/api/v1/accounts/:id/unfreeze", server.unfreezeAccount)
+	authRoutes.GET("/api/v1/accounts/:id/statement", server.getAccountStatement)
+	authRoutes.GET("/api/v1/accounts/:id/balance", server.getBalanceAsOf)
+	authRoutes.GET("/api/v1/accounts/:id/entry_chain", server.verifyEntryChain)
+	authRoutes.GET("/api/v1/balances", server.listBalancesAsOf)
 
 	// Routes for hanlder transfer api request
 	authRoutes.POST("/api/v1/transfers", server.createTransfer)
+	authRoutes.GET("/api/v1/transfers", server.searchTransfers)
+	authRoutes.POST("/api/v1/transfers/:id/reversals", server.reverseTransfer)
+
+	authRoutes.POST("/api/v1/exchange_rates", server.loadExchangeRates)
+
+	authRoutes.POST("/api/v1/reconciliations", server.runReconciliation)
+	authRoutes.GET("/api/v1/reconciliations", server.listReconciliations)
+	authRoutes.GET("/api/v1/reconciliations/:id", server.getReconciliation)
 
 	// Routes for handler usr api request
 
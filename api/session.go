@@ -0,0 +1,122 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// errNoSession is returned when the access token predates sessions being stamped on tokens
+var errNoSession = errors.New("access token isn't tied to a session, log in again")
+
+// sessionResponse is a login of the user, a device or browser that can still renew its tokens.
+// Its id is the one of the session family, it doesn't change when the tokens are renewed
+type sessionResponse struct {
+	ID           uuid.UUID `json:"id"`
+	UserAgent    string    `json:"user_agent"`
+	ClientIP     string    `json:"client_ip"`
+	SignedInAt   time.Time `json:"signed_in_at"`
+	LastActiveAt time.Time `json:"last_active_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	// Current is the session of the token the request was made with
+	Current bool `json:"current"`
+}
+
+func newSessionResponse(session sqlc.ListActiveSessionsRow, currentID uuid.UUID) sessionResponse {
+	return sessionResponse{
+		ID:           session.FamilyID,
+		UserAgent:    session.UserAgent,
+		ClientIP:     session.ClientIp,
+		SignedInAt:   session.SignedInAt,
+		LastActiveAt: session.CreatedAt,
+		ExpiresAt:    session.ExpiresAt,
+		Current:      session.FamilyID == currentID,
+	}
+}
+
+type listSessionsResponse struct {
+	Sessions []sessionResponse `json:"sessions"`
+}
+
+// listSessions lists the active sessions of the authenticated user, the most recently used first
+func (server *Server) listSessions(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	sessions, err := server.store.ListActiveSessions(ctx, sqlc.ListActiveSessionsParams{
+		Username: authPayload.Username,
+		Now:      time.Now(),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := listSessionsResponse{Sessions: make([]sessionResponse, 0, len(sessions))}
+	for _, session := range sessions {
+		rsp.Sessions = append(rsp.Sessions, newSessionResponse(session, authPayload.SessionID))
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type revokeSessionURI struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// revokeSession blocks a session of the authenticated user, it can't be renewed anymore and its access tokens are refused.
+// Revoking the current session is the same as logging out
+func (server *Server) revokeSession(ctx *gin.Context) {
+	uri := &revokeSessionURI{}
+	if err := ctx.ShouldBindUri(uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	// Only sessions of the user are blocked, those of someone else are as not found as those that don't exist
+	blocked, err := server.store.BlockUserSessionFamily(ctx, sqlc.BlockUserSessionFamilyParams{
+		FamilyID: uuid.MustParse(uri.ID),
+		Username: authPayload.Username,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if blocked == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(sql.ErrNoRows))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Session revoked successfully",
+	})
+}
+
+type revokeOtherSessionsResponse struct {
+	RevokedSessions int64 `json:"revoked_sessions"`
+}
+
+// revokeOtherSessions blocks every session of the authenticated user but the one the request was made with
+func (server *Server) revokeOtherSessions(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.SessionID == uuid.Nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errNoSession))
+		return
+	}
+
+	revoked, err := server.store.BlockOtherUserSessions(ctx, sqlc.BlockOtherUserSessionsParams{
+		Username:     authPayload.Username,
+		KeptFamilyID: authPayload.SessionID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, revokeOtherSessionsResponse{RevokedSessions: revoked})
+}
@@ -0,0 +1,375 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/October-9th/simple-bank/database/mock"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/token"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func randomActiveSession(username string) sqlc.ListActiveSessionsRow {
+	familyID := uuid.New()
+	return sqlc.ListActiveSessionsRow{
+		ID:         uuid.New(),
+		Username:   username,
+		UserAgent:  util.RandomString(12),
+		ClientIp:   "127.0.0.1",
+		ExpiresAt:  time.Now().Add(time.Hour).UTC().Truncate(time.Second),
+		CreatedAt:  time.Now().UTC().Truncate(time.Second),
+		FamilyID:   familyID,
+		SignedInAt: time.Now().Add(-time.Hour).UTC().Truncate(time.Second),
+	}
+}
+
+func TestListSessionsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	current := randomActiveSession(user.Username)
+	other := randomActiveSession(user.Username)
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute,
+					token.WithSessionID(current.FamilyID))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().IsSessionFamilyBlocked(gomock.Any(), gomock.Eq(current.FamilyID)).Times(1).Return(false, nil)
+				store.EXPECT().ListActiveSessions(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, arg sqlc.ListActiveSessionsParams) ([]sqlc.ListActiveSessionsRow, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.WithinDuration(t, time.Now(), arg.Now, time.Second)
+						return []sqlc.ListActiveSessionsRow{other, current}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp listSessionsResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp.Sessions, 2)
+				require.Equal(t, other.FamilyID, rsp.Sessions[0].ID)
+				require.False(t, rsp.Sessions[0].Current)
+				require.Equal(t, current.FamilyID, rsp.Sessions[1].ID)
+				require.True(t, rsp.Sessions[1].Current)
+				require.Equal(t, current.UserAgent, rsp.Sessions[1].UserAgent)
+				require.Equal(t, current.SignedInAt, rsp.Sessions[1].SignedInAt)
+				require.Equal(t, current.CreatedAt, rsp.Sessions[1].LastActiveAt)
+			},
+		},
+		{
+			name: "NoSessions",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListActiveSessions(gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"sessions":[]}`, recorder.Body.String())
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListActiveSessions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListActiveSessions(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestSever(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/v1/sessions", nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRevokeSessionAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	familyID := uuid.New()
+
+	testCases := []struct {
+		name          string
+		id            string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			id:   familyID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := sqlc.BlockUserSessionFamilyParams{FamilyID: familyID, Username: user.Username}
+				store.EXPECT().BlockUserSessionFamily(gomock.Any(), gomock.Eq(arg)).Times(1).Return(int64(2), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			// A session of another user is filtered out by the query, the same as one that doesn't exist
+			name: "NotFound",
+			id:   familyID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().BlockUserSessionFamily(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidID",
+			id:   "not-a-uuid",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().BlockUserSessionFamily(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			id:   familyID.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().BlockUserSessionFamily(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestSever(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/sessions/%s", tc.id)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRevokeOtherSessionsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	familyID := uuid.New()
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute,
+					token.WithSessionID(familyID))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().IsSessionFamilyBlocked(gomock.Any(), gomock.Eq(familyID)).Times(1).Return(false, nil)
+				arg := sqlc.BlockOtherUserSessionsParams{Username: user.Username, KeptFamilyID: familyID}
+				store.EXPECT().BlockOtherUserSessions(gomock.Any(), gomock.Eq(arg)).Times(1).Return(int64(3), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp revokeOtherSessionsResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, int64(3), rsp.RevokedSessions)
+			},
+		},
+		{
+			// The access token of a revoked session stops working before it expires
+			name: "RevokedSession",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute,
+					token.WithSessionID(familyID))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().IsSessionFamilyBlocked(gomock.Any(), gomock.Eq(familyID)).Times(1).Return(true, nil)
+				store.EXPECT().BlockOtherUserSessions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			// Without its session the token would be revoked along with all the others
+			name: "TokenWithoutSession",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().BlockOtherUserSessions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "ReadOnlyToken",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute,
+					token.WithSessionID(familyID), token.WithScopes(token.ScopeRead))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().BlockOtherUserSessions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute,
+					token.WithSessionID(familyID))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().IsSessionFamilyBlocked(gomock.Any(), gomock.Eq(familyID)).Times(1).Return(false, nil)
+				store.EXPECT().BlockOtherUserSessions(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestSever(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/sessions/revoke_others", nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRenewThenRevokeSessionAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	familyID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestSever(t, store)
+
+	// A refresh token that was already renewed once, its session isn't the first of the family
+	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, util.DepositorRole, time.Hour,
+		token.WithType(token.TokenTypeRefresh), token.WithScopes(token.DefaultScopes...))
+	require.NoError(t, err)
+	session := sqlc.Session{
+		ID:           refreshPayload.ID,
+		FamilyID:     familyID,
+		Username:     user.Username,
+		RefreshToken: refreshToken,
+		ExpiresAt:    refreshPayload.ExpiredAt,
+	}
+	store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+	store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ interface{}, arg sqlc.RotateSessionTxParams) (sqlc.RotateSessionTxResult, error) {
+			return sqlc.RotateSessionTxResult{Session: sqlc.Session{
+				ID:        arg.NewSession.ID,
+				FamilyID:  arg.FamilyID,
+				ExpiresAt: arg.NewSession.ExpiresAt,
+			}}, nil
+		})
+
+	data, err := json.Marshal(map[string]string{"refresh_token": refreshToken})
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/api/v1/users/renew_access", bytes.NewReader(data))
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp renewAccessTokenResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Equal(t, familyID, rsp.SessionID)
+
+	// The session id the renewal returned is the one the session is revoked with
+	arg := sqlc.BlockUserSessionFamilyParams{FamilyID: familyID, Username: user.Username}
+	store.EXPECT().BlockUserSessionFamily(gomock.Any(), gomock.Eq(arg)).Times(1).Return(int64(2), nil)
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/sessions/%s", rsp.SessionID), nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/October-9th/simple-bank/authz"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/statement"
+	"github.com/October-9th/simple-bank/token"
+	"github.com/October-9th/simple-bank/validate"
+	"github.com/gin-gonic/gin"
+)
+
+type getAccountStatementRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type getAccountStatementQuery struct {
+	// From is included and To is excluded, both are RFC 3339 timestamps
+	From   time.Time `form:"from" binding:"required"`
+	To     time.Time `form:"to" binding:"required"`
+	Format string    `form:"format" binding:"omitempty,oneof=csv json ofx"`
+}
+
+// getAccountStatement returns the entries of an account in a period between its opening and closing balance,
+// as JSON by default or as CSV or OFX when asked for with the format parameter
+func (server *Server) getAccountStatement(ctx *gin.Context) {
+	req := &getAccountStatementRequest{}
+	if err := ctx.ShouldBindUri(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	query := &getAccountStatementQuery{}
+	if err := ctx.ShouldBindQuery(query); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if err := validate.ValidateStatementPeriod(query.From, query.To); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if query.Format == "" {
+		query.Format = statement.FormatJSON
+	}
+
+	account, err := server.store.GetAccount(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if err := authz.Authorize(authPayload, authz.ReadAccount, account.Owner); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.StatementTx(ctx, sqlc.StatementTxParams{
+		AccountID: req.ID,
+		From:      query.From,
+		To:        query.To,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	stmt, err := statement.New(result, query.From, query.To, time.Now())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	var body bytes.Buffer
+	if err := statement.Write(&body, query.Format, stmt); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.Header("Content-Disposition", `attachment; filename="`+statement.FileName(stmt, query.Format)+`"`)
+	ctx.Data(http.StatusOK, statement.ContentType(query.Format), body.Bytes())
+}
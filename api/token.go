@@ -2,11 +2,15 @@ package api
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/token"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type renewAccessTokenRequest struct {
@@ -14,10 +18,16 @@ type renewAccessTokenRequest struct {
 }
 
 type renewAccessTokenResponse struct {
-	AccessToken          string    `json:"access_token"`
-	AccessTokenExpiresAt time.Time `json:"access_token_expires_at"`
+	// SessionID is the id of the login session, the same one login returned, renewing doesn't change it
+	SessionID             uuid.UUID `json:"session_id"`
+	AccessToken           string    `json:"access_token"`
+	AccessTokenExpiresAt  time.Time `json:"access_token_expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
 }
 
+// renewAccessToken exchanges a refresh token for a new access token and a new refresh token.
+// The old refresh token can't be used again, replaying it blocks every session of its family
 func (server *Server) renewAccessToken(ctx *gin.Context) {
 	// Binding JSON raw data to loginUserRequest obj
 	req := &renewAccessTokenRequest{}
@@ -26,7 +36,7 @@ func (server *Server) renewAccessToken(ctx *gin.Context) {
 		return
 	}
 
-	refreshPayload, err := server.tokenMaker.VerifyToken(req.RefreshToken)
+	refreshPayload, err := server.tokenMaker.VerifyToken(req.RefreshToken, token.TokenTypeRefresh)
 
 	if err != nil {
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
@@ -67,26 +77,124 @@ func (server *Server) renewAccessToken(ctx *gin.Context) {
 		return
 	}
 
-	// If authorized, generate access token
-	accessToken, accessPayload, err := server.tokenMaker.CreateToken(refreshPayload.Username, server.config.AccessTokenDuration)
+	// Read the role again, it may have changed since the session was created
+	user, err := server.store.GetUser(ctx, session.Username)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
+	// If authorized, generate access token, with the scopes granted when the session started
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, server.config.AccessTokenDuration,
+		token.WithScopes(refreshPayload.Scopes...), token.WithSessionID(session.FamilyID))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
+	// and the refresh token that replaces the one being used
+	newRefreshToken, newRefreshPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, server.config.RefreshTokenDuration,
+		token.WithType(token.TokenTypeRefresh), token.WithScopes(refreshPayload.Scopes...))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
-	// Send access token and refresh token back to user
+
+	result, err := server.store.RotateSessionTx(ctx, sqlc.RotateSessionTxParams{
+		SessionID: session.ID,
+		FamilyID:  session.FamilyID,
+		NewSession: sqlc.CreateSessionParams{
+			ID:           newRefreshPayload.ID,
+			Username:     refreshPayload.Username,
+			RefreshToken: newRefreshToken,
+			UserAgent:    ctx.Request.UserAgent(),
+			ClientIp:     ctx.ClientIP(),
+			IsBlocked:    false,
+			ExpiresAt:    newRefreshPayload.ExpiredAt,
+		},
+	})
+	if err != nil {
+		if errors.Is(err, sqlc.ErrRefreshTokenReused) || errors.Is(err, sqlc.ErrSessionFamilyExpired) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Send access token and refresh token back to user, the refresh token works until its session expires
 	rsp := renewAccessTokenResponse{
-		AccessToken:          accessToken,
-		AccessTokenExpiresAt: accessPayload.ExpiredAt,
+		SessionID:             session.FamilyID,
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessPayload.ExpiredAt,
+		RefreshToken:          newRefreshToken,
+		RefreshTokenExpiresAt: result.Session.ExpiresAt,
 	}
 	ctx.JSON(http.StatusOK, rsp)
 }
+
+type logoutRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// logoutUser blocks the session of the given refresh token, and every session rotated from the same login,
+// so none of them can be renewed anymore
+func (server *Server) logoutUser(ctx *gin.Context) {
+	req := &logoutRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	refreshPayload, err := server.tokenMaker.VerifyToken(req.RefreshToken, token.TokenTypeRefresh)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if refreshPayload.Username != authPayload.Username {
+		err = fmt.Errorf("session doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	session, err := server.store.GetSession(ctx, refreshPayload.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if session.RefreshToken != req.RefreshToken {
+		err = fmt.Errorf("mismatched session token")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if err = server.store.BlockSessionFamily(ctx, session.FamilyID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Logged out successfully",
+	})
+}
+
+// logoutAllSessions blocks every session of the authenticated user
+func (server *Server) logoutAllSessions(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if err := server.store.BlockUserSessions(ctx, authPayload.Username); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Logged out of all sessions successfully",
+	})
+}
@@ -0,0 +1,346 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/October-9th/simple-bank/database/mock"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/token"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenewAccessTokenAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	familyExpiresAt := time.Now().Add(time.Minute).UTC().Truncate(time.Second)
+
+	testCases := []struct {
+		name string
+		// tokenType is the type of the token sent as refresh token, a refresh token when empty
+		tokenType     token.TokenType
+		buildStubs    func(store *mockdb.MockStore, refreshToken string, refreshPayload *token.Payload)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, refreshToken string)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, refreshPayload *token.Payload) {
+				session := sqlc.Session{
+					ID:           refreshPayload.ID,
+					FamilyID:     refreshPayload.ID,
+					Username:     user.Username,
+					RefreshToken: refreshToken,
+					ExpiresAt:    refreshPayload.ExpiredAt,
+				}
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(refreshPayload.ID)).Times(1).Return(session, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg sqlc.RotateSessionTxParams) (sqlc.RotateSessionTxResult, error) {
+						require.Equal(t, session.ID, arg.SessionID)
+						require.Equal(t, session.FamilyID, arg.FamilyID)
+						require.NotEqual(t, session.ID, arg.NewSession.ID)
+						// The session is capped to the expiry of the family
+						return sqlc.RotateSessionTxResult{Session: sqlc.Session{ID: arg.NewSession.ID, ExpiresAt: familyExpiresAt}}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, refreshToken string) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp renewAccessTokenResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.NotEmpty(t, rsp.AccessToken)
+				require.NotEmpty(t, rsp.RefreshToken)
+				require.NotEqual(t, refreshToken, rsp.RefreshToken)
+				require.True(t, familyExpiresAt.Equal(rsp.RefreshTokenExpiresAt))
+			},
+		},
+		{
+			name: "SessionFamilyExpired",
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, refreshPayload *token.Payload) {
+				session := sqlc.Session{
+					ID:           refreshPayload.ID,
+					FamilyID:     uuid.New(),
+					Username:     user.Username,
+					RefreshToken: refreshToken,
+					ExpiresAt:    refreshPayload.ExpiredAt,
+				}
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(refreshPayload.ID)).Times(1).Return(session, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sqlc.RotateSessionTxResult{}, sqlc.ErrSessionFamilyExpired)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, refreshToken string) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:      "AccessTokenAsRefreshToken",
+			tokenType: token.TokenTypeAccess,
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, refreshPayload *token.Payload) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, refreshToken string) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "ReusedRefreshToken",
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, refreshPayload *token.Payload) {
+				session := sqlc.Session{
+					ID:           refreshPayload.ID,
+					FamilyID:     refreshPayload.ID,
+					Username:     user.Username,
+					RefreshToken: refreshToken,
+					IsRotated:    true,
+					ExpiresAt:    refreshPayload.ExpiredAt,
+				}
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(refreshPayload.ID)).Times(1).Return(session, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sqlc.RotateSessionTxResult{}, sqlc.ErrRefreshTokenReused)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, refreshToken string) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "BlockedSession",
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, refreshPayload *token.Payload) {
+				session := sqlc.Session{
+					ID:           refreshPayload.ID,
+					FamilyID:     refreshPayload.ID,
+					Username:     user.Username,
+					RefreshToken: refreshToken,
+					IsBlocked:    true,
+					ExpiresAt:    refreshPayload.ExpiredAt,
+				}
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(refreshPayload.ID)).Times(1).Return(session, nil)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, refreshToken string) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestSever(t, store)
+
+			tokenType := tc.tokenType
+			if tokenType == "" {
+				tokenType = token.TokenTypeRefresh
+			}
+			refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, util.DepositorRole, time.Hour,
+				token.WithType(tokenType), token.WithScopes(token.DefaultScopes...))
+			require.NoError(t, err)
+			tc.buildStubs(store, refreshToken, refreshPayload)
+
+			recorder := httptest.NewRecorder()
+			data, err := json.Marshal(gin.H{"refresh_token": refreshToken})
+			require.NoError(t, err)
+
+			url := "/api/v1/users/renew_access"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder, refreshToken)
+		})
+	}
+}
+
+func TestLogoutUserAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	otherUser, _ := randomUser(t)
+
+	testCases := []struct {
+		name string
+		// refreshOwner is the user the refresh token sent in the body is created for
+		refreshOwner  string
+		setupAuth     func(t *testing.T, r *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore, refreshToken string, refreshPayload *token.Payload)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:         "OK",
+			refreshOwner: user.Username,
+			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, r, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, refreshPayload *token.Payload) {
+				session := sqlc.Session{
+					ID:           refreshPayload.ID,
+					FamilyID:     uuid.New(),
+					Username:     user.Username,
+					RefreshToken: refreshToken,
+					ExpiresAt:    refreshPayload.ExpiredAt,
+				}
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(refreshPayload.ID)).Times(1).Return(session, nil)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Eq(session.FamilyID)).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:         "OtherUserSession",
+			refreshOwner: otherUser.Username,
+			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, r, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, refreshPayload *token.Payload) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:         "SessionNotFound",
+			refreshOwner: user.Username,
+			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, r, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, refreshPayload *token.Payload) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(1).Return(sqlc.Session{}, sql.ErrNoRows)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:         "NoAuthorization",
+			refreshOwner: user.Username,
+			setupAuth:    func(t *testing.T, r *http.Request, tokenMaker token.Maker) {},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, refreshPayload *token.Payload) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestSever(t, store)
+
+			refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(tc.refreshOwner, util.DepositorRole, time.Hour,
+				token.WithType(token.TokenTypeRefresh))
+			require.NoError(t, err)
+			tc.buildStubs(store, refreshToken, refreshPayload)
+
+			recorder := httptest.NewRecorder()
+			data, err := json.Marshal(gin.H{"refresh_token": refreshToken})
+			require.NoError(t, err)
+
+			url := "/api/v1/users/logout"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestLogoutAllSessionsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, r *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, r, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().BlockUserSessions(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InternalServerError",
+			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, r, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().BlockUserSessions(gomock.Any(), gomock.Any()).Times(1).Return(sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().BlockUserSessions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestSever(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := "/api/v1/users/logout_all"
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -6,16 +6,44 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/October-9th/simple-bank/authz"
 	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/money"
 	"github.com/October-9th/simple-bank/token"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/October-9th/simple-bank/validate"
 	"github.com/gin-gonic/gin"
 )
 
+// idempotencyKeyHeader lets clients retry a transfer without the risk of sending the money twice
+const idempotencyKeyHeader = "Idempotency-Key"
+
 type transferRequest struct {
 	FromAccountID int64  `json:"from_account_id" binding:"required,min=1"`
-	ToAccountID   int64  `json:"to_account_id" binding:"required,min=1"`
-	Amount        int32  `json:"amount" binding:"required,gt=0"`
+	ToAccountID   int64  `json:"to_account_id" binding:"required,min=1,nefield=FromAccountID"`
+	Amount        int64  `json:"amount" binding:"required,gt=0"` // in minor units of the currency, e.g. cents for USD
 	Currency      string `json:"currency" binding:"required,currency"`
+
+	// Optional details kept with the transfer, checked by validateTransferDetails
+	Memo              string            `json:"memo"`
+	ExternalReference string            `json:"external_reference"`
+	Metadata          map[string]string `json:"metadata"`
+}
+
+// validateTransferDetails checks the free-form fields of a transfer request
+func validateTransferDetails(req *transferRequest) error {
+	if err := validate.ValidateMemo(req.Memo); err != nil {
+		return fmt.Errorf("memo %w", err)
+	}
+	if req.ExternalReference != "" {
+		if err := validate.ValidateExternalReference(req.ExternalReference); err != nil {
+			return fmt.Errorf("external_reference %w", err)
+		}
+	}
+	if err := validate.ValidateMetadata(req.Metadata); err != nil {
+		return fmt.Errorf("metadata %w", err)
+	}
+	return nil
 }
 
 func (server *Server) createTransfer(ctx *gin.Context) {
@@ -26,20 +54,38 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 		return
 	}
 
-	fromAccount, valid := server.validAccount(ctx, req.FromAccountID, req.Currency)
+	if err := validateTransferDetails(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	idempotencyKey := ctx.GetHeader(idempotencyKeyHeader)
+	if idempotencyKey != "" {
+		if err := validate.ValidateIdempotencyKey(idempotencyKey); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("%s header %v", idempotencyKeyHeader, err)))
+			return
+		}
+	}
+
+	fromAccount, valid := server.validAccount(ctx, req.FromAccountID)
 	if !valid {
 		err := errors.New("account is not valid")
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
+	// The amount is in the currency of the source account, the destination may use another one
+	if fromAccount.Currency != req.Currency {
+		err := fmt.Errorf("account [%d] currency mismatch: %s vs %s", fromAccount.ID, fromAccount.Currency, req.Currency)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	if fromAccount.Owner != authPayload.Username {
-		err := errors.New("from account doesn't belong to the authenticated user")
+	if err := authz.Authorize(authPayload, authz.TransferFrom, fromAccount.Owner); err != nil {
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
-	_, valid = server.validAccount(ctx, req.ToAccountID, req.Currency)
+	_, valid = server.validAccount(ctx, req.ToAccountID)
 
 	if !valid {
 		err := errors.New("account is not valid")
@@ -47,14 +93,40 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 		return
 	}
 
+	amount, err := money.New(req.Amount, req.Currency)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
 	arg := sqlc.TransferTxParams{
-		FromAccountID: req.FromAccountID,
-		ToAccountID:   req.ToAccountID,
-		Amount:        int64(req.Amount),
+		FromAccountID:     req.FromAccountID,
+		ToAccountID:       req.ToAccountID,
+		Amount:            amount,
+		Memo:              req.Memo,
+		ExternalReference: req.ExternalReference,
+		Metadata:          req.Metadata,
+	}
+	if idempotencyKey != "" {
+		arg.IdempotencyKey = idempotencyKey
+		arg.Username = authPayload.Username
 	}
 
 	result, err := server.store.TransferTx(ctx, arg)
 	if err != nil {
+		if errors.Is(err, sqlc.ErrInsufficientFunds) || errors.Is(err, sqlc.ErrNoExchangeRate) || errors.Is(err, money.ErrOverflow) ||
+			errors.Is(err, money.ErrCurrencyMismatch) || errors.Is(err, sqlc.ErrSameAccount) || errors.Is(err, sqlc.ErrAmountTooSmallToConvert) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+		if errors.Is(err, sqlc.ErrAccountClosed) || errors.Is(err, sqlc.ErrAccountFrozen) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		if errors.Is(err, sqlc.ErrIdempotencyKeyReused) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
@@ -63,8 +135,8 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 }
 
 // Function to check if an account with a specific ID really exists,
-// and its currency matches the input currency
-func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency string) (sqlc.Account, bool) {
+// and it is active so money can move in or out of it
+func (server *Server) validAccount(ctx *gin.Context, accountID int64) (sqlc.Account, bool) {
 	account, err := server.store.GetAccount(ctx, accountID)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -72,12 +144,12 @@ func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency s
 			return account, false
 		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return account, false
 	}
-	if account.Currency != currency {
-		err := fmt.Errorf("account [%d] currency mismatch: %s vs %s", accountID, account.Currency, currency)
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+	if account.Status != util.AccountActive {
+		err := fmt.Errorf("account [%d] is %s", accountID, account.Status)
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
 		return account, false
 	}
-
 	return account, true
 }
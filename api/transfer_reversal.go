@@ -0,0 +1,82 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/October-9th/simple-bank/authz"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/money"
+	"github.com/October-9th/simple-bank/token"
+	"github.com/gin-gonic/gin"
+)
+
+type reverseTransferURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type reverseTransferRequest struct {
+	// Amount is in minor units of the currency of the transfer's destination, leave it out to reverse whatever is left
+	Amount int64 `json:"amount" binding:"min=0"`
+}
+
+// reverseTransfer gives back all or part of a transfer. Only the recipient of the transfer or an admin may do it
+func (server *Server) reverseTransfer(ctx *gin.Context) {
+	uri := &reverseTransferURI{}
+	if err := ctx.ShouldBindUri(uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// The body is optional, an empty one reverses the whole transfer
+	req := &reverseTransferRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	transfer, err := server.store.GetTransfer(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	recipient, err := server.store.GetAccount(ctx, transfer.ToAccountID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if err := authz.Authorize(authPayload, authz.ReverseTransfer, recipient.Owner); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.ReverseTransferTx(ctx, sqlc.ReverseTransferTxParams{
+		TransferID: transfer.ID,
+		Amount:     req.Amount,
+	})
+	if err != nil {
+		if errors.Is(err, sqlc.ErrReversalExceedsTransfer) || errors.Is(err, sqlc.ErrTransferReversed) ||
+			errors.Is(err, sqlc.ErrReverseReversal) || errors.Is(err, sqlc.ErrInsufficientFunds) ||
+			errors.Is(err, sqlc.ErrInvalidAmount) || errors.Is(err, money.ErrOverflow) || errors.Is(err, sqlc.ErrSameAccount) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+		if errors.Is(err, sqlc.ErrAccountClosed) || errors.Is(err, sqlc.ErrAccountFrozen) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/October-9th/simple-bank/cursor"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/token"
+	"github.com/October-9th/simple-bank/validate"
+	"github.com/gin-gonic/gin"
+)
+
+type searchTransfersRequest struct {
+	// Every filter is optional. Amounts and currency are those of the side of the transfer the user owns,
+	// from is included and to is excluded, both are RFC 3339 timestamps
+	AccountID             int64     `form:"account_id" binding:"omitempty,min=1"`
+	Direction             string    `form:"direction" binding:"omitempty,oneof=sent received"`
+	CounterpartyAccountID int64     `form:"counterparty_account_id" binding:"omitempty,min=1"`
+	MinAmount             int64     `form:"min_amount" binding:"omitempty,min=1"`
+	MaxAmount             int64     `form:"max_amount" binding:"omitempty,min=1"`
+	From                  time.Time `form:"from"`
+	To                    time.Time `form:"to"`
+	Currency              string    `form:"currency" binding:"omitempty,currency"`
+	Memo                  string    `form:"memo"`
+	PageSize              int32     `form:"page_size" binding:"required,min=5,max=10"`
+	// Cursor is the next_cursor of the previous page, left out for the first page
+	Cursor string `form:"cursor"`
+}
+
+type searchTransfersResponse struct {
+	Transfers []sqlc.Transfer `json:"transfers"`
+	// NextCursor is empty on the last page
+	NextCursor string `json:"next_cursor"`
+}
+
+// searchTransfers lists the transfers sent or received by the accounts of the authenticated user, newest first.
+// Only the user's own accounts are searched whatever the role, an account of someone else simply matches nothing
+func (server *Server) searchTransfers(ctx *gin.Context) {
+	req := &searchTransfersRequest{}
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if err := validate.ValidateAmountRange(req.MinAmount, req.MaxAmount); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("amount range %w", err)))
+		return
+	}
+	if err := validate.ValidateTimeRange(req.From, req.To); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("period %w", err)))
+		return
+	}
+	if err := validate.ValidateMemo(req.Memo); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("memo %w", err)))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := sqlc.SearchTransfersParams{
+		Owner: authPayload.Username,
+		// One more row than the page tells whether there is a next page
+		LimitCount: req.PageSize + 1,
+	}
+	if req.Direction != "" {
+		arg.Direction = sql.NullString{String: req.Direction, Valid: true}
+	}
+	if req.AccountID != 0 {
+		arg.AccountID = sql.NullInt64{Int64: req.AccountID, Valid: true}
+	}
+	if req.CounterpartyAccountID != 0 {
+		arg.CounterpartyAccountID = sql.NullInt64{Int64: req.CounterpartyAccountID, Valid: true}
+	}
+	if req.MinAmount != 0 {
+		arg.MinAmount = sql.NullInt64{Int64: req.MinAmount, Valid: true}
+	}
+	if req.MaxAmount != 0 {
+		arg.MaxAmount = sql.NullInt64{Int64: req.MaxAmount, Valid: true}
+	}
+	if !req.From.IsZero() {
+		arg.FromTime = sql.NullTime{Time: req.From, Valid: true}
+	}
+	if !req.To.IsZero() {
+		arg.ToTime = sql.NullTime{Time: req.To, Valid: true}
+	}
+	if req.Currency != "" {
+		arg.Currency = sql.NullString{String: req.Currency, Valid: true}
+	}
+	if req.Memo != "" {
+		arg.Memo = sql.NullString{String: sqlc.ContainsPattern(req.Memo), Valid: true}
+	}
+	if req.Cursor != "" {
+		after, err := cursor.Decode(req.Cursor)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.CursorCreatedAt = sql.NullTime{Time: after.CreatedAt, Valid: true}
+		arg.CursorID = sql.NullInt64{Int64: after.ID, Valid: true}
+	}
+
+	transfers, err := server.store.SearchTransfers(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := searchTransfersResponse{Transfers: transfers}
+	if len(transfers) > int(req.PageSize) {
+		rsp.Transfers = transfers[:req.PageSize]
+		last := rsp.Transfers[len(rsp.Transfers)-1]
+		rsp.NextCursor = cursor.Encode(cursor.Cursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
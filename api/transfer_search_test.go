@@ -0,0 +1,192 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/October-9th/simple-bank/cursor"
+	mockdb "github.com/October-9th/simple-bank/database/mock"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/token"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchTransfersAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	transfers := make([]sqlc.Transfer, 6)
+	for i := range transfers {
+		transfers[i] = sqlc.Transfer{
+			ID:            int64(100 - i),
+			FromAccountID: account.ID,
+			ToAccountID:   util.RandomInt(1, 1000),
+			Amount:        util.RandomMoney(),
+			ExchangeRate:  "1",
+			CreatedAt:     now.Add(-time.Duration(i) * time.Minute),
+		}
+	}
+	after := cursor.Cursor{CreatedAt: transfers[4].CreatedAt, ID: transfers[4].ID}
+
+	testCases := []struct {
+		name          string
+		query         url.Values
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "FirstPage",
+			query: url.Values{
+				"account_id": {"42"},
+				"direction":  {"sent"},
+				"min_amount": {"10"},
+				"memo":       {"rent"},
+				"page_size":  {"5"},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := sqlc.SearchTransfersParams{
+					Owner:      user.Username,
+					Direction:  sql.NullString{String: "sent", Valid: true},
+					AccountID:  sql.NullInt64{Int64: 42, Valid: true},
+					MinAmount:  sql.NullInt64{Int64: 10, Valid: true},
+					Memo:       sql.NullString{String: "%rent%", Valid: true},
+					LimitCount: 6,
+				}
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Eq(arg)).Times(1).Return(transfers, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp searchTransfersResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp.Transfers, 5)
+
+				next, err := cursor.Decode(rsp.NextCursor)
+				require.NoError(t, err)
+				require.Equal(t, transfers[4].ID, next.ID)
+				require.True(t, transfers[4].CreatedAt.Equal(next.CreatedAt))
+			},
+		},
+		{
+			name:  "LastPage",
+			query: url.Values{"page_size": {"5"}, "cursor": {cursor.Encode(after)}},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := sqlc.SearchTransfersParams{
+					Owner:           user.Username,
+					CursorCreatedAt: sql.NullTime{Time: after.CreatedAt, Valid: true},
+					CursorID:        sql.NullInt64{Int64: after.ID, Valid: true},
+					LimitCount:      6,
+				}
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Eq(arg)).Times(1).Return(transfers[5:], nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp searchTransfersResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp.Transfers, 1)
+				require.Empty(t, rsp.NextCursor)
+			},
+		},
+		{
+			name:  "InvalidCursor",
+			query: url.Values{"page_size": {"5"}, "cursor": {"not-a-cursor"}},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidDirection",
+			query: url.Values{"page_size": {"5"}, "direction": {"both"}},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidAmountRange",
+			query: url.Values{"page_size": {"5"}, "min_amount": {"100"}, "max_amount": {"10"}},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "NoAuthorization",
+			query: url.Values{"page_size": {"5"}},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:  "InternalError",
+			query: url.Values{"page_size": {"5"}},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestSever(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/v1/transfers?"+tc.query.Encode(), nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	mockdb "github.com/October-9th/simple-bank/database/mock"
 	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/money"
 	"github.com/October-9th/simple-bank/token"
 	"github.com/October-9th/simple-bank/util"
 	"github.com/gin-gonic/gin"
@@ -34,12 +35,22 @@ func TestCreateTransfer(t *testing.T) {
 	account2.Currency = util.USD
 	account3.Currency = util.CAD
 
+	closedAccount := randomAccount(user2.Username)
+	closedAccount.Currency = util.USD
+	closedAccount.Status = util.AccountClosed
+	closedAccount.ClosedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	frozenAccount := randomAccount(user1.Username)
+	frozenAccount.Currency = util.USD
+	frozenAccount.Status = util.AccountFrozen
+
 	testCases := []struct {
-		name          string
-		body          gin.H
-		setupAuth     func(t *testing.T, r *http.Request, tokenMaker token.Maker)
-		buildStubs    func(store *mockdb.MockStore)
-		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+		name           string
+		body           gin.H
+		idempotencyKey string
+		setupAuth      func(t *testing.T, r *http.Request, tokenMaker token.Maker)
+		buildStubs     func(store *mockdb.MockStore)
+		checkResponse  func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
 			name: "Ok",
@@ -50,7 +61,7 @@ func TestCreateTransfer(t *testing.T) {
 				"currency":        util.USD,
 			},
 			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, r, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+				addAuthorization(t, r, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
@@ -59,7 +70,7 @@ func TestCreateTransfer(t *testing.T) {
 				arg := sqlc.TransferTxParams{
 					FromAccountID: account1.ID,
 					ToAccountID:   account2.ID,
-					Amount:        amount,
+					Amount:        money.Amount{Minor: amount, Currency: account1.Currency},
 				}
 
 				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1)
@@ -68,6 +79,79 @@ func TestCreateTransfer(t *testing.T) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
+		{
+			name: "WithDetails",
+			body: gin.H{
+				"from_account_id":    account1.ID,
+				"to_account_id":      account2.ID,
+				"amount":             amount,
+				"currency":           util.USD,
+				"memo":               "rent for March",
+				"external_reference": "INV-2026/03",
+				"metadata":           gin.H{"category": "rent"},
+			},
+			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, r, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+
+				arg := sqlc.TransferTxParams{
+					FromAccountID:     account1.ID,
+					ToAccountID:       account2.ID,
+					Amount:            money.Amount{Minor: amount, Currency: account1.Currency},
+					Memo:              "rent for March",
+					ExternalReference: "INV-2026/03",
+					Metadata:          map[string]string{"category": "rent"},
+				}
+
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "MemoTooLong",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          amount,
+				"currency":        util.USD,
+				"memo":            util.RandomString(141),
+			},
+			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, r, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidMetadata",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          amount,
+				"currency":        util.USD,
+				"metadata":        gin.H{"bad key": "value\n"},
+			},
+			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, r, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name: "UnauthorizedUser",
 			body: gin.H{
@@ -77,7 +161,7 @@ func TestCreateTransfer(t *testing.T) {
 				"currency":        util.USD,
 			},
 			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, r, tokenMaker, authorizationTypeBearer, user2.Username, time.Minute)
+				addAuthorization(t, r, tokenMaker, authorizationTypeBearer, user2.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
@@ -110,7 +194,7 @@ func TestCreateTransfer(t *testing.T) {
 			name: "BadRequest",
 			body: gin.H{},
 			setupAuth: func(t *testing.T, r *http.Request, token token.Maker) {
-				addAuthorization(t, r, token, authorizationTypeBearer, user1.Username, time.Minute)
+				addAuthorization(t, r, token, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
@@ -129,7 +213,26 @@ func TestCreateTransfer(t *testing.T) {
 				"currency":        "TEST",
 			},
 			setupAuth: func(t *testing.T, r *http.Request, token token.Maker) {
-				addAuthorization(t, r, token, authorizationTypeBearer, user1.Username, time.Minute)
+				addAuthorization(t, r, token, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "SameAccount",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account1.ID,
+				"amount":          amount,
+				"currency":        util.USD,
+			},
+			setupAuth: func(t *testing.T, r *http.Request, token token.Maker) {
+				addAuthorization(t, r, token, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
@@ -148,7 +251,7 @@ func TestCreateTransfer(t *testing.T) {
 				"currency":        util.USD,
 			},
 			setupAuth: func(t *testing.T, r *http.Request, token token.Maker) {
-				addAuthorization(t, r, token, authorizationTypeBearer, user1.Username, time.Minute)
+				addAuthorization(t, r, token, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
@@ -167,7 +270,7 @@ func TestCreateTransfer(t *testing.T) {
 				"currency":        util.USD,
 			},
 			setupAuth: func(t *testing.T, r *http.Request, token token.Maker) {
-				addAuthorization(t, r, token, authorizationTypeBearer, user1.Username, time.Minute)
+				addAuthorization(t, r, token, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(sqlc.Account{}, sql.ErrNoRows)
@@ -186,7 +289,7 @@ func TestCreateTransfer(t *testing.T) {
 				"currency":        util.USD,
 			},
 			setupAuth: func(t *testing.T, r *http.Request, token token.Maker) {
-				addAuthorization(t, r, token, authorizationTypeBearer, user1.Username, time.Minute)
+				addAuthorization(t, r, token, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
@@ -200,17 +303,17 @@ func TestCreateTransfer(t *testing.T) {
 		{
 			name: "FromAccountCurrencyMismatch",
 			body: gin.H{
-				"from_account_id": account3.ID,
+				"from_account_id": account1.ID,
 				"to_account_id":   account2.ID,
 				"amount":          amount,
 				"currency":        util.CAD,
 			},
 			setupAuth: func(t *testing.T, r *http.Request, token token.Maker) {
-				addAuthorization(t, r, token, authorizationTypeBearer, user3.Username, time.Minute)
+				addAuthorization(t, r, token, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account3.ID)).Times(1).Return(account3, nil)
-				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(0)
 				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -218,7 +321,7 @@ func TestCreateTransfer(t *testing.T) {
 			},
 		},
 		{
-			name: "ToAccountCurrencyMismatch",
+			name: "CrossCurrency",
 			body: gin.H{
 				"from_account_id": account1.ID,
 				"to_account_id":   account3.ID,
@@ -226,15 +329,99 @@ func TestCreateTransfer(t *testing.T) {
 				"currency":        util.USD,
 			},
 			setupAuth: func(t *testing.T, r *http.Request, token token.Maker) {
-				addAuthorization(t, r, token, authorizationTypeBearer, user1.Username, time.Minute)
+				addAuthorization(t, r, token, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
+				arg := sqlc.TransferTxParams{
+					FromAccountID: account1.ID,
+					ToAccountID:   account3.ID,
+					Amount:        money.Amount{Minor: amount, Currency: account1.Currency},
+				}
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account3.ID)).Times(1).Return(account3, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NoExchangeRate",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account3.ID,
+				"amount":          amount,
+				"currency":        util.USD,
+			},
+			setupAuth: func(t *testing.T, r *http.Request, token token.Maker) {
+				addAuthorization(t, r, token, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account3.ID)).Times(1).Return(account3, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(sqlc.TransferTxResult{}, sqlc.ErrNoExchangeRate)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "AmountTooSmallToConvert",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account3.ID,
+				"amount":          amount,
+				"currency":        util.USD,
+			},
+			setupAuth: func(t *testing.T, r *http.Request, token token.Maker) {
+				addAuthorization(t, r, token, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account3.ID)).Times(1).Return(account3, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(sqlc.TransferTxResult{}, sqlc.ErrAmountTooSmallToConvert)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "ToAccountClosed",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   closedAccount.ID,
+				"amount":          amount,
+				"currency":        util.USD,
+			},
+			setupAuth: func(t *testing.T, r *http.Request, token token.Maker) {
+				addAuthorization(t, r, token, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(closedAccount.ID)).Times(1).Return(closedAccount, nil)
 				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "FromAccountFrozen",
+			body: gin.H{
+				"from_account_id": frozenAccount.ID,
+				"to_account_id":   account2.ID,
+				"amount":          amount,
+				"currency":        util.USD,
+			},
+			setupAuth: func(t *testing.T, r *http.Request, token token.Maker) {
+				addAuthorization(t, r, token, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(frozenAccount.ID)).Times(1).Return(frozenAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
 			},
 		},
 		{
@@ -246,7 +433,7 @@ func TestCreateTransfer(t *testing.T) {
 				"currency":        util.USD,
 			},
 			setupAuth: func(t *testing.T, r *http.Request, token token.Maker) {
-				addAuthorization(t, r, token, authorizationTypeBearer, user1.Username, time.Minute)
+				addAuthorization(t, r, token, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(sqlc.Account{}, sql.ErrConnDone)
@@ -255,6 +442,96 @@ func TestCreateTransfer(t *testing.T) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
+		{
+			name: "InsufficientFunds",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          amount,
+				"currency":        util.USD,
+			},
+			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, r, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(sqlc.TransferTxResult{}, sqlc.ErrInsufficientFunds)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "IdempotencyKey",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          amount,
+				"currency":        util.USD,
+			},
+			idempotencyKey: "retry-key_1",
+			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, r, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+
+				arg := sqlc.TransferTxParams{
+					FromAccountID:  account1.ID,
+					ToAccountID:    account2.ID,
+					Amount:         money.Amount{Minor: amount, Currency: account1.Currency},
+					IdempotencyKey: "retry-key_1",
+					Username:       user1.Username,
+				}
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidIdempotencyKey",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          amount,
+				"currency":        util.USD,
+			},
+			idempotencyKey: "not a key!",
+			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, r, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "IdempotencyKeyReused",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          amount,
+				"currency":        util.USD,
+			},
+			idempotencyKey: "retry-key_1",
+			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, r, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(sqlc.TransferTxResult{}, sqlc.ErrIdempotencyKeyReused)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
 		{
 			name: "TransferTxError",
 			body: gin.H{
@@ -264,7 +541,7 @@ func TestCreateTransfer(t *testing.T) {
 				"currency":        util.USD,
 			},
 			setupAuth: func(t *testing.T, r *http.Request, token token.Maker) {
-				addAuthorization(t, r, token, authorizationTypeBearer, user1.Username, time.Minute)
+				addAuthorization(t, r, token, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
 			},
 
 			buildStubs: func(store *mockdb.MockStore) {
@@ -297,6 +574,9 @@ func TestCreateTransfer(t *testing.T) {
 			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
 			require.NoError(t, err)
 
+			if tc.idempotencyKey != "" {
+				request.Header.Set(idempotencyKeyHeader, tc.idempotencyKey)
+			}
 			tc.setupAuth(t, request, server.tokenMaker)
 			server.router.ServeHTTP(recorder, request)
 			tc.checkResponse(t, recorder)
@@ -5,7 +5,9 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/October-9th/simple-bank/authz"
 	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/token"
 	"github.com/October-9th/simple-bank/util"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -23,6 +25,7 @@ type userResponse struct {
 	Username          string    `json:"username"`
 	FullName          string    `json:"fullname"`
 	Email             string    `json:"email"`
+	Role              string    `json:"role"`
 	CreatedAt         time.Time `json:"created_at"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 }
@@ -33,6 +36,7 @@ func newUserResponse(user sqlc.User) userResponse {
 		Username:          user.Username,
 		FullName:          user.FullName,
 		Email:             user.Email,
+		Role:              user.Role,
 		CreatedAt:         user.CreatedAt,
 		PasswordChangedAt: user.PasswordChangedAt,
 	}
@@ -113,15 +117,17 @@ func (server *Server) loginUser(ctx *gin.Context) {
 		return
 	}
 
-	// If authorized, generate access token
-	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.AccessTokenDuration)
+	// If authorized, generate the refresh token, its id is the id of the session the login starts
+	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, server.config.RefreshTokenDuration,
+		token.WithType(token.TokenTypeRefresh), token.WithScopes(token.DefaultScopes...))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	// generate the refresh token
-	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.RefreshTokenDuration)
+	// then the access token of that session
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, server.config.AccessTokenDuration,
+		token.WithScopes(token.DefaultScopes...), token.WithSessionID(refreshPayload.ID))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
@@ -136,6 +142,8 @@ func (server *Server) loginUser(ctx *gin.Context) {
 		ClientIp:     ctx.ClientIP(),
 		IsBlocked:    false,
 		ExpiresAt:    refreshPayload.ExpiredAt,
+		// A login starts a new family, renewals add to it
+		FamilyID: refreshPayload.ID,
 	})
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
@@ -152,3 +160,47 @@ func (server *Server) loginUser(ctx *gin.Context) {
 	}
 	ctx.JSON(http.StatusOK, rsp)
 }
+
+type updateUserRoleUri struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+type updateUserRoleRequest struct {
+	Role string `json:"role" binding:"required,role"`
+}
+
+// updateUserRole lets an admin give a user another role. Tokens already issued keep the role they were created with,
+// the new one applies from the next login or renewal
+func (server *Server) updateUserRole(ctx *gin.Context) {
+	uri := &updateUserRoleUri{}
+	if err := ctx.ShouldBindUri(uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	req := &updateUserRoleRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if err := authz.Authorize(authPayload, authz.AssignRole, uri.Username); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.UpdateUserRole(ctx, sqlc.UpdateUserRoleParams{
+		Username: uri.Username,
+		Role:     req.Role,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
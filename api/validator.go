@@ -13,3 +13,10 @@ var validCurrency validator.Func = func(fieldLevel validator.FieldLevel) bool {
 	return false
 
 }
+
+var validRole validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if role, ok := fieldLevel.Field().Interface().(string); ok {
+		return util.IsSupportedRole(role)
+	}
+	return false
+}
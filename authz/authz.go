@@ -0,0 +1,92 @@
+// Package authz holds the authorization rules shared by the HTTP and the gRPC servers
+package authz
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/October-9th/simple-bank/token"
+	"github.com/October-9th/simple-bank/util"
+)
+
+// ErrPermissionDenied is returned when the role of the authenticated user doesn't allow an action
+var ErrPermissionDenied = errors.New("permission denied")
+
+// Action is something a user can do on a resource owned by some user
+type Action string
+
+const (
+	// ReadAccount covers reading an account, its entries and its transfers
+	ReadAccount Action = "read account"
+	// ManageAccount covers opening and closing an account
+	ManageAccount Action = "manage account"
+	// TransferFrom covers sending money out of an account
+	TransferFrom Action = "transfer from account"
+	// ReverseTransfer covers giving back money received through a transfer, checked against the recipient
+	ReverseTransfer Action = "reverse transfer"
+	// AdjustBalance covers changing a balance outside of a transfer
+	AdjustBalance Action = "adjust balance"
+	// FreezeAccount covers freezing and unfreezing an account
+	FreezeAccount Action = "freeze account"
+	// SetOverdraftLimit covers changing how far below zero the balance of an account may go
+	SetOverdraftLimit Action = "set overdraft limit"
+	// ManageExchangeRates covers loading exchange rates, which belong to no user
+	ManageExchangeRates Action = "manage exchange rates"
+	// Reconcile covers running the ledger reconciliation, reading its reports and verifying entry chains
+	Reconcile Action = "reconcile ledger"
+	// AssignRole covers changing the role of a user, checked against the user whose role changes
+	AssignRole Action = "assign role"
+)
+
+// scope tells on whose resources a role may perform an action
+type scope int
+
+const (
+	scopeNone scope = iota
+	scopeOwn
+	scopeAll
+)
+
+// rules maps each role to the actions it may perform.
+// An action missing from a role is denied
+var rules = map[string]map[Action]scope{
+	util.DepositorRole: {
+		ReadAccount:     scopeOwn,
+		ManageAccount:   scopeOwn,
+		TransferFrom:    scopeOwn,
+		ReverseTransfer: scopeOwn,
+	},
+	util.BankerRole: {
+		ReadAccount:       scopeAll,
+		ManageAccount:     scopeOwn,
+		TransferFrom:      scopeOwn,
+		ReverseTransfer:   scopeOwn,
+		SetOverdraftLimit: scopeAll,
+	},
+	util.AdminRole: {
+		ReadAccount:         scopeAll,
+		ManageAccount:       scopeAll,
+		TransferFrom:        scopeOwn,
+		ReverseTransfer:     scopeAll,
+		AdjustBalance:       scopeAll,
+		FreezeAccount:       scopeAll,
+		SetOverdraftLimit:   scopeAll,
+		ManageExchangeRates: scopeAll,
+		Reconcile:           scopeAll,
+		AssignRole:          scopeAll,
+	},
+}
+
+// Authorize checks that the authenticated user may perform action on a resource owned by owner
+func Authorize(payload *token.Payload, action Action, owner string) error {
+	switch rules[payload.Role][action] {
+	case scopeAll:
+		return nil
+	case scopeOwn:
+		if owner == payload.Username {
+			return nil
+		}
+		return fmt.Errorf("%w: %s of another user", ErrPermissionDenied, action)
+	}
+	return fmt.Errorf("%w: %s requires another role", ErrPermissionDenied, action)
+}
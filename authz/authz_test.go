@@ -0,0 +1,65 @@
+package authz
+
+import (
+	"testing"
+	"time"
+
+	"github.com/October-9th/simple-bank/token"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorize(t *testing.T) {
+	username := util.RandomOwner()
+	otherUser := util.RandomOwner()
+
+	testCases := []struct {
+		role    string
+		action  Action
+		owner   string
+		allowed bool
+	}{
+		{util.DepositorRole, ReadAccount, username, true},
+		{util.DepositorRole, ReadAccount, otherUser, false},
+		{util.DepositorRole, ManageAccount, otherUser, false},
+		{util.DepositorRole, TransferFrom, username, true},
+		{util.DepositorRole, AdjustBalance, username, false},
+		{util.BankerRole, ReadAccount, otherUser, true},
+		{util.BankerRole, ManageAccount, otherUser, false},
+		{util.BankerRole, TransferFrom, otherUser, false},
+		{util.BankerRole, AdjustBalance, otherUser, false},
+		{util.AdminRole, ManageAccount, otherUser, true},
+		{util.AdminRole, AdjustBalance, otherUser, true},
+		{util.AdminRole, FreezeAccount, otherUser, true},
+		{util.BankerRole, FreezeAccount, otherUser, false},
+		{util.DepositorRole, FreezeAccount, username, false},
+		{util.AdminRole, ManageExchangeRates, "", true},
+		{util.BankerRole, ManageExchangeRates, "", false},
+		{util.AdminRole, Reconcile, "", true},
+		{util.DepositorRole, ReverseTransfer, username, true},
+		{util.DepositorRole, ReverseTransfer, otherUser, false},
+		{util.BankerRole, ReverseTransfer, otherUser, false},
+		{util.AdminRole, ReverseTransfer, otherUser, true},
+		{util.BankerRole, Reconcile, "", false},
+		{util.AdminRole, TransferFrom, otherUser, false},
+		{util.AdminRole, AssignRole, otherUser, true},
+		{util.AdminRole, SetOverdraftLimit, otherUser, true},
+		{util.BankerRole, SetOverdraftLimit, otherUser, true},
+		{util.DepositorRole, SetOverdraftLimit, username, false},
+		{util.BankerRole, AssignRole, otherUser, false},
+		{util.DepositorRole, AssignRole, username, false},
+		{"unknown", ReadAccount, username, false},
+	}
+
+	for _, tc := range testCases {
+		payload, err := token.NewPayLoad(username, tc.role, time.Minute)
+		require.NoError(t, err)
+
+		err = Authorize(payload, tc.action, tc.owner)
+		if tc.allowed {
+			require.NoError(t, err, "%s %s", tc.role, tc.action)
+		} else {
+			require.ErrorIs(t, err, ErrPermissionDenied, "%s %s", tc.role, tc.action)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+// Package cursor encodes the position of the last row of a page as an opaque token, so lists ordered by
+// creation time can be paged with a keyset that stays stable while new rows are inserted
+package cursor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrInvalidCursor is returned for a token that wasn't produced by Encode
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is the (created_at, id) key of the last row of a page, the next page starts strictly after it
+type Cursor struct {
+	CreatedAt time.Time
+	ID        int64
+}
+
+// token is the encoded form, kept short since it travels in URLs
+type token struct {
+	CreatedAt time.Time `json:"t"`
+	ID        int64     `json:"i"`
+}
+
+// Encode returns the opaque token of the cursor, safe to use in a query string
+func Encode(c Cursor) string {
+	data, _ := json.Marshal(token{CreatedAt: c.CreatedAt.UTC(), ID: c.ID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode parses a token returned by Encode
+func Decode(value string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var t token
+	if err := json.Unmarshal(data, &t); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	if t.ID < 1 || t.CreatedAt.IsZero() {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return Cursor{CreatedAt: t.CreatedAt, ID: t.ID}, nil
+}
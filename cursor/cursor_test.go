@@ -0,0 +1,33 @@
+package cursor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	c := Cursor{CreatedAt: time.Date(2023, 5, 17, 10, 30, 0, 123456000, time.UTC), ID: 42}
+
+	value := Encode(c)
+	require.NotContains(t, value, "=")
+
+	decoded, err := Decode(value)
+	require.NoError(t, err)
+	require.True(t, c.CreatedAt.Equal(decoded.CreatedAt))
+	require.Equal(t, c.ID, decoded.ID)
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	for _, value := range []string{
+		"",
+		"not base64!",
+		Encode(Cursor{CreatedAt: time.Now()}),
+		Encode(Cursor{ID: 42}),
+		"eyJ0IjoxfQ", // {"t":1}
+	} {
+		_, err := Decode(value)
+		require.ErrorIs(t, err, ErrInvalidCursor, value)
+	}
+}
@@ -0,0 +1,5 @@
+ALTER TABLE "accounts" ADD COLUMN "overdraft_limit" bigint NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN "accounts"."overdraft_limit" IS 'how far below zero the balance may go, must not be negative';
+
+ALTER TABLE "accounts" ADD CONSTRAINT "overdraft_limit_non_negative" CHECK ("overdraft_limit" >= 0);
@@ -0,0 +1,14 @@
+CREATE TABLE "idempotency_keys" (
+  "username" varchar NOT NULL,
+  "key" varchar NOT NULL,
+  "request_hash" varchar NOT NULL,
+  "response" jsonb NOT NULL DEFAULT '{}',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("username", "key")
+);
+
+COMMENT ON COLUMN "idempotency_keys"."request_hash" IS 'sha256 of the request the key was first used with';
+
+COMMENT ON COLUMN "idempotency_keys"."response" IS 'serialized result returned to replays of the same request';
+
+ALTER TABLE "idempotency_keys" ADD FOREIGN KEY ("username") REFERENCES "users" ("username") ON DELETE CASCADE ON UPDATE CASCADE;
@@ -0,0 +1,3 @@
+ALTER TABLE IF EXISTS "sessions" DROP COLUMN IF EXISTS "is_rotated";
+
+ALTER TABLE IF EXISTS "sessions" DROP COLUMN IF EXISTS "family_id";
@@ -0,0 +1,14 @@
+ALTER TABLE "sessions" ADD COLUMN "family_id" uuid;
+
+-- Every existing session starts its own family
+UPDATE "sessions" SET "family_id" = "id";
+
+ALTER TABLE "sessions" ALTER COLUMN "family_id" SET NOT NULL;
+
+ALTER TABLE "sessions" ADD COLUMN "is_rotated" BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX ON "sessions" ("family_id");
+
+COMMENT ON COLUMN "sessions"."family_id" IS 'id of the login session every rotated session descends from';
+
+COMMENT ON COLUMN "sessions"."is_rotated" IS 'the refresh token was already exchanged for a new one';
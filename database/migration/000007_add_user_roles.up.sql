@@ -0,0 +1,5 @@
+ALTER TABLE "users" ADD COLUMN "role" varchar NOT NULL DEFAULT 'depositor';
+
+ALTER TABLE "users" ADD CONSTRAINT "user_role_valid" CHECK ("role" IN ('depositor', 'banker', 'admin'));
+
+COMMENT ON COLUMN "users"."role" IS 'depositor, banker or admin';
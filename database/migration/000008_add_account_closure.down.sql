@@ -0,0 +1,7 @@
+ALTER TABLE IF EXISTS "entries" DROP CONSTRAINT IF EXISTS "entries_account_id_fkey";
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+DROP TABLE IF EXISTS "balance_adjustments";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "closed_at";
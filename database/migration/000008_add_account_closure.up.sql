@@ -0,0 +1,30 @@
+ALTER TABLE "accounts" ADD COLUMN "closed_at" timestamptz;
+
+COMMENT ON COLUMN "accounts"."closed_at" IS 'set once the account is closed, closed accounts keep their history but take no more transfers';
+
+CREATE TABLE "balance_adjustments" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "entry_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "reason" varchar NOT NULL,
+  "adjusted_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "balance_adjustments" ("account_id");
+
+COMMENT ON COLUMN "balance_adjustments"."amount" IS 'can be positive or negative, never zero';
+
+ALTER TABLE "balance_adjustments" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "balance_adjustments" ADD FOREIGN KEY ("entry_id") REFERENCES "entries" ("id");
+
+ALTER TABLE "balance_adjustments" ADD FOREIGN KEY ("adjusted_by") REFERENCES "users" ("username") ON UPDATE CASCADE;
+
+ALTER TABLE "balance_adjustments" ADD CONSTRAINT "adjustment_amount_non_zero" CHECK ("amount" <> 0);
+
+-- Entries are the account history, deleting an account must no longer wipe them
+ALTER TABLE "entries" DROP CONSTRAINT IF EXISTS "entries_account_id_fkey";
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
@@ -0,0 +1,3 @@
+ALTER TABLE IF EXISTS "accounts" DROP CONSTRAINT IF EXISTS "account_status_valid";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "status";
@@ -0,0 +1,7 @@
+ALTER TABLE "accounts" ADD COLUMN "status" varchar NOT NULL DEFAULT 'active';
+
+COMMENT ON COLUMN "accounts"."status" IS 'active, frozen or closed, only active accounts send or receive money';
+
+ALTER TABLE "accounts" ADD CONSTRAINT "account_status_valid" CHECK ("status" IN ('active', 'frozen', 'closed'));
+
+UPDATE "accounts" SET "status" = 'closed' WHERE "closed_at" IS NOT NULL;
@@ -0,0 +1,30 @@
+CREATE TABLE "exchange_rates" (
+  "id" bigserial PRIMARY KEY,
+  "base_currency" varchar NOT NULL,
+  "quote_currency" varchar NOT NULL,
+  "rate" numeric(20,10) NOT NULL,
+  "effective_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "exchange_rates" ("base_currency", "quote_currency", "effective_at");
+
+COMMENT ON COLUMN "exchange_rates"."rate" IS 'units of quote currency for one unit of base currency, must be positive';
+
+COMMENT ON COLUMN "exchange_rates"."effective_at" IS 'the rate applies from this time until a later rate of the same pair takes over';
+
+ALTER TABLE "exchange_rates" ADD CONSTRAINT "exchange_rate_positive" CHECK ("rate" > 0);
+
+ALTER TABLE "transfer" ADD COLUMN "to_amount" bigint;
+
+UPDATE "transfer" SET "to_amount" = "amount";
+
+ALTER TABLE "transfer" ALTER COLUMN "to_amount" SET NOT NULL;
+
+ALTER TABLE "transfer" ADD COLUMN "exchange_rate" numeric(20,10) NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN "transfer"."amount" IS 'must be positive, in the currency of the source account';
+
+COMMENT ON COLUMN "transfer"."to_amount" IS 'must be positive, in the currency of the destination account';
+
+COMMENT ON COLUMN "transfer"."exchange_rate" IS 'the rate used to convert amount into to_amount, 1 when both accounts share a currency';
@@ -0,0 +1,7 @@
+ALTER TABLE IF EXISTS "exchange_rates" DROP CONSTRAINT IF EXISTS "exchange_rates_quote_currency_fkey";
+
+ALTER TABLE IF EXISTS "exchange_rates" DROP CONSTRAINT IF EXISTS "exchange_rates_base_currency_fkey";
+
+ALTER TABLE IF EXISTS "accounts" DROP CONSTRAINT IF EXISTS "accounts_currency_fkey";
+
+DROP TABLE IF EXISTS "currencies";
@@ -0,0 +1,5 @@
+ALTER TABLE IF EXISTS "entries" DROP CONSTRAINT IF EXISTS "entry_amount_non_zero";
+
+ALTER TABLE IF EXISTS "transfer" DROP CONSTRAINT IF EXISTS "transfer_to_amount_positive";
+
+ALTER TABLE IF EXISTS "transfer" DROP CONSTRAINT IF EXISTS "transfer_amount_positive";
@@ -0,0 +1,5 @@
+ALTER TABLE "transfer" ADD CONSTRAINT "transfer_amount_positive" CHECK ("amount" > 0);
+
+ALTER TABLE "transfer" ADD CONSTRAINT "transfer_to_amount_positive" CHECK ("to_amount" > 0);
+
+ALTER TABLE "entries" ADD CONSTRAINT "entry_amount_non_zero" CHECK ("amount" <> 0);
@@ -0,0 +1,20 @@
+ALTER TABLE "entries" ADD COLUMN "transfer_id" bigint;
+
+COMMENT ON COLUMN "entries"."transfer_id" IS 'the transfer this entry belongs to, null for balance adjustments';
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfer" ("id");
+
+CREATE INDEX ON "entries" ("transfer_id");
+
+CREATE INDEX ON "entries" ("account_id", "created_at");
+
+-- TransferTx writes a transfer and both of its entries in one transaction, so they share created_at
+UPDATE "entries" e
+SET "transfer_id" = t."id"
+FROM "transfer" t
+WHERE e."transfer_id" IS NULL
+  AND e."created_at" = t."created_at"
+  AND (
+    (e."account_id" = t."from_account_id" AND e."amount" = -t."amount") OR
+    (e."account_id" = t."to_account_id" AND e."amount" = t."to_amount")
+  );
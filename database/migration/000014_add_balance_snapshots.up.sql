@@ -0,0 +1,16 @@
+CREATE TABLE "balance_snapshots" (
+  "account_id" bigint NOT NULL,
+  "snapshot_date" date NOT NULL,
+  "cutoff" timestamptz NOT NULL,
+  "balance" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "snapshot_date")
+);
+
+CREATE INDEX ON "balance_snapshots" ("account_id", "cutoff");
+
+COMMENT ON COLUMN "balance_snapshots"."snapshot_date" IS 'the UTC day the snapshot closes';
+
+COMMENT ON COLUMN "balance_snapshots"."cutoff" IS 'end of snapshot_date, balance is the sum of every entry created before it';
+
+ALTER TABLE "balance_snapshots" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
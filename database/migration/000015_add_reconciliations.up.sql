@@ -0,0 +1,34 @@
+CREATE TABLE "reconciliation_runs" (
+  "id" bigserial PRIMARY KEY,
+  "started_by" varchar NOT NULL,
+  "accounts_checked" bigint NOT NULL,
+  "transfers_checked" bigint NOT NULL,
+  "entries_checked" bigint NOT NULL,
+  "discrepancy_count" bigint NOT NULL,
+  "started_at" timestamptz NOT NULL,
+  "finished_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "reconciliation_discrepancies" (
+  "id" bigserial PRIMARY KEY,
+  "run_id" bigint NOT NULL,
+  "kind" varchar NOT NULL,
+  "account_id" bigint,
+  "transfer_id" bigint,
+  "entry_id" bigint,
+  "expected" bigint NOT NULL,
+  "actual" bigint NOT NULL,
+  "detail" varchar NOT NULL
+);
+
+CREATE INDEX ON "reconciliation_discrepancies" ("run_id");
+
+COMMENT ON COLUMN "reconciliation_runs"."started_by" IS 'username of the admin who ran it, or the name of the job';
+
+COMMENT ON COLUMN "reconciliation_discrepancies"."kind" IS 'balance_mismatch, transfer_entries_mismatch, orphan_entry, adjustment_mismatch or snapshot_mismatch';
+
+COMMENT ON COLUMN "reconciliation_discrepancies"."expected" IS 'the value the ledger implies, e.g. the sum of the entries';
+
+COMMENT ON COLUMN "reconciliation_discrepancies"."actual" IS 'the value found, e.g. accounts.balance';
+
+ALTER TABLE "reconciliation_discrepancies" ADD FOREIGN KEY ("run_id") REFERENCES "reconciliation_runs" ("id");
@@ -0,0 +1,11 @@
+ALTER TABLE "entries" ADD COLUMN "prev_hash" bytea;
+
+ALTER TABLE "entries" ADD COLUMN "hash" bytea;
+
+ALTER TABLE "accounts" ADD COLUMN "entry_hash" bytea;
+
+COMMENT ON COLUMN "entries"."prev_hash" IS 'hash of the previous entry of the account, null for entries written before the chain existed';
+
+COMMENT ON COLUMN "entries"."hash" IS 'SHA-256 over prev_hash, the entry and its transfer, null for entries written before the chain existed';
+
+COMMENT ON COLUMN "accounts"."entry_hash" IS 'hash of the latest entry of the account, the head of its entry chain';
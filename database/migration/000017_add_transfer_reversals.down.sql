@@ -0,0 +1 @@
+ALTER TABLE IF EXISTS "transfer" DROP COLUMN IF EXISTS "reverses_transfer_id";
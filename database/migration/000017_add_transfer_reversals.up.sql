@@ -0,0 +1,7 @@
+ALTER TABLE "transfer" ADD COLUMN "reverses_transfer_id" bigint;
+
+COMMENT ON COLUMN "transfer"."reverses_transfer_id" IS 'the transfer this one gives money back for, null for ordinary transfers';
+
+ALTER TABLE "transfer" ADD FOREIGN KEY ("reverses_transfer_id") REFERENCES "transfer" ("id");
+
+CREATE INDEX ON "transfer" ("reverses_transfer_id");
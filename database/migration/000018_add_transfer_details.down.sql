@@ -0,0 +1,5 @@
+ALTER TABLE IF EXISTS "transfer" DROP COLUMN IF EXISTS "metadata";
+
+ALTER TABLE IF EXISTS "transfer" DROP COLUMN IF EXISTS "external_reference";
+
+ALTER TABLE IF EXISTS "transfer" DROP COLUMN IF EXISTS "memo";
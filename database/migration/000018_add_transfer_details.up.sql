@@ -0,0 +1,15 @@
+ALTER TABLE "transfer" ADD COLUMN "memo" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "transfer" ADD COLUMN "external_reference" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "transfer" ADD COLUMN "metadata" jsonb NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN "transfer"."memo" IS 'free text the sender wrote about the transfer';
+
+COMMENT ON COLUMN "transfer"."external_reference" IS 'reference in another system, e.g. an invoice number';
+
+COMMENT ON COLUMN "transfer"."metadata" IS 'string keys to string values set by the client';
+
+CREATE INDEX ON "transfer" ("external_reference") WHERE "external_reference" <> '';
+
+CREATE INDEX ON "transfer" USING GIN ("metadata" jsonb_path_ops);
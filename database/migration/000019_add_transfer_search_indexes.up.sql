@@ -0,0 +1,4 @@
+-- SearchTransfers walks the transfers of each side newest first, seeking past the cursor
+CREATE INDEX ON "transfer" ("from_account_id", "created_at", "id");
+
+CREATE INDEX ON "transfer" ("to_account_id", "created_at", "id");
@@ -0,0 +1,2 @@
+-- Sessions are listed and revoked by user
+CREATE INDEX ON "sessions" ("username");
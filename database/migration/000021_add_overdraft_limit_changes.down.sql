@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "overdraft_limit_changes";
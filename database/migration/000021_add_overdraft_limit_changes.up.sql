@@ -0,0 +1,17 @@
+CREATE TABLE "overdraft_limit_changes" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "previous_limit" bigint NOT NULL,
+  "new_limit" bigint NOT NULL,
+  "reason" varchar NOT NULL,
+  "changed_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "overdraft_limit_changes" ("account_id");
+
+COMMENT ON COLUMN "overdraft_limit_changes"."changed_by" IS 'username of the banker or admin who changed the limit';
+
+ALTER TABLE "overdraft_limit_changes" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "overdraft_limit_changes" ADD FOREIGN KEY ("changed_by") REFERENCES "users" ("username") ON UPDATE CASCADE;
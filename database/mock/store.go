@@ -6,9 +6,11 @@ package mockdb
 
 import (
 	context "context"
+	sql "database/sql"
 	reflect "reflect"
 
 	sqlc "github.com/October-9th/simple-bank/database/sqlc"
+	fx "github.com/October-9th/simple-bank/fx"
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
 )
@@ -51,6 +53,138 @@ func (mr *MockStoreMockRecorder) AddAccountBalance(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccountBalance", reflect.TypeOf((*MockStore)(nil).AddAccountBalance), arg0, arg1)
 }
 
+// AdjustBalanceTx mocks base method.
+func (m *MockStore) AdjustBalanceTx(arg0 context.Context, arg1 sqlc.AdjustBalanceTxParams) (sqlc.AdjustBalanceTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustBalanceTx", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.AdjustBalanceTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdjustBalanceTx indicates an expected call of AdjustBalanceTx.
+func (mr *MockStoreMockRecorder) AdjustBalanceTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustBalanceTx", reflect.TypeOf((*MockStore)(nil).AdjustBalanceTx), arg0, arg1)
+}
+
+// BlockOtherUserSessions mocks base method.
+func (m *MockStore) BlockOtherUserSessions(arg0 context.Context, arg1 sqlc.BlockOtherUserSessionsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockOtherUserSessions", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockOtherUserSessions indicates an expected call of BlockOtherUserSessions.
+func (mr *MockStoreMockRecorder) BlockOtherUserSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockOtherUserSessions", reflect.TypeOf((*MockStore)(nil).BlockOtherUserSessions), arg0, arg1)
+}
+
+// BlockSession mocks base method.
+func (m *MockStore) BlockSession(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockSession", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockSession indicates an expected call of BlockSession.
+func (mr *MockStoreMockRecorder) BlockSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockSession", reflect.TypeOf((*MockStore)(nil).BlockSession), arg0, arg1)
+}
+
+// BlockSessionFamily mocks base method.
+func (m *MockStore) BlockSessionFamily(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockSessionFamily", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockSessionFamily indicates an expected call of BlockSessionFamily.
+func (mr *MockStoreMockRecorder) BlockSessionFamily(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockSessionFamily", reflect.TypeOf((*MockStore)(nil).BlockSessionFamily), arg0, arg1)
+}
+
+// BlockUserSessionFamily mocks base method.
+func (m *MockStore) BlockUserSessionFamily(arg0 context.Context, arg1 sqlc.BlockUserSessionFamilyParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockUserSessionFamily", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockUserSessionFamily indicates an expected call of BlockUserSessionFamily.
+func (mr *MockStoreMockRecorder) BlockUserSessionFamily(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockUserSessionFamily", reflect.TypeOf((*MockStore)(nil).BlockUserSessionFamily), arg0, arg1)
+}
+
+// BlockUserSessions mocks base method.
+func (m *MockStore) BlockUserSessions(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockUserSessions", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockUserSessions indicates an expected call of BlockUserSessions.
+func (mr *MockStoreMockRecorder) BlockUserSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockUserSessions", reflect.TypeOf((*MockStore)(nil).BlockUserSessions), arg0, arg1)
+}
+
+// CloseAccount mocks base method.
+func (m *MockStore) CloseAccount(arg0 context.Context, arg1 int64) (sqlc.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseAccount", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseAccount indicates an expected call of CloseAccount.
+func (mr *MockStoreMockRecorder) CloseAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAccount", reflect.TypeOf((*MockStore)(nil).CloseAccount), arg0, arg1)
+}
+
+// CloseAccountTx mocks base method.
+func (m *MockStore) CloseAccountTx(arg0 context.Context, arg1 int64) (sqlc.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseAccountTx", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseAccountTx indicates an expected call of CloseAccountTx.
+func (mr *MockStoreMockRecorder) CloseAccountTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAccountTx", reflect.TypeOf((*MockStore)(nil).CloseAccountTx), arg0, arg1)
+}
+
+// CountLedgerRows mocks base method.
+func (m *MockStore) CountLedgerRows(arg0 context.Context) (sqlc.CountLedgerRowsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountLedgerRows", arg0)
+	ret0, _ := ret[0].(sqlc.CountLedgerRowsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountLedgerRows indicates an expected call of CountLedgerRows.
+func (mr *MockStoreMockRecorder) CountLedgerRows(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountLedgerRows", reflect.TypeOf((*MockStore)(nil).CountLedgerRows), arg0)
+}
+
 // CreateAccount mocks base method.
 func (m *MockStore) CreateAccount(arg0 context.Context, arg1 sqlc.CreateAccountParams) (sqlc.Account, error) {
 	m.ctrl.T.Helper()
@@ -66,6 +200,36 @@ func (mr *MockStoreMockRecorder) CreateAccount(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockStore)(nil).CreateAccount), arg0, arg1)
 }
 
+// CreateBalanceAdjustment mocks base method.
+func (m *MockStore) CreateBalanceAdjustment(arg0 context.Context, arg1 sqlc.CreateBalanceAdjustmentParams) (sqlc.BalanceAdjustment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBalanceAdjustment", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.BalanceAdjustment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBalanceAdjustment indicates an expected call of CreateBalanceAdjustment.
+func (mr *MockStoreMockRecorder) CreateBalanceAdjustment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBalanceAdjustment", reflect.TypeOf((*MockStore)(nil).CreateBalanceAdjustment), arg0, arg1)
+}
+
+// CreateBalanceSnapshots mocks base method.
+func (m *MockStore) CreateBalanceSnapshots(arg0 context.Context, arg1 sqlc.CreateBalanceSnapshotsParams) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBalanceSnapshots", arg0, arg1)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBalanceSnapshots indicates an expected call of CreateBalanceSnapshots.
+func (mr *MockStoreMockRecorder) CreateBalanceSnapshots(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBalanceSnapshots", reflect.TypeOf((*MockStore)(nil).CreateBalanceSnapshots), arg0, arg1)
+}
+
 // CreateEntry mocks base method.
 func (m *MockStore) CreateEntry(arg0 context.Context, arg1 sqlc.CreateEntryParams) (sqlc.Entry, error) {
 	m.ctrl.T.Helper()
@@ -81,6 +245,66 @@ func (mr *MockStoreMockRecorder) CreateEntry(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockStore)(nil).CreateEntry), arg0, arg1)
 }
 
+// CreateIdempotencyKey mocks base method.
+func (m *MockStore) CreateIdempotencyKey(arg0 context.Context, arg1 sqlc.CreateIdempotencyKeyParams) (sqlc.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIdempotencyKey", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIdempotencyKey indicates an expected call of CreateIdempotencyKey.
+func (mr *MockStoreMockRecorder) CreateIdempotencyKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIdempotencyKey", reflect.TypeOf((*MockStore)(nil).CreateIdempotencyKey), arg0, arg1)
+}
+
+// CreateOverdraftLimitChange mocks base method.
+func (m *MockStore) CreateOverdraftLimitChange(arg0 context.Context, arg1 sqlc.CreateOverdraftLimitChangeParams) (sqlc.OverdraftLimitChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOverdraftLimitChange", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.OverdraftLimitChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOverdraftLimitChange indicates an expected call of CreateOverdraftLimitChange.
+func (mr *MockStoreMockRecorder) CreateOverdraftLimitChange(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOverdraftLimitChange", reflect.TypeOf((*MockStore)(nil).CreateOverdraftLimitChange), arg0, arg1)
+}
+
+// CreateReconciliationDiscrepancy mocks base method.
+func (m *MockStore) CreateReconciliationDiscrepancy(arg0 context.Context, arg1 sqlc.CreateReconciliationDiscrepancyParams) (sqlc.ReconciliationDiscrepancy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReconciliationDiscrepancy", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.ReconciliationDiscrepancy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReconciliationDiscrepancy indicates an expected call of CreateReconciliationDiscrepancy.
+func (mr *MockStoreMockRecorder) CreateReconciliationDiscrepancy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReconciliationDiscrepancy", reflect.TypeOf((*MockStore)(nil).CreateReconciliationDiscrepancy), arg0, arg1)
+}
+
+// CreateReconciliationRun mocks base method.
+func (m *MockStore) CreateReconciliationRun(arg0 context.Context, arg1 sqlc.CreateReconciliationRunParams) (sqlc.ReconciliationRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReconciliationRun", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.ReconciliationRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReconciliationRun indicates an expected call of CreateReconciliationRun.
+func (mr *MockStoreMockRecorder) CreateReconciliationRun(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReconciliationRun", reflect.TypeOf((*MockStore)(nil).CreateReconciliationRun), arg0, arg1)
+}
+
 // CreateSession mocks base method.
 func (m *MockStore) CreateSession(arg0 context.Context, arg1 sqlc.CreateSessionParams) (sqlc.Session, error) {
 	m.ctrl.T.Helper()
@@ -126,18 +350,19 @@ func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
 }
 
-// DeleteAccount mocks base method.
-func (m *MockStore) DeleteAccount(arg0 context.Context, arg1 int64) error {
+// FreezeAccountTx mocks base method.
+func (m *MockStore) FreezeAccountTx(arg0 context.Context, arg1 int64) (sqlc.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAccount", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "FreezeAccountTx", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteAccount indicates an expected call of DeleteAccount.
-func (mr *MockStoreMockRecorder) DeleteAccount(arg0, arg1 interface{}) *gomock.Call {
+// FreezeAccountTx indicates an expected call of FreezeAccountTx.
+func (mr *MockStoreMockRecorder) FreezeAccountTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockStore)(nil).DeleteAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreezeAccountTx", reflect.TypeOf((*MockStore)(nil).FreezeAccountTx), arg0, arg1)
 }
 
 // GetAccount mocks base method.
@@ -170,6 +395,51 @@ func (mr *MockStoreMockRecorder) GetAccountForUpdated(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountForUpdated", reflect.TypeOf((*MockStore)(nil).GetAccountForUpdated), arg0, arg1)
 }
 
+// GetBalanceAsOf mocks base method.
+func (m *MockStore) GetBalanceAsOf(arg0 context.Context, arg1 sqlc.GetBalanceAsOfParams) (sqlc.AccountBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceAsOf", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.AccountBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceAsOf indicates an expected call of GetBalanceAsOf.
+func (mr *MockStoreMockRecorder) GetBalanceAsOf(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceAsOf", reflect.TypeOf((*MockStore)(nil).GetBalanceAsOf), arg0, arg1)
+}
+
+// GetBalanceSnapshot mocks base method.
+func (m *MockStore) GetBalanceSnapshot(arg0 context.Context, arg1 sqlc.GetBalanceSnapshotParams) (sqlc.BalanceSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceSnapshot", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.BalanceSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceSnapshot indicates an expected call of GetBalanceSnapshot.
+func (mr *MockStoreMockRecorder) GetBalanceSnapshot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceSnapshot", reflect.TypeOf((*MockStore)(nil).GetBalanceSnapshot), arg0, arg1)
+}
+
+// GetCurrency mocks base method.
+func (m *MockStore) GetCurrency(arg0 context.Context, arg1 string) (sqlc.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrency", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrency indicates an expected call of GetCurrency.
+func (mr *MockStoreMockRecorder) GetCurrency(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrency", reflect.TypeOf((*MockStore)(nil).GetCurrency), arg0, arg1)
+}
+
 // GetEntry mocks base method.
 func (m *MockStore) GetEntry(arg0 context.Context, arg1 int64) (sqlc.Entry, error) {
 	m.ctrl.T.Helper()
@@ -185,6 +455,51 @@ func (mr *MockStoreMockRecorder) GetEntry(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockStore)(nil).GetEntry), arg0, arg1)
 }
 
+// GetExchangeRate mocks base method.
+func (m *MockStore) GetExchangeRate(arg0 context.Context, arg1 sqlc.GetExchangeRateParams) (sqlc.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExchangeRate", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExchangeRate indicates an expected call of GetExchangeRate.
+func (mr *MockStoreMockRecorder) GetExchangeRate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExchangeRate", reflect.TypeOf((*MockStore)(nil).GetExchangeRate), arg0, arg1)
+}
+
+// GetIdempotencyKey mocks base method.
+func (m *MockStore) GetIdempotencyKey(arg0 context.Context, arg1 sqlc.GetIdempotencyKeyParams) (sqlc.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdempotencyKey", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdempotencyKey indicates an expected call of GetIdempotencyKey.
+func (mr *MockStoreMockRecorder) GetIdempotencyKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdempotencyKey", reflect.TypeOf((*MockStore)(nil).GetIdempotencyKey), arg0, arg1)
+}
+
+// GetReconciliationRun mocks base method.
+func (m *MockStore) GetReconciliationRun(arg0 context.Context, arg1 int64) (sqlc.ReconciliationRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReconciliationRun", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.ReconciliationRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReconciliationRun indicates an expected call of GetReconciliationRun.
+func (mr *MockStoreMockRecorder) GetReconciliationRun(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReconciliationRun", reflect.TypeOf((*MockStore)(nil).GetReconciliationRun), arg0, arg1)
+}
+
 // GetSession mocks base method.
 func (m *MockStore) GetSession(arg0 context.Context, arg1 uuid.UUID) (sqlc.Session, error) {
 	m.ctrl.T.Helper()
@@ -215,6 +530,21 @@ func (mr *MockStoreMockRecorder) GetTransfer(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockStore)(nil).GetTransfer), arg0, arg1)
 }
 
+// GetTransferForUpdate mocks base method.
+func (m *MockStore) GetTransferForUpdate(arg0 context.Context, arg1 int64) (sqlc.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferForUpdate indicates an expected call of GetTransferForUpdate.
+func (mr *MockStoreMockRecorder) GetTransferForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferForUpdate", reflect.TypeOf((*MockStore)(nil).GetTransferForUpdate), arg0, arg1)
+}
+
 // GetUser mocks base method.
 func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (sqlc.User, error) {
 	m.ctrl.T.Helper()
@@ -230,6 +560,21 @@ func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
 }
 
+// IsSessionFamilyBlocked mocks base method.
+func (m *MockStore) IsSessionFamilyBlocked(arg0 context.Context, arg1 uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsSessionFamilyBlocked", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsSessionFamilyBlocked indicates an expected call of IsSessionFamilyBlocked.
+func (mr *MockStoreMockRecorder) IsSessionFamilyBlocked(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSessionFamilyBlocked", reflect.TypeOf((*MockStore)(nil).IsSessionFamilyBlocked), arg0, arg1)
+}
+
 // ListAccounts mocks base method.
 func (m *MockStore) ListAccounts(arg0 context.Context, arg1 sqlc.ListAccountsParams) ([]sqlc.Account, error) {
 	m.ctrl.T.Helper()
@@ -245,6 +590,96 @@ func (mr *MockStoreMockRecorder) ListAccounts(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockStore)(nil).ListAccounts), arg0, arg1)
 }
 
+// ListActiveSessions mocks base method.
+func (m *MockStore) ListActiveSessions(arg0 context.Context, arg1 sqlc.ListActiveSessionsParams) ([]sqlc.ListActiveSessionsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveSessions", arg0, arg1)
+	ret0, _ := ret[0].([]sqlc.ListActiveSessionsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveSessions indicates an expected call of ListActiveSessions.
+func (mr *MockStoreMockRecorder) ListActiveSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveSessions", reflect.TypeOf((*MockStore)(nil).ListActiveSessions), arg0, arg1)
+}
+
+// ListAdjustmentMismatches mocks base method.
+func (m *MockStore) ListAdjustmentMismatches(arg0 context.Context) ([]sqlc.ListAdjustmentMismatchesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAdjustmentMismatches", arg0)
+	ret0, _ := ret[0].([]sqlc.ListAdjustmentMismatchesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAdjustmentMismatches indicates an expected call of ListAdjustmentMismatches.
+func (mr *MockStoreMockRecorder) ListAdjustmentMismatches(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAdjustmentMismatches", reflect.TypeOf((*MockStore)(nil).ListAdjustmentMismatches), arg0)
+}
+
+// ListBalanceAdjustments mocks base method.
+func (m *MockStore) ListBalanceAdjustments(arg0 context.Context, arg1 sqlc.ListBalanceAdjustmentsParams) ([]sqlc.BalanceAdjustment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBalanceAdjustments", arg0, arg1)
+	ret0, _ := ret[0].([]sqlc.BalanceAdjustment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBalanceAdjustments indicates an expected call of ListBalanceAdjustments.
+func (mr *MockStoreMockRecorder) ListBalanceAdjustments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBalanceAdjustments", reflect.TypeOf((*MockStore)(nil).ListBalanceAdjustments), arg0, arg1)
+}
+
+// ListBalanceMismatches mocks base method.
+func (m *MockStore) ListBalanceMismatches(arg0 context.Context) ([]sqlc.ListBalanceMismatchesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBalanceMismatches", arg0)
+	ret0, _ := ret[0].([]sqlc.ListBalanceMismatchesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBalanceMismatches indicates an expected call of ListBalanceMismatches.
+func (mr *MockStoreMockRecorder) ListBalanceMismatches(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBalanceMismatches", reflect.TypeOf((*MockStore)(nil).ListBalanceMismatches), arg0)
+}
+
+// ListBalancesAsOf mocks base method.
+func (m *MockStore) ListBalancesAsOf(arg0 context.Context, arg1 sqlc.ListBalancesAsOfParams) ([]sqlc.AccountBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBalancesAsOf", arg0, arg1)
+	ret0, _ := ret[0].([]sqlc.AccountBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBalancesAsOf indicates an expected call of ListBalancesAsOf.
+func (mr *MockStoreMockRecorder) ListBalancesAsOf(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBalancesAsOf", reflect.TypeOf((*MockStore)(nil).ListBalancesAsOf), arg0, arg1)
+}
+
+// ListCurrencies mocks base method.
+func (m *MockStore) ListCurrencies(arg0 context.Context) ([]sqlc.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCurrencies", arg0)
+	ret0, _ := ret[0].([]sqlc.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCurrencies indicates an expected call of ListCurrencies.
+func (mr *MockStoreMockRecorder) ListCurrencies(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCurrencies", reflect.TypeOf((*MockStore)(nil).ListCurrencies), arg0)
+}
+
 // ListEntries mocks base method.
 func (m *MockStore) ListEntries(arg0 context.Context, arg1 sqlc.ListEntriesParams) ([]sqlc.Entry, error) {
 	m.ctrl.T.Helper()
@@ -260,6 +695,156 @@ func (mr *MockStoreMockRecorder) ListEntries(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockStore)(nil).ListEntries), arg0, arg1)
 }
 
+// ListEntryChain mocks base method.
+func (m *MockStore) ListEntryChain(arg0 context.Context, arg1 sqlc.ListEntryChainParams) ([]sqlc.ListEntryChainRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntryChain", arg0, arg1)
+	ret0, _ := ret[0].([]sqlc.ListEntryChainRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntryChain indicates an expected call of ListEntryChain.
+func (mr *MockStoreMockRecorder) ListEntryChain(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntryChain", reflect.TypeOf((*MockStore)(nil).ListEntryChain), arg0, arg1)
+}
+
+// ListExchangeRates mocks base method.
+func (m *MockStore) ListExchangeRates(arg0 context.Context, arg1 sqlc.ListExchangeRatesParams) ([]sqlc.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExchangeRates", arg0, arg1)
+	ret0, _ := ret[0].([]sqlc.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExchangeRates indicates an expected call of ListExchangeRates.
+func (mr *MockStoreMockRecorder) ListExchangeRates(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExchangeRates", reflect.TypeOf((*MockStore)(nil).ListExchangeRates), arg0, arg1)
+}
+
+// ListOrphanEntries mocks base method.
+func (m *MockStore) ListOrphanEntries(arg0 context.Context) ([]sqlc.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrphanEntries", arg0)
+	ret0, _ := ret[0].([]sqlc.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrphanEntries indicates an expected call of ListOrphanEntries.
+func (mr *MockStoreMockRecorder) ListOrphanEntries(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrphanEntries", reflect.TypeOf((*MockStore)(nil).ListOrphanEntries), arg0)
+}
+
+// ListOverdraftLimitChanges mocks base method.
+func (m *MockStore) ListOverdraftLimitChanges(arg0 context.Context, arg1 sqlc.ListOverdraftLimitChangesParams) ([]sqlc.OverdraftLimitChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOverdraftLimitChanges", arg0, arg1)
+	ret0, _ := ret[0].([]sqlc.OverdraftLimitChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOverdraftLimitChanges indicates an expected call of ListOverdraftLimitChanges.
+func (mr *MockStoreMockRecorder) ListOverdraftLimitChanges(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOverdraftLimitChanges", reflect.TypeOf((*MockStore)(nil).ListOverdraftLimitChanges), arg0, arg1)
+}
+
+// ListOwnerBalancesAsOf mocks base method.
+func (m *MockStore) ListOwnerBalancesAsOf(arg0 context.Context, arg1 sqlc.ListOwnerBalancesAsOfParams) ([]sqlc.ListOwnerBalancesAsOfRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOwnerBalancesAsOf", arg0, arg1)
+	ret0, _ := ret[0].([]sqlc.ListOwnerBalancesAsOfRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOwnerBalancesAsOf indicates an expected call of ListOwnerBalancesAsOf.
+func (mr *MockStoreMockRecorder) ListOwnerBalancesAsOf(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOwnerBalancesAsOf", reflect.TypeOf((*MockStore)(nil).ListOwnerBalancesAsOf), arg0, arg1)
+}
+
+// ListReconciliationDiscrepancies mocks base method.
+func (m *MockStore) ListReconciliationDiscrepancies(arg0 context.Context, arg1 int64) ([]sqlc.ReconciliationDiscrepancy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReconciliationDiscrepancies", arg0, arg1)
+	ret0, _ := ret[0].([]sqlc.ReconciliationDiscrepancy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReconciliationDiscrepancies indicates an expected call of ListReconciliationDiscrepancies.
+func (mr *MockStoreMockRecorder) ListReconciliationDiscrepancies(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReconciliationDiscrepancies", reflect.TypeOf((*MockStore)(nil).ListReconciliationDiscrepancies), arg0, arg1)
+}
+
+// ListReconciliationRuns mocks base method.
+func (m *MockStore) ListReconciliationRuns(arg0 context.Context, arg1 sqlc.ListReconciliationRunsParams) ([]sqlc.ReconciliationRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReconciliationRuns", arg0, arg1)
+	ret0, _ := ret[0].([]sqlc.ReconciliationRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReconciliationRuns indicates an expected call of ListReconciliationRuns.
+func (mr *MockStoreMockRecorder) ListReconciliationRuns(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReconciliationRuns", reflect.TypeOf((*MockStore)(nil).ListReconciliationRuns), arg0, arg1)
+}
+
+// ListSnapshotMismatches mocks base method.
+func (m *MockStore) ListSnapshotMismatches(arg0 context.Context) ([]sqlc.ListSnapshotMismatchesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSnapshotMismatches", arg0)
+	ret0, _ := ret[0].([]sqlc.ListSnapshotMismatchesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSnapshotMismatches indicates an expected call of ListSnapshotMismatches.
+func (mr *MockStoreMockRecorder) ListSnapshotMismatches(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSnapshotMismatches", reflect.TypeOf((*MockStore)(nil).ListSnapshotMismatches), arg0)
+}
+
+// ListStatementEntries mocks base method.
+func (m *MockStore) ListStatementEntries(arg0 context.Context, arg1 sqlc.ListStatementEntriesParams) ([]sqlc.ListStatementEntriesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStatementEntries", arg0, arg1)
+	ret0, _ := ret[0].([]sqlc.ListStatementEntriesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStatementEntries indicates an expected call of ListStatementEntries.
+func (mr *MockStoreMockRecorder) ListStatementEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStatementEntries", reflect.TypeOf((*MockStore)(nil).ListStatementEntries), arg0, arg1)
+}
+
+// ListTransferEntriesMismatches mocks base method.
+func (m *MockStore) ListTransferEntriesMismatches(arg0 context.Context) ([]sqlc.ListTransferEntriesMismatchesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransferEntriesMismatches", arg0)
+	ret0, _ := ret[0].([]sqlc.ListTransferEntriesMismatchesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransferEntriesMismatches indicates an expected call of ListTransferEntriesMismatches.
+func (mr *MockStoreMockRecorder) ListTransferEntriesMismatches(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransferEntriesMismatches", reflect.TypeOf((*MockStore)(nil).ListTransferEntriesMismatches), arg0)
+}
+
 // ListTransfers mocks base method.
 func (m *MockStore) ListTransfers(arg0 context.Context, arg1 sqlc.ListTransfersParams) ([]sqlc.Transfer, error) {
 	m.ctrl.T.Helper()
@@ -275,6 +860,214 @@ func (mr *MockStoreMockRecorder) ListTransfers(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfers", reflect.TypeOf((*MockStore)(nil).ListTransfers), arg0, arg1)
 }
 
+// LoadExchangeRatesTx mocks base method.
+func (m *MockStore) LoadExchangeRatesTx(arg0 context.Context, arg1 []fx.Rate) ([]sqlc.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadExchangeRatesTx", arg0, arg1)
+	ret0, _ := ret[0].([]sqlc.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadExchangeRatesTx indicates an expected call of LoadExchangeRatesTx.
+func (mr *MockStoreMockRecorder) LoadExchangeRatesTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadExchangeRatesTx", reflect.TypeOf((*MockStore)(nil).LoadExchangeRatesTx), arg0, arg1)
+}
+
+// LockLedgerDay mocks base method.
+func (m *MockStore) LockLedgerDay(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockLedgerDay", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockLedgerDay indicates an expected call of LockLedgerDay.
+func (mr *MockStoreMockRecorder) LockLedgerDay(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockLedgerDay", reflect.TypeOf((*MockStore)(nil).LockLedgerDay), arg0)
+}
+
+// MarkSessionRotated mocks base method.
+func (m *MockStore) MarkSessionRotated(arg0 context.Context, arg1 uuid.UUID) (sqlc.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkSessionRotated", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkSessionRotated indicates an expected call of MarkSessionRotated.
+func (mr *MockStoreMockRecorder) MarkSessionRotated(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkSessionRotated", reflect.TypeOf((*MockStore)(nil).MarkSessionRotated), arg0, arg1)
+}
+
+// ReconcileTx mocks base method.
+func (m *MockStore) ReconcileTx(arg0 context.Context, arg1 string) (sqlc.ReconcileTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileTx", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.ReconcileTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileTx indicates an expected call of ReconcileTx.
+func (mr *MockStoreMockRecorder) ReconcileTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileTx", reflect.TypeOf((*MockStore)(nil).ReconcileTx), arg0, arg1)
+}
+
+// ReverseTransferTx mocks base method.
+func (m *MockStore) ReverseTransferTx(arg0 context.Context, arg1 sqlc.ReverseTransferTxParams) (sqlc.ReverseTransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReverseTransferTx", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.ReverseTransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReverseTransferTx indicates an expected call of ReverseTransferTx.
+func (mr *MockStoreMockRecorder) ReverseTransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReverseTransferTx", reflect.TypeOf((*MockStore)(nil).ReverseTransferTx), arg0, arg1)
+}
+
+// RotateSessionTx mocks base method.
+func (m *MockStore) RotateSessionTx(arg0 context.Context, arg1 sqlc.RotateSessionTxParams) (sqlc.RotateSessionTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateSessionTx", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.RotateSessionTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateSessionTx indicates an expected call of RotateSessionTx.
+func (mr *MockStoreMockRecorder) RotateSessionTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateSessionTx", reflect.TypeOf((*MockStore)(nil).RotateSessionTx), arg0, arg1)
+}
+
+// SealEntry mocks base method.
+func (m *MockStore) SealEntry(arg0 context.Context, arg1 sqlc.SealEntryParams) (sqlc.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SealEntry", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SealEntry indicates an expected call of SealEntry.
+func (mr *MockStoreMockRecorder) SealEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SealEntry", reflect.TypeOf((*MockStore)(nil).SealEntry), arg0, arg1)
+}
+
+// SearchTransfers mocks base method.
+func (m *MockStore) SearchTransfers(arg0 context.Context, arg1 sqlc.SearchTransfersParams) ([]sqlc.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]sqlc.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTransfers indicates an expected call of SearchTransfers.
+func (mr *MockStoreMockRecorder) SearchTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTransfers", reflect.TypeOf((*MockStore)(nil).SearchTransfers), arg0, arg1)
+}
+
+// SetLockTimeout mocks base method.
+func (m *MockStore) SetLockTimeout(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLockTimeout", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLockTimeout indicates an expected call of SetLockTimeout.
+func (mr *MockStoreMockRecorder) SetLockTimeout(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLockTimeout", reflect.TypeOf((*MockStore)(nil).SetLockTimeout), arg0, arg1)
+}
+
+// SnapshotBalances mocks base method.
+func (m *MockStore) SnapshotBalances(arg0 context.Context, arg1 sqlc.SnapshotBalancesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SnapshotBalances", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SnapshotBalances indicates an expected call of SnapshotBalances.
+func (mr *MockStoreMockRecorder) SnapshotBalances(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SnapshotBalances", reflect.TypeOf((*MockStore)(nil).SnapshotBalances), arg0, arg1)
+}
+
+// StatementTx mocks base method.
+func (m *MockStore) StatementTx(arg0 context.Context, arg1 sqlc.StatementTxParams) (sqlc.StatementTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StatementTx", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.StatementTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StatementTx indicates an expected call of StatementTx.
+func (mr *MockStoreMockRecorder) StatementTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatementTx", reflect.TypeOf((*MockStore)(nil).StatementTx), arg0, arg1)
+}
+
+// SumBalanceAsOf mocks base method.
+func (m *MockStore) SumBalanceAsOf(arg0 context.Context, arg1 sqlc.SumBalanceAsOfParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumBalanceAsOf", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumBalanceAsOf indicates an expected call of SumBalanceAsOf.
+func (mr *MockStoreMockRecorder) SumBalanceAsOf(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumBalanceAsOf", reflect.TypeOf((*MockStore)(nil).SumBalanceAsOf), arg0, arg1)
+}
+
+// SumBalanceBefore mocks base method.
+func (m *MockStore) SumBalanceBefore(arg0 context.Context, arg1 sqlc.SumBalanceBeforeParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumBalanceBefore", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumBalanceBefore indicates an expected call of SumBalanceBefore.
+func (mr *MockStoreMockRecorder) SumBalanceBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumBalanceBefore", reflect.TypeOf((*MockStore)(nil).SumBalanceBefore), arg0, arg1)
+}
+
+// SumTransferReversals mocks base method.
+func (m *MockStore) SumTransferReversals(arg0 context.Context, arg1 sql.NullInt64) (sqlc.SumTransferReversalsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumTransferReversals", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.SumTransferReversalsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumTransferReversals indicates an expected call of SumTransferReversals.
+func (mr *MockStoreMockRecorder) SumTransferReversals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumTransferReversals", reflect.TypeOf((*MockStore)(nil).SumTransferReversals), arg0, arg1)
+}
+
 // TransferTx mocks base method.
 func (m *MockStore) TransferTx(arg0 context.Context, arg1 sqlc.TransferTxParams) (sqlc.TransferTxResult, error) {
 	m.ctrl.T.Helper()
@@ -290,17 +1083,150 @@ func (mr *MockStoreMockRecorder) TransferTx(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferTx", reflect.TypeOf((*MockStore)(nil).TransferTx), arg0, arg1)
 }
 
-// UpdateAccount mocks base method.
-func (m *MockStore) UpdateAccount(arg0 context.Context, arg1 sqlc.UpdateAccountParams) (sqlc.Account, error) {
+// UnfreezeAccountTx mocks base method.
+func (m *MockStore) UnfreezeAccountTx(arg0 context.Context, arg1 int64) (sqlc.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnfreezeAccountTx", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnfreezeAccountTx indicates an expected call of UnfreezeAccountTx.
+func (mr *MockStoreMockRecorder) UnfreezeAccountTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfreezeAccountTx", reflect.TypeOf((*MockStore)(nil).UnfreezeAccountTx), arg0, arg1)
+}
+
+// UpdateAccountEntryHash mocks base method.
+func (m *MockStore) UpdateAccountEntryHash(arg0 context.Context, arg1 sqlc.UpdateAccountEntryHashParams) (sqlc.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountEntryHash", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccountEntryHash indicates an expected call of UpdateAccountEntryHash.
+func (mr *MockStoreMockRecorder) UpdateAccountEntryHash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountEntryHash", reflect.TypeOf((*MockStore)(nil).UpdateAccountEntryHash), arg0, arg1)
+}
+
+// UpdateAccountOverdraftLimit mocks base method.
+func (m *MockStore) UpdateAccountOverdraftLimit(arg0 context.Context, arg1 sqlc.UpdateAccountOverdraftLimitParams) (sqlc.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountOverdraftLimit", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccountOverdraftLimit indicates an expected call of UpdateAccountOverdraftLimit.
+func (mr *MockStoreMockRecorder) UpdateAccountOverdraftLimit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountOverdraftLimit", reflect.TypeOf((*MockStore)(nil).UpdateAccountOverdraftLimit), arg0, arg1)
+}
+
+// UpdateAccountStatus mocks base method.
+func (m *MockStore) UpdateAccountStatus(arg0 context.Context, arg1 sqlc.UpdateAccountStatusParams) (sqlc.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateAccount", arg0, arg1)
+	ret := m.ctrl.Call(m, "UpdateAccountStatus", arg0, arg1)
 	ret0, _ := ret[0].(sqlc.Account)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateAccount indicates an expected call of UpdateAccount.
-func (mr *MockStoreMockRecorder) UpdateAccount(arg0, arg1 interface{}) *gomock.Call {
+// UpdateAccountStatus indicates an expected call of UpdateAccountStatus.
+func (mr *MockStoreMockRecorder) UpdateAccountStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountStatus", reflect.TypeOf((*MockStore)(nil).UpdateAccountStatus), arg0, arg1)
+}
+
+// UpdateIdempotencyKeyResponse mocks base method.
+func (m *MockStore) UpdateIdempotencyKeyResponse(arg0 context.Context, arg1 sqlc.UpdateIdempotencyKeyResponseParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateIdempotencyKeyResponse", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateIdempotencyKeyResponse indicates an expected call of UpdateIdempotencyKeyResponse.
+func (mr *MockStoreMockRecorder) UpdateIdempotencyKeyResponse(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIdempotencyKeyResponse", reflect.TypeOf((*MockStore)(nil).UpdateIdempotencyKeyResponse), arg0, arg1)
+}
+
+// UpdateOverdraftLimitTx mocks base method.
+func (m *MockStore) UpdateOverdraftLimitTx(arg0 context.Context, arg1 sqlc.UpdateOverdraftLimitTxParams) (sqlc.UpdateOverdraftLimitTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOverdraftLimitTx", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.UpdateOverdraftLimitTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOverdraftLimitTx indicates an expected call of UpdateOverdraftLimitTx.
+func (mr *MockStoreMockRecorder) UpdateOverdraftLimitTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOverdraftLimitTx", reflect.TypeOf((*MockStore)(nil).UpdateOverdraftLimitTx), arg0, arg1)
+}
+
+// UpdateUserRole mocks base method.
+func (m *MockStore) UpdateUserRole(arg0 context.Context, arg1 sqlc.UpdateUserRoleParams) (sqlc.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserRole", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserRole indicates an expected call of UpdateUserRole.
+func (mr *MockStoreMockRecorder) UpdateUserRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRole", reflect.TypeOf((*MockStore)(nil).UpdateUserRole), arg0, arg1)
+}
+
+// UpsertExchangeRate mocks base method.
+func (m *MockStore) UpsertExchangeRate(arg0 context.Context, arg1 sqlc.UpsertExchangeRateParams) (sqlc.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertExchangeRate", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertExchangeRate indicates an expected call of UpsertExchangeRate.
+func (mr *MockStoreMockRecorder) UpsertExchangeRate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertExchangeRate", reflect.TypeOf((*MockStore)(nil).UpsertExchangeRate), arg0, arg1)
+}
+
+// VerifyEntryChain mocks base method.
+func (m *MockStore) VerifyEntryChain(arg0 context.Context, arg1 int64) (sqlc.EntryChainResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEntryChain", arg0, arg1)
+	ret0, _ := ret[0].(sqlc.EntryChainResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyEntryChain indicates an expected call of VerifyEntryChain.
+func (mr *MockStoreMockRecorder) VerifyEntryChain(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEntryChain", reflect.TypeOf((*MockStore)(nil).VerifyEntryChain), arg0, arg1)
+}
+
+// WaitLedgerDay mocks base method.
+func (m *MockStore) WaitLedgerDay(arg0 context.Context, arg1 int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitLedgerDay", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WaitLedgerDay indicates an expected call of WaitLedgerDay.
+func (mr *MockStoreMockRecorder) WaitLedgerDay(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccount", reflect.TypeOf((*MockStore)(nil).UpdateAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitLedgerDay", reflect.TypeOf((*MockStore)(nil).WaitLedgerDay), arg0, arg1)
 }
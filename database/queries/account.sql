@@ -22,19 +22,33 @@ ORDER BY id
 LIMIT $2
 OFFSET $3;
 
--- name: UpdateAccount :one
+-- name: AddAccountBalance :one
 UPDATE accounts
-SET balance = $2
-WHERE id = $1
+SET balance = balance + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
 RETURNING *;
 
--- name: AddAccountBalance :one
+
+-- name: UpdateAccountOverdraftLimit :one
 UPDATE accounts
-SET balance = balance + sqlc.arg(amount)
+SET overdraft_limit = sqlc.arg(overdraft_limit)
 WHERE id = sqlc.arg(id)
 RETURNING *;
 
+-- name: CloseAccount :one
+UPDATE accounts
+SET status = 'closed', closed_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET status = sqlc.arg(status)
+WHERE id = sqlc.arg(id)
+RETURNING *;
 
--- name: DeleteAccount :exec
-DELETE FROM accounts
-WHERE id = $1; 
\ No newline at end of file
+-- name: UpdateAccountEntryHash :one
+UPDATE accounts
+SET entry_hash = sqlc.arg(entry_hash)
+WHERE id = sqlc.arg(id)
+RETURNING *;
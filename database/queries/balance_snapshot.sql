@@ -0,0 +1,48 @@
+-- name: CreateBalanceSnapshots :many
+-- Snapshots up to batch_size accounts that existed by cutoff and have no snapshot of the day yet,
+-- starting from the latest earlier snapshot of each account. Running it again only fills the gaps
+INSERT INTO balance_snapshots (account_id, snapshot_date, cutoff, balance)
+SELECT
+  a.id,
+  @snapshot_date::date,
+  @cutoff::timestamptz,
+  (COALESCE(prev.balance, 0) + COALESCE((
+    SELECT SUM(e.amount) FROM entries e
+    WHERE e.account_id = a.id
+      AND e.created_at >= COALESCE(prev.cutoff, '-infinity'::timestamptz)
+      AND e.created_at < @cutoff::timestamptz
+  ), 0))::bigint
+FROM accounts a
+LEFT JOIN LATERAL (
+  SELECT s.balance, s.cutoff FROM balance_snapshots s
+  WHERE s.account_id = a.id AND s.cutoff < @cutoff::timestamptz
+  ORDER BY s.cutoff DESC
+  LIMIT 1
+) prev ON true
+WHERE a.created_at < @cutoff::timestamptz
+  AND NOT EXISTS (
+    SELECT 1 FROM balance_snapshots s
+    WHERE s.account_id = a.id AND s.snapshot_date = @snapshot_date::date
+  )
+ORDER BY a.id
+LIMIT @batch_size::int
+ON CONFLICT (account_id, snapshot_date) DO NOTHING
+RETURNING account_id;
+
+-- name: GetBalanceSnapshot :one
+SELECT * FROM balance_snapshots
+WHERE account_id = $1 AND snapshot_date = $2
+LIMIT 1;
+
+-- name: LockLedgerDay :exec
+-- Taken by every transaction writing entries, shared with the others of the same UTC day. Its entries are stamped
+-- with the start of the transaction, the key is the day they belong to, in days since the epoch
+SELECT pg_advisory_xact_lock_shared(1818584167, (now() AT TIME ZONE 'UTC')::date - DATE '1970-01-01');
+
+-- name: WaitLedgerDay :exec
+-- Waits, up to the lock timeout of the transaction, until no transaction writing entries of day is still open
+SELECT pg_advisory_xact_lock(1818584167, @day::int);
+
+-- name: SetLockTimeout :exec
+-- Limits how long the statements of the current transaction wait for a lock
+SELECT pg_catalog.set_config('lock_timeout', @timeout::text, true);
@@ -1,9 +1,10 @@
 -- name: CreateEntry :one
 INSERT INTO entries (
   account_id,
-  amount
+  amount,
+  transfer_id
 ) VALUES (
-  $1, $2
+  $1, $2, $3
 ) RETURNING *;
 
 -- name: GetEntry :one
@@ -15,4 +16,102 @@ SELECT * FROM entries
 WHERE account_id = $1
 ORDER BY id
 LIMIT $2
-OFFSET $3;
\ No newline at end of file
+OFFSET $3;
+
+-- name: SumBalanceBefore :one
+-- Starts from the latest snapshot taken by before and adds the entries created after it
+WITH snapshot AS (
+  SELECT bs.balance, bs.cutoff FROM balance_snapshots bs
+  WHERE bs.account_id = @account_id::bigint AND bs.cutoff <= @before::timestamptz
+  ORDER BY bs.cutoff DESC
+  LIMIT 1
+)
+SELECT (COALESCE((SELECT snapshot.balance FROM snapshot), 0) + COALESCE((
+  SELECT SUM(e.amount) FROM entries e
+  WHERE e.account_id = @account_id::bigint
+    AND e.created_at >= COALESCE((SELECT snapshot.cutoff FROM snapshot), '-infinity'::timestamptz)
+    AND e.created_at < @before::timestamptz
+), 0))::bigint AS balance;
+
+-- name: ListStatementEntries :many
+SELECT
+  e.id,
+  e.account_id,
+  e.amount,
+  e.created_at,
+  e.transfer_id,
+  t.exchange_rate,
+  t.reverses_transfer_id,
+  t.memo,
+  c.id AS counterparty_account_id,
+  c.owner AS counterparty_owner,
+  c.currency AS counterparty_currency,
+  ba.reason AS adjustment_reason
+FROM entries e
+LEFT JOIN transfer t ON t.id = e.transfer_id
+LEFT JOIN accounts c ON c.id = CASE WHEN t.from_account_id = e.account_id THEN t.to_account_id ELSE t.from_account_id END
+LEFT JOIN balance_adjustments ba ON ba.entry_id = e.id
+WHERE e.account_id = @account_id
+  AND e.created_at >= @from_time
+  AND e.created_at < @to_time
+ORDER BY e.created_at, e.id;
+
+-- name: SumBalanceAsOf :one
+-- Like SumBalanceBefore, but entries created at as_of are included
+WITH snapshot AS (
+  SELECT bs.balance, bs.cutoff FROM balance_snapshots bs
+  WHERE bs.account_id = @account_id::bigint AND bs.cutoff <= @as_of::timestamptz
+  ORDER BY bs.cutoff DESC
+  LIMIT 1
+)
+SELECT (COALESCE((SELECT snapshot.balance FROM snapshot), 0) + COALESCE((
+  SELECT SUM(e.amount) FROM entries e
+  WHERE e.account_id = @account_id::bigint
+    AND e.created_at >= COALESCE((SELECT snapshot.cutoff FROM snapshot), '-infinity'::timestamptz)
+    AND e.created_at <= @as_of::timestamptz
+), 0))::bigint AS balance;
+
+-- name: ListOwnerBalancesAsOf :many
+SELECT
+  a.id AS account_id,
+  a.currency,
+  (COALESCE(s.balance, 0) + COALESCE((
+    SELECT SUM(e.amount) FROM entries e
+    WHERE e.account_id = a.id
+      AND e.created_at >= COALESCE(s.cutoff, '-infinity'::timestamptz)
+      AND e.created_at <= @as_of::timestamptz
+  ), 0))::bigint AS balance
+FROM accounts a
+LEFT JOIN LATERAL (
+  SELECT bs.balance, bs.cutoff FROM balance_snapshots bs
+  WHERE bs.account_id = a.id AND bs.cutoff <= @as_of::timestamptz
+  ORDER BY bs.cutoff DESC
+  LIMIT 1
+) s ON true
+WHERE a.owner = @owner AND a.created_at <= @as_of::timestamptz
+ORDER BY a.id;
+
+-- name: SealEntry :one
+UPDATE entries
+SET prev_hash = sqlc.arg(prev_hash), hash = sqlc.arg(hash)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListEntryChain :many
+-- Walks the chain of an account in the order it was written, with the transfer sealed into each entry
+SELECT e.id, e.account_id, e.amount, e.created_at, e.transfer_id, e.prev_hash, e.hash,
+  t.from_account_id AS transfer_from_account_id,
+  t.to_account_id AS transfer_to_account_id,
+  t.amount AS transfer_amount,
+  t.to_amount AS transfer_to_amount,
+  t.exchange_rate AS transfer_exchange_rate,
+  t.created_at AS transfer_created_at,
+  t.reverses_transfer_id AS transfer_reverses_transfer_id,
+  t.memo AS transfer_memo,
+  t.external_reference AS transfer_external_reference,
+  COALESCE(t.metadata, '{}')::text AS transfer_metadata
+FROM entries e
+LEFT JOIN transfer t ON t.id = e.transfer_id
+WHERE e.account_id = sqlc.arg(account_id) AND e.id > sqlc.arg(after_id)
+ORDER BY e.id
+LIMIT sqlc.arg(limit_count);
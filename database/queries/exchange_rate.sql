@@ -0,0 +1,25 @@
+-- name: UpsertExchangeRate :one
+INSERT INTO exchange_rates(
+    base_currency, quote_currency, rate, effective_at
+)VALUES(
+    $1, $2, $3, $4
+)
+ON CONFLICT (base_currency, quote_currency, effective_at)
+DO UPDATE SET rate = EXCLUDED.rate
+RETURNING *;
+
+-- name: GetExchangeRate :one
+-- Returns the rate of a pair in effect at a given time
+SELECT * FROM exchange_rates
+WHERE base_currency = sqlc.arg(base_currency)
+    AND quote_currency = sqlc.arg(quote_currency)
+    AND effective_at <= sqlc.arg(as_of)
+ORDER BY effective_at DESC
+LIMIT 1;
+
+-- name: ListExchangeRates :many
+SELECT * FROM exchange_rates
+WHERE base_currency = $1 AND quote_currency = $2
+ORDER BY effective_at DESC
+LIMIT $3
+OFFSET $4;
@@ -0,0 +1,14 @@
+-- name: CreateOverdraftLimitChange :one
+INSERT INTO overdraft_limit_changes(
+    account_id, previous_limit, new_limit, reason, changed_by
+)VALUES(
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: ListOverdraftLimitChanges :many
+SELECT * FROM overdraft_limit_changes
+WHERE account_id = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
@@ -0,0 +1,87 @@
+-- name: CountLedgerRows :one
+SELECT
+  (SELECT COUNT(*) FROM accounts)::bigint AS accounts,
+  (SELECT COUNT(*) FROM transfer)::bigint AS transfers,
+  (SELECT COUNT(*) FROM entries)::bigint AS entries;
+
+-- name: ListBalanceMismatches :many
+-- Accounts whose balance isn't the sum of their entries
+SELECT a.id AS account_id, a.balance, COALESCE(SUM(e.amount), 0)::bigint AS entries_balance
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id
+GROUP BY a.id
+HAVING a.balance <> COALESCE(SUM(e.amount), 0)
+ORDER BY a.id;
+
+-- name: ListTransferEntriesMismatches :many
+-- Transfers that don't have exactly a debit of amount on the source account
+-- and a credit of to_amount on the destination account
+SELECT
+  t.id AS transfer_id,
+  t.from_account_id,
+  t.to_account_id,
+  t.amount,
+  t.to_amount,
+  COUNT(e.id)::bigint AS entry_count,
+  COALESCE(SUM(e.amount) FILTER (WHERE e.account_id = t.from_account_id), 0)::bigint AS from_entries_amount,
+  COALESCE(SUM(e.amount) FILTER (WHERE e.account_id = t.to_account_id), 0)::bigint AS to_entries_amount
+FROM transfer t
+LEFT JOIN entries e ON e.transfer_id = t.id
+GROUP BY t.id
+HAVING COUNT(e.id) <> 2
+  OR COUNT(e.id) FILTER (WHERE e.account_id = t.from_account_id AND e.amount = -t.amount) <> 1
+  OR COUNT(e.id) FILTER (WHERE e.account_id = t.to_account_id AND e.amount = t.to_amount) <> 1
+ORDER BY t.id;
+
+-- name: ListOrphanEntries :many
+-- Entries that belong to neither a transfer nor a balance adjustment
+SELECT e.* FROM entries e
+WHERE e.transfer_id IS NULL
+  AND NOT EXISTS (SELECT 1 FROM balance_adjustments ba WHERE ba.entry_id = e.id)
+ORDER BY e.id;
+
+-- name: ListAdjustmentMismatches :many
+-- Balance adjustments whose entry is on another account or of another amount
+SELECT ba.id AS adjustment_id, ba.account_id, ba.entry_id, ba.amount, e.account_id AS entry_account_id, e.amount AS entry_amount
+FROM balance_adjustments ba
+JOIN entries e ON e.id = ba.entry_id
+WHERE e.account_id <> ba.account_id OR e.amount <> ba.amount
+ORDER BY ba.id;
+
+-- name: ListSnapshotMismatches :many
+-- Balance snapshots that no longer match the entries created before their cutoff
+SELECT bs.account_id, bs.snapshot_date, bs.balance, COALESCE(SUM(e.amount), 0)::bigint AS entries_balance
+FROM balance_snapshots bs
+LEFT JOIN entries e ON e.account_id = bs.account_id AND e.created_at < bs.cutoff
+GROUP BY bs.account_id, bs.snapshot_date
+HAVING bs.balance <> COALESCE(SUM(e.amount), 0)
+ORDER BY bs.account_id, bs.snapshot_date;
+
+-- name: CreateReconciliationRun :one
+INSERT INTO reconciliation_runs (
+  started_by, accounts_checked, transfers_checked, entries_checked, discrepancy_count, started_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: CreateReconciliationDiscrepancy :one
+INSERT INTO reconciliation_discrepancies (
+  run_id, kind, account_id, transfer_id, entry_id, expected, actual, detail
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: GetReconciliationRun :one
+SELECT * FROM reconciliation_runs
+WHERE id = $1 LIMIT 1;
+
+-- name: ListReconciliationRuns :many
+SELECT * FROM reconciliation_runs
+ORDER BY id DESC
+LIMIT $1
+OFFSET $2;
+
+-- name: ListReconciliationDiscrepancies :many
+SELECT * FROM reconciliation_discrepancies
+WHERE run_id = $1
+ORDER BY id;
@@ -6,9 +6,10 @@ INSERT INTO sessions(
         user_agent,
         client_ip,
         is_blocked,
-        expires_at
+        expires_at,
+        family_id
 )VALUES(
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8
 ) RETURNING *;
 
 -- name: GetSession :one
@@ -19,4 +20,60 @@ FROM
 WHERE
     id = $1
 LIMIT
-    1;
\ No newline at end of file
+    1;
+
+-- name: BlockSession :exec
+UPDATE sessions
+SET is_blocked = true
+WHERE id = $1;
+
+-- name: BlockUserSessions :exec
+UPDATE sessions
+SET is_blocked = true
+WHERE username = $1 AND is_blocked = false;
+
+
+-- name: MarkSessionRotated :one
+UPDATE sessions
+SET is_rotated = true
+WHERE id = $1 AND is_rotated = false
+RETURNING *;
+
+-- name: BlockSessionFamily :exec
+UPDATE sessions
+SET is_blocked = true
+WHERE family_id = $1;
+
+-- name: ListActiveSessions :many
+-- The sessions of username that can still be renewed, one per login: the last refresh token of each family,
+-- with the time of the login that started the family
+SELECT s.*, f.created_at AS signed_in_at
+FROM sessions s
+JOIN sessions f ON f.id = s.family_id
+WHERE s.username = sqlc.arg(username)
+  AND s.is_blocked = false
+  AND s.is_rotated = false
+  AND s.expires_at > sqlc.arg(now)
+ORDER BY s.created_at DESC;
+
+-- name: BlockUserSessionFamily :execrows
+UPDATE sessions
+SET is_blocked = true
+WHERE family_id = sqlc.arg(family_id) AND username = sqlc.arg(username) AND is_blocked = false;
+
+-- name: BlockOtherUserSessions :one
+-- Blocks every session of username except those of the family kept, and returns how many logins were revoked
+WITH blocked AS (
+  UPDATE sessions
+  SET is_blocked = true
+  WHERE username = sqlc.arg(username) AND family_id <> sqlc.arg(kept_family_id) AND is_blocked = false
+  RETURNING family_id
+)
+SELECT count(DISTINCT family_id) FROM blocked;
+
+-- name: IsSessionFamilyBlocked :one
+-- Tells whether the login the family descends from was revoked, by a logout, a revocation or a reused refresh token
+SELECT EXISTS(
+  SELECT 1 FROM sessions
+  WHERE family_id = $1 AND is_blocked = true
+);
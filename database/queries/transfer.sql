@@ -2,20 +2,82 @@
 INSERT INTO transfer (
   from_account_id,
   to_account_id,
-  amount
+  amount,
+  to_amount,
+  exchange_rate,
+  reverses_transfer_id,
+  memo,
+  external_reference,
+  metadata
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
 ) RETURNING *;
 
 -- name: GetTransfer :one
 SELECT * FROM transfer
 WHERE id = $1 LIMIT 1;
 
+-- name: GetTransferForUpdate :one
+SELECT * FROM transfer
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: SumTransferReversals :one
+-- How much of a transfer has been given back so far, amount in the currency of its destination
+-- and to_amount in the currency of its source
+SELECT
+  COALESCE(SUM(amount), 0)::bigint AS amount,
+  COALESCE(SUM(to_amount), 0)::bigint AS to_amount
+FROM transfer
+WHERE reverses_transfer_id = $1;
+
 -- name: ListTransfers :many
+-- The filters are optional: memo matches as a case insensitive LIKE pattern,
+-- external_reference exactly, and metadata matches transfers whose metadata contains it
 SELECT * FROM transfer
 WHERE 
-    from_account_id = $1 OR
-    to_account_id = $2
+    (from_account_id = sqlc.arg(from_account_id) OR
+    to_account_id = sqlc.arg(to_account_id))
+    AND (sqlc.narg(memo)::varchar IS NULL OR memo ILIKE sqlc.narg(memo))
+    AND (sqlc.narg(external_reference)::varchar IS NULL OR external_reference = sqlc.narg(external_reference))
+    AND (sqlc.narg(metadata)::text IS NULL OR metadata @> sqlc.narg(metadata)::text::jsonb)
 ORDER BY id
-LIMIT $3
-OFFSET $4;
\ No newline at end of file
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: SearchTransfers :many
+-- Transfers sent or received by accounts of owner, newest first. Every filter is optional.
+-- A transfer matches when its sending side is owned and matches the filters, or its receiving side is:
+-- amounts and currency are those of that side and the counterparty is the other account.
+-- Pages continue strictly after the (cursor_created_at, cursor_id) of the last row of the previous page
+SELECT t.* FROM transfer t
+JOIN accounts fa ON fa.id = t.from_account_id
+JOIN accounts ta ON ta.id = t.to_account_id
+WHERE (
+    (
+      sqlc.narg(direction)::varchar IS DISTINCT FROM 'received'
+      AND fa.owner = sqlc.arg(owner)
+      AND (sqlc.narg(account_id)::bigint IS NULL OR t.from_account_id = sqlc.narg(account_id)::bigint)
+      AND (sqlc.narg(counterparty_account_id)::bigint IS NULL OR t.to_account_id = sqlc.narg(counterparty_account_id)::bigint)
+      AND (sqlc.narg(min_amount)::bigint IS NULL OR t.amount >= sqlc.narg(min_amount)::bigint)
+      AND (sqlc.narg(max_amount)::bigint IS NULL OR t.amount <= sqlc.narg(max_amount)::bigint)
+      AND (sqlc.narg(currency)::varchar IS NULL OR fa.currency = sqlc.narg(currency)::varchar)
+    ) OR (
+      sqlc.narg(direction)::varchar IS DISTINCT FROM 'sent'
+      AND ta.owner = sqlc.arg(owner)
+      AND (sqlc.narg(account_id)::bigint IS NULL OR t.to_account_id = sqlc.narg(account_id)::bigint)
+      AND (sqlc.narg(counterparty_account_id)::bigint IS NULL OR t.from_account_id = sqlc.narg(counterparty_account_id)::bigint)
+      AND (sqlc.narg(min_amount)::bigint IS NULL OR t.to_amount >= sqlc.narg(min_amount)::bigint)
+      AND (sqlc.narg(max_amount)::bigint IS NULL OR t.to_amount <= sqlc.narg(max_amount)::bigint)
+      AND (sqlc.narg(currency)::varchar IS NULL OR ta.currency = sqlc.narg(currency)::varchar)
+    )
+  )
+  AND (sqlc.narg(from_time)::timestamptz IS NULL OR t.created_at >= sqlc.narg(from_time)::timestamptz)
+  AND (sqlc.narg(to_time)::timestamptz IS NULL OR t.created_at < sqlc.narg(to_time)::timestamptz)
+  AND (sqlc.narg(memo)::varchar IS NULL OR t.memo ILIKE sqlc.narg(memo)::varchar)
+  AND (
+    sqlc.narg(cursor_created_at)::timestamptz IS NULL
+    OR (t.created_at, t.id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::bigint)
+  )
+ORDER BY t.created_at DESC, t.id DESC
+LIMIT sqlc.arg(limit_count);
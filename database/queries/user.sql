@@ -11,4 +11,10 @@ RETURNING *;
 
 -- name: GetUser :one
 SELECT * FROM users
-WHERE username = $1 LIMIT 1;
\ No newline at end of file
+WHERE username = $1 LIMIT 1;
+
+-- name: UpdateUserRole :one
+UPDATE users
+SET role = sqlc.arg(role)
+WHERE username = sqlc.arg(username)
+RETURNING *;
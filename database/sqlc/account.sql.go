@@ -13,7 +13,7 @@ const addAccountBalance = `-- name: AddAccountBalance :one
 UPDATE accounts
 SET balance = balance + $1
 WHERE id = $2
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, closed_at, status, entry_hash
 `
 
 type AddAccountBalanceParams struct {
@@ -30,6 +30,34 @@ func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalancePa
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.ClosedAt,
+		&i.Status,
+		&i.EntryHash,
+	)
+	return i, err
+}
+
+const closeAccount = `-- name: CloseAccount :one
+UPDATE accounts
+SET status = 'closed', closed_at = now()
+WHERE id = $1
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, closed_at, status, entry_hash
+`
+
+func (q *Queries) CloseAccount(ctx context.Context, id int64) (Account, error) {
+	row := q.db.QueryRowContext(ctx, closeAccount, id)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.ClosedAt,
+		&i.Status,
+		&i.EntryHash,
 	)
 	return i, err
 }
@@ -40,7 +68,7 @@ INSERT INTO accounts(
 )VALUES(
     $1, $2, $3
 )
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, closed_at, status, entry_hash
 `
 
 type CreateAccountParams struct {
@@ -58,22 +86,16 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.ClosedAt,
+		&i.Status,
+		&i.EntryHash,
 	)
 	return i, err
 }
 
-const deleteAccount = `-- name: DeleteAccount :exec
-DELETE FROM accounts
-WHERE id = $1
-`
-
-func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteAccount, id)
-	return err
-}
-
 const getAccount = `-- name: GetAccount :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, overdraft_limit, closed_at, status, entry_hash FROM accounts
 WHERE id = $1 LIMIT 1
 `
 
@@ -86,12 +108,16 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.ClosedAt,
+		&i.Status,
+		&i.EntryHash,
 	)
 	return i, err
 }
 
 const getAccountForUpdated = `-- name: GetAccountForUpdated :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, overdraft_limit, closed_at, status, entry_hash FROM accounts
 WHERE id = $1 LIMIT 1
 FOR NO KEY UPDATE
 `
@@ -105,12 +131,16 @@ func (q *Queries) GetAccountForUpdated(ctx context.Context, id int64) (Account,
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.ClosedAt,
+		&i.Status,
+		&i.EntryHash,
 	)
 	return i, err
 }
 
 const listAccounts = `-- name: ListAccounts :many
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, overdraft_limit, closed_at, status, entry_hash FROM accounts
 WHERE owner = $1 
 ORDER BY id
 LIMIT $2
@@ -138,6 +168,10 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.OverdraftLimit,
+			&i.ClosedAt,
+			&i.Status,
+			&i.EntryHash,
 		); err != nil {
 			return nil, err
 		}
@@ -152,20 +186,78 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 	return items, nil
 }
 
-const updateAccount = `-- name: UpdateAccount :one
+const updateAccountEntryHash = `-- name: UpdateAccountEntryHash :one
 UPDATE accounts
-SET balance = $2
-WHERE id = $1
-RETURNING id, owner, balance, currency, created_at
+SET entry_hash = $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, closed_at, status, entry_hash
 `
 
-type UpdateAccountParams struct {
-	ID      int64
-	Balance int64
+type UpdateAccountEntryHashParams struct {
+	EntryHash []byte
+	ID        int64
+}
+
+func (q *Queries) UpdateAccountEntryHash(ctx context.Context, arg UpdateAccountEntryHashParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountEntryHash, arg.EntryHash, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.ClosedAt,
+		&i.Status,
+		&i.EntryHash,
+	)
+	return i, err
+}
+
+const updateAccountOverdraftLimit = `-- name: UpdateAccountOverdraftLimit :one
+UPDATE accounts
+SET overdraft_limit = $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, closed_at, status, entry_hash
+`
+
+type UpdateAccountOverdraftLimitParams struct {
+	OverdraftLimit int64
+	ID             int64
+}
+
+func (q *Queries) UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountOverdraftLimit, arg.OverdraftLimit, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.ClosedAt,
+		&i.Status,
+		&i.EntryHash,
+	)
+	return i, err
+}
+
+const updateAccountStatus = `-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET status = $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, closed_at, status, entry_hash
+`
+
+type UpdateAccountStatusParams struct {
+	Status string
+	ID     int64
 }
 
-func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, updateAccount, arg.ID, arg.Balance)
+func (q *Queries) UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountStatus, arg.Status, arg.ID)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -173,6 +265,10 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.ClosedAt,
+		&i.Status,
+		&i.EntryHash,
 	)
 	return i, err
 }
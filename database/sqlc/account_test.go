@@ -2,20 +2,35 @@ package sqlc
 
 import (
 	"context"
-	"database/sql"
 	"testing"
 	"time"
 
+	"github.com/October-9th/simple-bank/money"
 	"github.com/October-9th/simple-bank/util"
 	"github.com/stretchr/testify/require"
 )
 
 func CreateRandomAccount(t *testing.T) Account {
+	return CreateRandomAccountWithCurrency(t, util.RandomCurrency())
+}
+
+// CreateRandomAccountPair creates two accounts sharing a currency, so money moves between them without conversion
+func CreateRandomAccountPair(t *testing.T) (Account, Account) {
+	currency := util.RandomCurrency()
+	return CreateRandomAccountWithCurrency(t, currency), CreateRandomAccountWithCurrency(t, currency)
+}
+
+// amountOf returns minor units of account's currency
+func amountOf(account Account, minor int64) money.Amount {
+	return money.Amount{Minor: minor, Currency: account.Currency}
+}
+
+func CreateRandomAccountWithCurrency(t *testing.T, currency string) Account {
 	user := CreateRandomUser(t)
 	arg := CreateAccountParams{
 		Owner:    user.Username,
 		Balance:  util.RandomMoney(),
-		Currency: util.RandomCurrency(),
+		Currency: currency,
 	}
 	account, err := testQueries.CreateAccount(context.Background(), arg)
 	require.NoError(t, err)
@@ -65,30 +80,169 @@ func TestListAccounts(t *testing.T) {
 	}
 }
 
-func TestDeleteAccount(t *testing.T) {
-	account_test := CreateRandomAccount(t)
-	err := testQueries.DeleteAccount(context.Background(), account_test.ID)
+func TestAdjustBalanceTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := CreateRandomAccount(t)
+	admin := CreateRandomUser(t)
+
+	arg := AdjustBalanceTxParams{
+		AccountID:  account.ID,
+		Amount:     amountOf(account, -account.Balance),
+		Reason:     util.RandomString(10),
+		AdjustedBy: admin.Username,
+	}
+	result, err := store.AdjustBalanceTx(context.Background(), arg)
 	require.NoError(t, err)
 
-	account_after_deleted, err := testQueries.GetAccount(context.Background(), account_test.ID)
-	require.Error(t, err)
-	require.Error(t, err, sql.ErrNoRows.Error())
-	require.Empty(t, account_after_deleted)
+	require.Equal(t, int64(0), result.Account.Balance)
+	require.Equal(t, account.ID, result.Entry.AccountID)
+	require.Equal(t, arg.Amount.Minor, result.Entry.Amount)
+	require.Equal(t, result.Entry.ID, result.Adjustment.EntryID)
+	require.Equal(t, arg.Reason, result.Adjustment.Reason)
+	require.Equal(t, admin.Username, result.Adjustment.AdjustedBy)
+
+	// Going below the overdraft limit rolls the adjustment back
+	arg.Amount = amountOf(account, -1)
+	_, err = store.AdjustBalanceTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// An amount in another currency rolls the adjustment back
+	arg.Amount = money.Amount{Minor: 1, Currency: util.USD}
+	if account.Currency == util.USD {
+		arg.Amount.Currency = util.EUR
+	}
+	_, err = store.AdjustBalanceTx(context.Background(), arg)
+	require.ErrorIs(t, err, money.ErrCurrencyMismatch)
+
+	adjustments, err := store.ListBalanceAdjustments(context.Background(), ListBalanceAdjustmentsParams{
+		AccountID: account.ID,
+		Limit:     5,
+		Offset:    0,
+	})
+	require.NoError(t, err)
+	require.Len(t, adjustments, 1)
 }
 
-func TestUpdateAccount(t *testing.T) {
-	account_test := CreateRandomAccount(t)
-	arg := UpdateAccountParams{
-		ID:      account_test.ID,
-		Balance: util.RandomMoney(),
+func TestUpdateOverdraftLimitTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := CreateRandomAccount(t)
+	banker := CreateRandomUser(t)
+
+	arg := UpdateOverdraftLimitTxParams{
+		AccountID:      account.ID,
+		OverdraftLimit: 500,
+		Reason:         util.RandomString(10),
+		ChangedBy:      banker.Username,
 	}
-	updatedAccount, err := testQueries.UpdateAccount(context.Background(), arg)
+	result, err := store.UpdateOverdraftLimitTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(500), result.Account.OverdraftLimit)
+	require.Equal(t, account.OverdraftLimit, result.Change.PreviousLimit)
+	require.Equal(t, int64(500), result.Change.NewLimit)
+	require.Equal(t, banker.Username, result.Change.ChangedBy)
+
+	arg.OverdraftLimit = -1
+	_, err = store.UpdateOverdraftLimitTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrInvalidAmount)
+
+	changes, err := store.ListOverdraftLimitChanges(context.Background(), ListOverdraftLimitChangesParams{
+		AccountID: account.ID,
+		Limit:     5,
+		Offset:    0,
+	})
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+}
+
+func TestCloseAccountTx(t *testing.T) {
+	store := NewStore(testDB)
+	account_1, account_2 := CreateRandomAccountPair(t)
+
+	// The balance has to be zero first
+	_, err := store.CloseAccountTx(context.Background(), account_1.ID)
+	require.ErrorIs(t, err, ErrNonZeroBalance)
+
+	_, err = store.AdjustBalanceTx(context.Background(), AdjustBalanceTxParams{
+		AccountID:  account_1.ID,
+		Amount:     amountOf(account_1, -account_1.Balance),
+		Reason:     util.RandomString(10),
+		AdjustedBy: account_1.Owner,
+	})
+	require.NoError(t, err)
+
+	closed, err := store.CloseAccountTx(context.Background(), account_1.ID)
+	require.NoError(t, err)
+	require.True(t, closed.ClosedAt.Valid)
+	require.Equal(t, util.AccountClosed, closed.Status)
+
+	_, err = store.CloseAccountTx(context.Background(), account_1.ID)
+	require.ErrorIs(t, err, ErrAccountClosed)
+
+	// Closed accounts keep their history but take no more transfers in either direction
+	entries, err := store.ListEntries(context.Background(), ListEntriesParams{
+		AccountID: account_1.ID,
+		Limit:     5,
+		Offset:    0,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account_2.ID,
+		ToAccountID:   account_1.ID,
+		Amount:        amountOf(account_2, 1),
+	})
+	require.ErrorIs(t, err, ErrAccountClosed)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account_1.ID,
+		ToAccountID:   account_2.ID,
+		Amount:        amountOf(account_1, 1),
+	})
+	require.ErrorIs(t, err, ErrAccountClosed)
+}
+
+func TestFreezeAccountTx(t *testing.T) {
+	store := NewStore(testDB)
+	account_1, account_2 := CreateRandomAccountPair(t)
+	require.Equal(t, util.AccountActive, account_1.Status)
+
+	frozen, err := store.FreezeAccountTx(context.Background(), account_1.ID)
 	require.NoError(t, err)
-	require.Equal(t, updatedAccount.ID, account_test.ID)
-	require.Equal(t, updatedAccount.Owner, account_test.Owner)
-	require.Equal(t, arg.Balance, updatedAccount.Balance)
-	require.Equal(t, updatedAccount.Currency, account_test.Currency)
-	require.NotEmpty(t, updatedAccount)
+	require.Equal(t, util.AccountFrozen, frozen.Status)
+
+	_, err = store.FreezeAccountTx(context.Background(), account_1.ID)
+	require.ErrorIs(t, err, ErrInvalidStatusTransition)
+
+	// A frozen account can't be closed without being unfrozen first
+	_, err = store.CloseAccountTx(context.Background(), account_1.ID)
+	require.ErrorIs(t, err, ErrInvalidStatusTransition)
 
-	require.WithinDuration(t, updatedAccount.CreatedAt, account_test.CreatedAt, time.Second)
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account_1.ID,
+		ToAccountID:   account_2.ID,
+		Amount:        amountOf(account_1, 1),
+	})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account_2.ID,
+		ToAccountID:   account_1.ID,
+		Amount:        amountOf(account_2, 1),
+	})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+
+	active, err := store.UnfreezeAccountTx(context.Background(), account_1.ID)
+	require.NoError(t, err)
+	require.Equal(t, util.AccountActive, active.Status)
+
+	_, err = store.UnfreezeAccountTx(context.Background(), account_1.ID)
+	require.ErrorIs(t, err, ErrInvalidStatusTransition)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account_1.ID,
+		ToAccountID:   account_2.ID,
+		Amount:        amountOf(account_1, 1),
+	})
+	require.NoError(t, err)
 }
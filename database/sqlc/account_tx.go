@@ -0,0 +1,231 @@
+package sqlc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/October-9th/simple-bank/money"
+	"github.com/October-9th/simple-bank/util"
+)
+
+var (
+	// ErrAccountClosed is returned when money is moved into or out of a closed account,
+	// or when an account is closed twice
+	ErrAccountClosed = errors.New("account is closed")
+	// ErrAccountFrozen is returned when money is moved into or out of a frozen account
+	ErrAccountFrozen = errors.New("account is frozen")
+	// ErrNonZeroBalance is returned by CloseAccountTx when the account still holds money
+	ErrNonZeroBalance = errors.New("account balance must be zero to close it")
+	// ErrInvalidStatusTransition is returned when an account can't move from its current status to the requested one
+	ErrInvalidStatusTransition = errors.New("invalid account status transition")
+)
+
+// checkAccountActive returns an error unless the account may send or receive money
+func checkAccountActive(account Account) error {
+	switch account.Status {
+	case util.AccountActive:
+		return nil
+	case util.AccountFrozen:
+		return ErrAccountFrozen
+	case util.AccountClosed:
+		return ErrAccountClosed
+	}
+	return fmt.Errorf("account has unknown status %q", account.Status)
+}
+
+// checkAccountStatusTransition returns an error unless the account may move to status
+func checkAccountStatusTransition(account Account, status string) error {
+	if util.CanTransitionAccountStatus(account.Status, status) {
+		return nil
+	}
+	if account.Status == util.AccountClosed {
+		return ErrAccountClosed
+	}
+	return fmt.Errorf("%w: %s to %s", ErrInvalidStatusTransition, account.Status, status)
+}
+
+// CloseAccountTx closes an active account. The account row is locked first,
+// so a concurrent transfer either completes before the balance check or sees the account closed
+func (s *SQLStore) CloseAccountTx(ctx context.Context, accountID int64) (Account, error) {
+	var account Account
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		account, err = q.GetAccountForUpdated(ctx, accountID)
+		if err != nil {
+			return err
+		}
+		if err := checkAccountStatusTransition(account, util.AccountClosed); err != nil {
+			return err
+		}
+		if account.Balance != 0 {
+			return ErrNonZeroBalance
+		}
+
+		account, err = q.CloseAccount(ctx, accountID)
+		return err
+	})
+	return account, err
+}
+
+// FreezeAccountTx stops an active account from sending or receiving money
+func (s *SQLStore) FreezeAccountTx(ctx context.Context, accountID int64) (Account, error) {
+	return s.changeAccountStatus(ctx, accountID, util.AccountFrozen)
+}
+
+// UnfreezeAccountTx makes a frozen account active again
+func (s *SQLStore) UnfreezeAccountTx(ctx context.Context, accountID int64) (Account, error) {
+	return s.changeAccountStatus(ctx, accountID, util.AccountActive)
+}
+
+func (s *SQLStore) changeAccountStatus(ctx context.Context, accountID int64, status string) (Account, error) {
+	var account Account
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		account, err = q.GetAccountForUpdated(ctx, accountID)
+		if err != nil {
+			return err
+		}
+		if err := checkAccountStatusTransition(account, status); err != nil {
+			return err
+		}
+
+		account, err = q.UpdateAccountStatus(ctx, UpdateAccountStatusParams{
+			ID:     accountID,
+			Status: status,
+		})
+		return err
+	})
+	return account, err
+}
+
+// AdjustBalanceTxParams contains the input parameters of the adjust balance transaction
+type AdjustBalanceTxParams struct {
+	AccountID int64 `json:"account_id"`
+	// Amount is added to the balance, a negative amount takes money out. It has to be in the account currency
+	Amount     money.Amount `json:"amount"`
+	Reason     string       `json:"reason"`
+	AdjustedBy string       `json:"adjusted_by"`
+}
+
+// AdjustBalanceTxResult is the result of the adjust balance transaction
+type AdjustBalanceTxResult struct {
+	Adjustment BalanceAdjustment `json:"adjustment"` // the audit record of the adjustment
+	Entry      Entry             `json:"entry"`      // the entry which records the balance change
+	Account    Account           `json:"account"`    // the account after its balance has been updated
+}
+
+// AdjustBalanceTx corrects an account's balance outside of a transfer.
+// The change is recorded as an entry, like any other balance change, and audited with who made it and why
+func (s *SQLStore) AdjustBalanceTx(ctx context.Context, arg AdjustBalanceTxParams) (AdjustBalanceTxResult, error) {
+	var txResult AdjustBalanceTxResult
+
+	if arg.Amount.IsZero() {
+		return txResult, fmt.Errorf("%w: adjustment amount must not be zero", ErrInvalidAmount)
+	}
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		// Entries of the day can't be snapshotted while this transaction may still write some
+		if err := q.LockLedgerDay(ctx); err != nil {
+			return err
+		}
+
+		var err error
+
+		txResult.Account, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+			ID:     arg.AccountID,
+			Amount: arg.Amount.Minor,
+		})
+		if err != nil {
+			return asOverflow(err)
+		}
+		if txResult.Account.Currency != arg.Amount.Currency {
+			return fmt.Errorf("%w: amount is in %s, account [%d] holds %s",
+				money.ErrCurrencyMismatch, arg.Amount.Currency, txResult.Account.ID, txResult.Account.Currency)
+		}
+
+		// The balance update locked the account, so the entry is sealed onto the end of its chain
+		txResult.Entry, txResult.Account, err = s.appendEntry(ctx, q, txResult.Account, CreateEntryParams{
+			AccountID: arg.AccountID,
+			Amount:    arg.Amount.Minor,
+		}, nil)
+		if err != nil {
+			return err
+		}
+
+		// Frozen accounts may still be corrected while they are under investigation
+		if txResult.Account.Status == util.AccountClosed {
+			return ErrAccountClosed
+		}
+		if txResult.Account.Balance < -txResult.Account.OverdraftLimit {
+			return ErrInsufficientFunds
+		}
+
+		txResult.Adjustment, err = q.CreateBalanceAdjustment(ctx, CreateBalanceAdjustmentParams{
+			AccountID:  arg.AccountID,
+			EntryID:    txResult.Entry.ID,
+			Amount:     arg.Amount.Minor,
+			Reason:     arg.Reason,
+			AdjustedBy: arg.AdjustedBy,
+		})
+		return err
+	})
+	return txResult, err
+}
+
+// UpdateOverdraftLimitTxParams contains the input parameters of the update overdraft limit transaction
+type UpdateOverdraftLimitTxParams struct {
+	AccountID int64 `json:"account_id"`
+	// OverdraftLimit is how far below zero the balance may go, in minor units of the account currency
+	OverdraftLimit int64  `json:"overdraft_limit"`
+	Reason         string `json:"reason"`
+	ChangedBy      string `json:"changed_by"`
+}
+
+// UpdateOverdraftLimitTxResult is the result of the update overdraft limit transaction
+type UpdateOverdraftLimitTxResult struct {
+	Change  OverdraftLimitChange `json:"change"`  // the audit record of the change
+	Account Account              `json:"account"` // the account with its new limit
+}
+
+// UpdateOverdraftLimitTx sets how far below zero an account's balance may go, audited with the previous limit,
+// who changed it and why. Lowering the limit under the current debt is allowed, the account then can't send money until it is repaid
+func (s *SQLStore) UpdateOverdraftLimitTx(ctx context.Context, arg UpdateOverdraftLimitTxParams) (UpdateOverdraftLimitTxResult, error) {
+	var txResult UpdateOverdraftLimitTxResult
+
+	if arg.OverdraftLimit < 0 {
+		return txResult, fmt.Errorf("%w: overdraft limit must not be negative", ErrInvalidAmount)
+	}
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdated(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+		if account.Status == util.AccountClosed {
+			return ErrAccountClosed
+		}
+
+		txResult.Account, err = q.UpdateAccountOverdraftLimit(ctx, UpdateAccountOverdraftLimitParams{
+			ID:             arg.AccountID,
+			OverdraftLimit: arg.OverdraftLimit,
+		})
+		if err != nil {
+			return err
+		}
+
+		txResult.Change, err = q.CreateOverdraftLimitChange(ctx, CreateOverdraftLimitChangeParams{
+			AccountID:     arg.AccountID,
+			PreviousLimit: account.OverdraftLimit,
+			NewLimit:      arg.OverdraftLimit,
+			Reason:        arg.Reason,
+			ChangedBy:     arg.ChangedBy,
+		})
+		return err
+	})
+	return txResult, err
+}
@@ -0,0 +1,157 @@
+package sqlc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ErrDayNotSettled is returned by SnapshotBalances while transactions writing entries of the day are still open.
+// Their entries are stamped before the cutoff, a snapshot taken now would miss them for good
+var ErrDayNotSettled = errors.New("transactions of the day are still open")
+
+// AccountBalance is the balance of an account at a point in time
+type AccountBalance struct {
+	AccountID int64     `json:"account_id"`
+	Currency  string    `json:"currency"`
+	Balance   int64     `json:"balance"`
+	AsOf      time.Time `json:"as_of"`
+}
+
+// GetBalanceAsOfParams contains the input parameters of GetBalanceAsOf
+type GetBalanceAsOfParams struct {
+	AccountID int64     `json:"account_id"`
+	AsOf      time.Time `json:"as_of"`
+}
+
+// GetBalanceAsOf computes the balance of an account at AsOf from its latest balance snapshot and the entries after it,
+// entries created at AsOf are included.
+// It returns sql.ErrNoRows when the account doesn't exist
+func (s *SQLStore) GetBalanceAsOf(ctx context.Context, arg GetBalanceAsOfParams) (AccountBalance, error) {
+	account, err := s.GetAccount(ctx, arg.AccountID)
+	if err != nil {
+		return AccountBalance{}, err
+	}
+
+	balance, err := s.SumBalanceAsOf(ctx, SumBalanceAsOfParams{
+		AccountID: arg.AccountID,
+		AsOf:      arg.AsOf,
+	})
+	if err != nil {
+		return AccountBalance{}, err
+	}
+
+	return AccountBalance{
+		AccountID: account.ID,
+		Currency:  account.Currency,
+		Balance:   balance,
+		AsOf:      arg.AsOf,
+	}, nil
+}
+
+// ListBalancesAsOfParams contains the input parameters of ListBalancesAsOf
+type ListBalancesAsOfParams struct {
+	Owner string    `json:"owner"`
+	AsOf  time.Time `json:"as_of"`
+}
+
+// ListBalancesAsOf computes the balance at AsOf of every account of Owner that existed by then, the same way as GetBalanceAsOf
+func (s *SQLStore) ListBalancesAsOf(ctx context.Context, arg ListBalancesAsOfParams) ([]AccountBalance, error) {
+	rows, err := s.ListOwnerBalancesAsOf(ctx, ListOwnerBalancesAsOfParams{
+		Owner: arg.Owner,
+		AsOf:  arg.AsOf,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make([]AccountBalance, 0, len(rows))
+	for _, row := range rows {
+		balances = append(balances, AccountBalance{
+			AccountID: row.AccountID,
+			Currency:  row.Currency,
+			Balance:   row.Balance,
+			AsOf:      arg.AsOf,
+		})
+	}
+	return balances, nil
+}
+
+// defaultSnapshotBatchSize is the number of accounts SnapshotBalances snapshots per statement
+const defaultSnapshotBatchSize = 1000
+
+// defaultSettleTimeout is how long SnapshotBalances waits for the transactions of the day by default
+const defaultSettleTimeout = 30 * time.Second
+
+// SnapshotBalancesParams contains the input parameters of SnapshotBalances
+type SnapshotBalancesParams struct {
+	// Date is the UTC day to snapshot, its time of day is ignored
+	Date      time.Time `json:"date"`
+	BatchSize int32     `json:"batch_size"`
+	// SettleTimeout bounds the wait for the transactions still writing entries of the day
+	SettleTimeout time.Duration `json:"settle_timeout"`
+}
+
+// SnapshotDay returns the UTC day date falls on and the cutoff ending it
+func SnapshotDay(date time.Time) (day, cutoff time.Time) {
+	year, month, dayOfMonth := date.Date()
+	day = time.Date(year, month, dayOfMonth, 0, 0, 0, 0, time.UTC)
+	return day, day.AddDate(0, 0, 1)
+}
+
+// SnapshotBalances writes the end of day balance of every account that existed by the end of Date.
+// Each batch commits on its own and accounts that already have a snapshot of the day are skipped,
+// so running it twice is harmless and a run that stopped half way picks up where it left off.
+// It waits until every transaction writing entries of Date is over, and returns ErrDayNotSettled without writing anything
+// when some are still open after SettleTimeout. It returns the number of snapshots written by this run
+func (s *SQLStore) SnapshotBalances(ctx context.Context, arg SnapshotBalancesParams) (int64, error) {
+	batchSize := arg.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultSnapshotBatchSize
+	}
+	day, cutoff := SnapshotDay(arg.Date)
+
+	settleTimeout := arg.SettleTimeout
+	if settleTimeout <= 0 {
+		settleTimeout = defaultSettleTimeout
+	}
+	if err := s.waitLedgerDay(ctx, day, settleTimeout); err != nil {
+		return 0, err
+	}
+
+	var written int64
+	for {
+		accountIDs, err := s.CreateBalanceSnapshots(ctx, CreateBalanceSnapshotsParams{
+			SnapshotDate: day,
+			Cutoff:       cutoff,
+			BatchSize:    batchSize,
+		})
+		if err != nil {
+			return written, err
+		}
+		written += int64(len(accountIDs))
+		if len(accountIDs) < int(batchSize) {
+			return written, nil
+		}
+	}
+}
+
+// waitLedgerDay waits until no transaction writing entries of day is open. Those transactions hold the lock of their day
+// shared, taking it exclusively only waits for them: the transactions of later days use another lock and go on.
+// Once the day is over no new transaction writes entries of it, when the open ones are done the day can't change
+func (s *SQLStore) waitLedgerDay(ctx context.Context, day time.Time, timeout time.Duration) error {
+	err := s.execTx(ctx, func(q *Queries) error {
+		if err := q.SetLockTimeout(ctx, fmt.Sprintf("%dms", timeout.Milliseconds())); err != nil {
+			return err
+		}
+		return q.WaitLedgerDay(ctx, int32(day.Unix()/(24*60*60)))
+	})
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code.Name() == "lock_not_available" {
+		return fmt.Errorf("%w: still open after %s", ErrDayNotSettled, timeout)
+	}
+	return err
+}
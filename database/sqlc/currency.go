@@ -0,0 +1,28 @@
+package sqlc
+
+import (
+	"context"
+
+	"github.com/October-9th/simple-bank/util"
+)
+
+// LoadCurrencies replaces the currency registry of the util package with the currencies table,
+// so validators and formatting follow the database
+func LoadCurrencies(ctx context.Context, q Querier) error {
+	rows, err := q.ListCurrencies(ctx)
+	if err != nil {
+		return err
+	}
+
+	currencies := make([]util.Currency, len(rows))
+	for i, row := range rows {
+		currencies[i] = util.Currency{
+			Code:        row.Code,
+			NumericCode: row.NumericCode,
+			MinorUnit:   row.MinorUnit,
+			Enabled:     row.Enabled,
+		}
+	}
+	util.SetCurrencies(currencies)
+	return nil
+}
@@ -0,0 +1,43 @@
+package sqlc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/October-9th/simple-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCurrency(t *testing.T) {
+	testCases := []struct {
+		code        string
+		numericCode string
+		minorUnit   int32
+	}{
+		{util.USD, "840", 2},
+		{util.JPY, "392", 0},
+		{util.KWD, "414", 3},
+	}
+
+	for _, tc := range testCases {
+		currency, err := testQueries.GetCurrency(context.Background(), tc.code)
+		require.NoError(t, err)
+		require.Equal(t, tc.numericCode, currency.NumericCode)
+		require.Equal(t, tc.minorUnit, currency.MinorUnit)
+		require.True(t, currency.Enabled)
+	}
+}
+
+func TestLoadCurrencies(t *testing.T) {
+	currencies, err := testQueries.ListCurrencies(context.Background())
+	require.NoError(t, err)
+	require.Greater(t, len(currencies), 150)
+
+	require.NoError(t, LoadCurrencies(context.Background(), testQueries))
+
+	gbp, ok := util.LookupCurrency("GBP")
+	require.True(t, ok)
+	require.False(t, gbp.Enabled)
+	require.False(t, util.IsSupportedCurrency("GBP"))
+	require.True(t, util.IsSupportedCurrency(util.KWD))
+}
@@ -7,36 +7,43 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 const createEntry = `-- name: CreateEntry :one
 INSERT INTO entries (
   account_id,
-  amount
+  amount,
+  transfer_id
 ) VALUES (
-  $1, $2
-) RETURNING id, account_id, amount, created_at
+  $1, $2, $3
+) RETURNING id, account_id, amount, created_at, transfer_id, prev_hash, hash
 `
 
 type CreateEntryParams struct {
-	AccountID int64
-	Amount    int64
+	AccountID  int64
+	Amount     int64
+	TransferID sql.NullInt64
 }
 
 func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
-	row := q.db.QueryRowContext(ctx, createEntry, arg.AccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createEntry, arg.AccountID, arg.Amount, arg.TransferID)
 	var i Entry
 	err := row.Scan(
 		&i.ID,
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
+		&i.PrevHash,
+		&i.Hash,
 	)
 	return i, err
 }
 
 const getEntry = `-- name: GetEntry :one
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, transfer_id, prev_hash, hash FROM entries
 WHERE id = $1 LIMIT 1
 `
 
@@ -48,12 +55,15 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
+		&i.PrevHash,
+		&i.Hash,
 	)
 	return i, err
 }
 
 const listEntries = `-- name: ListEntries :many
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, transfer_id, prev_hash, hash FROM entries
 WHERE account_id = $1
 ORDER BY id
 LIMIT $2
@@ -80,6 +90,9 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 			&i.AccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.TransferID,
+			&i.PrevHash,
+			&i.Hash,
 		); err != nil {
 			return nil, err
 		}
@@ -93,3 +106,310 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 	}
 	return items, nil
 }
+
+const listEntryChain = `-- name: ListEntryChain :many
+SELECT e.id, e.account_id, e.amount, e.created_at, e.transfer_id, e.prev_hash, e.hash,
+  t.from_account_id AS transfer_from_account_id,
+  t.to_account_id AS transfer_to_account_id,
+  t.amount AS transfer_amount,
+  t.to_amount AS transfer_to_amount,
+  t.exchange_rate AS transfer_exchange_rate,
+  t.created_at AS transfer_created_at,
+  t.reverses_transfer_id AS transfer_reverses_transfer_id,
+  t.memo AS transfer_memo,
+  t.external_reference AS transfer_external_reference,
+  COALESCE(t.metadata, '{}')::text AS transfer_metadata
+FROM entries e
+LEFT JOIN transfer t ON t.id = e.transfer_id
+WHERE e.account_id = $1 AND e.id > $2
+ORDER BY e.id
+LIMIT $3
+`
+
+type ListEntryChainParams struct {
+	AccountID  int64
+	AfterID    int64
+	LimitCount int32
+}
+
+type ListEntryChainRow struct {
+	ID                         int64
+	AccountID                  int64
+	Amount                     int64
+	CreatedAt                  time.Time
+	TransferID                 sql.NullInt64
+	PrevHash                   []byte
+	Hash                       []byte
+	TransferFromAccountID      sql.NullInt64
+	TransferToAccountID        sql.NullInt64
+	TransferAmount             sql.NullInt64
+	TransferToAmount           sql.NullInt64
+	TransferExchangeRate       sql.NullString
+	TransferCreatedAt          sql.NullTime
+	TransferReversesTransferID sql.NullInt64
+	TransferMemo               sql.NullString
+	TransferExternalReference  sql.NullString
+	TransferMetadata           string
+}
+
+// Walks the chain of an account in the order it was written, with the transfer sealed into each entry
+func (q *Queries) ListEntryChain(ctx context.Context, arg ListEntryChainParams) ([]ListEntryChainRow, error) {
+	rows, err := q.db.QueryContext(ctx, listEntryChain, arg.AccountID, arg.AfterID, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListEntryChainRow{}
+	for rows.Next() {
+		var i ListEntryChainRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.TransferID,
+			&i.PrevHash,
+			&i.Hash,
+			&i.TransferFromAccountID,
+			&i.TransferToAccountID,
+			&i.TransferAmount,
+			&i.TransferToAmount,
+			&i.TransferExchangeRate,
+			&i.TransferCreatedAt,
+			&i.TransferReversesTransferID,
+			&i.TransferMemo,
+			&i.TransferExternalReference,
+			&i.TransferMetadata,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOwnerBalancesAsOf = `-- name: ListOwnerBalancesAsOf :many
+SELECT
+  a.id AS account_id,
+  a.currency,
+  (COALESCE(s.balance, 0) + COALESCE((
+    SELECT SUM(e.amount) FROM entries e
+    WHERE e.account_id = a.id
+      AND e.created_at >= COALESCE(s.cutoff, '-infinity'::timestamptz)
+      AND e.created_at <= $1::timestamptz
+  ), 0))::bigint AS balance
+FROM accounts a
+LEFT JOIN LATERAL (
+  SELECT bs.balance, bs.cutoff FROM balance_snapshots bs
+  WHERE bs.account_id = a.id AND bs.cutoff <= $1::timestamptz
+  ORDER BY bs.cutoff DESC
+  LIMIT 1
+) s ON true
+WHERE a.owner = $2 AND a.created_at <= $1::timestamptz
+ORDER BY a.id
+`
+
+type ListOwnerBalancesAsOfParams struct {
+	AsOf  time.Time
+	Owner string
+}
+
+type ListOwnerBalancesAsOfRow struct {
+	AccountID int64
+	Currency  string
+	Balance   int64
+}
+
+func (q *Queries) ListOwnerBalancesAsOf(ctx context.Context, arg ListOwnerBalancesAsOfParams) ([]ListOwnerBalancesAsOfRow, error) {
+	rows, err := q.db.QueryContext(ctx, listOwnerBalancesAsOf, arg.AsOf, arg.Owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListOwnerBalancesAsOfRow{}
+	for rows.Next() {
+		var i ListOwnerBalancesAsOfRow
+		if err := rows.Scan(&i.AccountID, &i.Currency, &i.Balance); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStatementEntries = `-- name: ListStatementEntries :many
+SELECT
+  e.id,
+  e.account_id,
+  e.amount,
+  e.created_at,
+  e.transfer_id,
+  t.exchange_rate,
+  t.reverses_transfer_id,
+  t.memo,
+  c.id AS counterparty_account_id,
+  c.owner AS counterparty_owner,
+  c.currency AS counterparty_currency,
+  ba.reason AS adjustment_reason
+FROM entries e
+LEFT JOIN transfer t ON t.id = e.transfer_id
+LEFT JOIN accounts c ON c.id = CASE WHEN t.from_account_id = e.account_id THEN t.to_account_id ELSE t.from_account_id END
+LEFT JOIN balance_adjustments ba ON ba.entry_id = e.id
+WHERE e.account_id = $1
+  AND e.created_at >= $2
+  AND e.created_at < $3
+ORDER BY e.created_at, e.id
+`
+
+type ListStatementEntriesParams struct {
+	AccountID int64
+	FromTime  time.Time
+	ToTime    time.Time
+}
+
+type ListStatementEntriesRow struct {
+	ID                    int64
+	AccountID             int64
+	Amount                int64
+	CreatedAt             time.Time
+	TransferID            sql.NullInt64
+	ExchangeRate          sql.NullString
+	ReversesTransferID    sql.NullInt64
+	Memo                  sql.NullString
+	CounterpartyAccountID sql.NullInt64
+	CounterpartyOwner     sql.NullString
+	CounterpartyCurrency  sql.NullString
+	AdjustmentReason      sql.NullString
+}
+
+func (q *Queries) ListStatementEntries(ctx context.Context, arg ListStatementEntriesParams) ([]ListStatementEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listStatementEntries, arg.AccountID, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListStatementEntriesRow{}
+	for rows.Next() {
+		var i ListStatementEntriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.TransferID,
+			&i.ExchangeRate,
+			&i.ReversesTransferID,
+			&i.Memo,
+			&i.CounterpartyAccountID,
+			&i.CounterpartyOwner,
+			&i.CounterpartyCurrency,
+			&i.AdjustmentReason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const sealEntry = `-- name: SealEntry :one
+UPDATE entries
+SET prev_hash = $1, hash = $2
+WHERE id = $3
+RETURNING id, account_id, amount, created_at, transfer_id, prev_hash, hash
+`
+
+type SealEntryParams struct {
+	PrevHash []byte
+	Hash     []byte
+	ID       int64
+}
+
+func (q *Queries) SealEntry(ctx context.Context, arg SealEntryParams) (Entry, error) {
+	row := q.db.QueryRowContext(ctx, sealEntry, arg.PrevHash, arg.Hash, arg.ID)
+	var i Entry
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.TransferID,
+		&i.PrevHash,
+		&i.Hash,
+	)
+	return i, err
+}
+
+const sumBalanceAsOf = `-- name: SumBalanceAsOf :one
+WITH snapshot AS (
+  SELECT bs.balance, bs.cutoff FROM balance_snapshots bs
+  WHERE bs.account_id = $1::bigint AND bs.cutoff <= $2::timestamptz
+  ORDER BY bs.cutoff DESC
+  LIMIT 1
+)
+SELECT (COALESCE((SELECT snapshot.balance FROM snapshot), 0) + COALESCE((
+  SELECT SUM(e.amount) FROM entries e
+  WHERE e.account_id = $1::bigint
+    AND e.created_at >= COALESCE((SELECT snapshot.cutoff FROM snapshot), '-infinity'::timestamptz)
+    AND e.created_at <= $2::timestamptz
+), 0))::bigint AS balance
+`
+
+type SumBalanceAsOfParams struct {
+	AccountID int64
+	AsOf      time.Time
+}
+
+// Like SumBalanceBefore, but entries created at as_of are included
+func (q *Queries) SumBalanceAsOf(ctx context.Context, arg SumBalanceAsOfParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, sumBalanceAsOf, arg.AccountID, arg.AsOf)
+	var balance int64
+	err := row.Scan(&balance)
+	return balance, err
+}
+
+const sumBalanceBefore = `-- name: SumBalanceBefore :one
+WITH snapshot AS (
+  SELECT bs.balance, bs.cutoff FROM balance_snapshots bs
+  WHERE bs.account_id = $1::bigint AND bs.cutoff <= $2::timestamptz
+  ORDER BY bs.cutoff DESC
+  LIMIT 1
+)
+SELECT (COALESCE((SELECT snapshot.balance FROM snapshot), 0) + COALESCE((
+  SELECT SUM(e.amount) FROM entries e
+  WHERE e.account_id = $1::bigint
+    AND e.created_at >= COALESCE((SELECT snapshot.cutoff FROM snapshot), '-infinity'::timestamptz)
+    AND e.created_at < $2::timestamptz
+), 0))::bigint AS balance
+`
+
+type SumBalanceBeforeParams struct {
+	AccountID int64
+	Before    time.Time
+}
+
+// Starts from the latest snapshot taken by before and adds the entries created after it
+func (q *Queries) SumBalanceBefore(ctx context.Context, arg SumBalanceBeforeParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, sumBalanceBefore, arg.AccountID, arg.Before)
+	var balance int64
+	err := row.Scan(&balance)
+	return balance, err
+}
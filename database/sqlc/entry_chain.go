@@ -0,0 +1,245 @@
+package sqlc
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"hash"
+)
+
+// GenesisHash is the previous hash of the first entry in every account's chain
+var GenesisHash = make([]byte, sha256.Size)
+
+// entryChainBatchSize is how many entries VerifyEntryChain reads at a time
+const entryChainBatchSize = 1000
+
+// Reasons reported by VerifyEntryChain for a broken chain
+const (
+	// ChainEntryModified is an entry, or the transfer it belongs to, that no longer matches its hash
+	ChainEntryModified = "entry_modified"
+	// ChainLinkBroken is an entry whose previous hash isn't the hash of the entry before it,
+	// an entry was removed, inserted or reordered
+	ChainLinkBroken = "link_broken"
+	// ChainEntryUnsealed is an entry without a hash after the chain of its account began
+	ChainEntryUnsealed = "entry_unsealed"
+	// ChainHeadMismatch is an account whose chain head isn't its latest entry, the latest entries were removed
+	ChainHeadMismatch = "head_mismatch"
+)
+
+// chainTimeFormat keeps the microseconds Postgres stores, whatever the time zone of the value
+const chainTimeFormat = "2006-01-02T15:04:05.000000Z"
+
+// EntryHash returns the hash sealing an entry onto its account's chain. It covers the previous hash,
+// the entry and, for transfer entries, the transfer, so editing either one breaks the chain.
+// It is an HMAC-SHA256 keyed with key, so rewriting a chain takes the key and not only access to the database.
+// An empty key makes it a plain SHA-256, which anyone can recompute
+func EntryHash(key []byte, prevHash []byte, entry Entry, transfer *Transfer) []byte {
+	var h hash.Hash
+	if len(key) > 0 {
+		h = hmac.New(sha256.New, key)
+	} else {
+		h = sha256.New()
+	}
+	h.Write(prevHash)
+	fmt.Fprintf(h, "v1|entry|%d|%d|%d|%s", entry.ID, entry.AccountID, entry.Amount, entry.CreatedAt.UTC().Format(chainTimeFormat))
+	if transfer != nil {
+		fmt.Fprintf(h, "|transfer|%d|%d|%d|%d|%d|%s|%s",
+			transfer.ID, transfer.FromAccountID, transfer.ToAccountID, transfer.Amount,
+			transfer.ToAmount, transfer.ExchangeRate, transfer.CreatedAt.UTC().Format(chainTimeFormat))
+		// Optional fields are only sealed when set, so hashes written before they existed stay valid
+		if transfer.ReversesTransferID.Valid {
+			fmt.Fprintf(h, "|reverses|%d", transfer.ReversesTransferID.Int64)
+		}
+		if transfer.Memo != "" {
+			fmt.Fprintf(h, "|memo|%q", transfer.Memo)
+		}
+		if transfer.ExternalReference != "" {
+			fmt.Fprintf(h, "|reference|%q", transfer.ExternalReference)
+		}
+		// jsonb is read back in the same normalized text it was returned in when the transfer was created
+		if metadata := string(transfer.Metadata); metadata != "" && metadata != "{}" {
+			fmt.Fprintf(h, "|metadata|%s", metadata)
+		}
+	} else {
+		fmt.Fprint(h, "|adjustment")
+	}
+	return h.Sum(nil)
+}
+
+// appendEntry creates an entry and seals it onto the end of its account's chain.
+// account must be the row returned by the update which locked it in this transaction, so no other
+// transaction can extend the same chain until this one ends. It returns the account with its new head
+func (s *SQLStore) appendEntry(ctx context.Context, q *Queries, account Account, arg CreateEntryParams, transfer *Transfer) (Entry, Account, error) {
+	entry, err := q.CreateEntry(ctx, arg)
+	if err != nil {
+		return entry, account, err
+	}
+
+	prevHash := account.EntryHash
+	if prevHash == nil {
+		prevHash = GenesisHash
+	}
+	entry, err = q.SealEntry(ctx, SealEntryParams{
+		ID:       entry.ID,
+		PrevHash: prevHash,
+		Hash:     EntryHash(s.entryHashKey, prevHash, entry, transfer),
+	})
+	if err != nil {
+		return entry, account, err
+	}
+
+	account, err = q.UpdateAccountEntryHash(ctx, UpdateAccountEntryHashParams{
+		ID:        account.ID,
+		EntryHash: entry.Hash,
+	})
+	return entry, account, err
+}
+
+// EntryChainResult is the result of verifying an account's chain
+type EntryChainResult struct {
+	AccountID int64 `json:"account_id"`
+	// EntriesChecked counts the entries whose hash was recomputed
+	EntriesChecked int64 `json:"entries_checked"`
+	// UnsealedEntries counts the entries written before the chain existed, they can't be verified
+	UnsealedEntries int64 `json:"unsealed_entries"`
+	Intact          bool  `json:"intact"`
+	// BrokenEntryID is the first entry that doesn't verify, zero when the chain is intact or only its head is wrong
+	BrokenEntryID int64  `json:"broken_entry_id"`
+	Reason        string `json:"reason"`
+	Detail        string `json:"detail"`
+}
+
+// VerifyEntryChain walks an account's entries in the order they were written, recomputing every hash,
+// and reports the first broken link. The walk reads a single repeatable read snapshot, so transfers
+// committing meanwhile can't show up as a broken chain. It returns sql.ErrNoRows when the account doesn't exist
+func (s *SQLStore) VerifyEntryChain(ctx context.Context, accountID int64) (EntryChainResult, error) {
+	result := EntryChainResult{AccountID: accountID}
+
+	opts := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	err := s.execTxOptions(ctx, opts, func(q *Queries) error {
+		account, err := q.GetAccount(ctx, accountID)
+		if err != nil {
+			return err
+		}
+
+		// nil until the first sealed entry, entries before it predate the chain
+		var prevHash []byte
+		var prevID int64
+		for {
+			entries, err := q.ListEntryChain(ctx, ListEntryChainParams{
+				AccountID:  accountID,
+				AfterID:    prevID,
+				LimitCount: entryChainBatchSize,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, row := range entries {
+				if !checkChainEntry(&result, s.entryHashKey, row, prevHash, prevID) {
+					return nil
+				}
+				if row.Hash != nil {
+					prevHash = row.Hash
+				}
+				prevID = row.ID
+			}
+			if len(entries) < entryChainBatchSize {
+				break
+			}
+		}
+
+		if !bytes.Equal(account.EntryHash, prevHash) {
+			result.Reason = ChainHeadMismatch
+			if prevHash == nil {
+				result.Detail = fmt.Sprintf("account %d has a chain head but no sealed entries", accountID)
+			} else {
+				result.Detail = fmt.Sprintf("head of account %d isn't the hash of its latest entry %d", accountID, prevID)
+			}
+			return nil
+		}
+		result.Intact = true
+		return nil
+	})
+	return result, err
+}
+
+// checkChainEntry verifies one entry against the hash of the sealed entry before it, prevHash is nil
+// while the chain hasn't begun. It records the reason in result and returns false when the link is broken
+func checkChainEntry(result *EntryChainResult, key []byte, row ListEntryChainRow, prevHash []byte, prevID int64) bool {
+	if row.Hash == nil {
+		if prevHash != nil {
+			result.BrokenEntryID = row.ID
+			result.Reason = ChainEntryUnsealed
+			result.Detail = fmt.Sprintf("entry %d has no hash but follows sealed entry %d", row.ID, prevID)
+			return false
+		}
+		result.UnsealedEntries++
+		return true
+	}
+
+	expectedPrev := prevHash
+	if expectedPrev == nil {
+		expectedPrev = GenesisHash
+	}
+	if !bytes.Equal(row.PrevHash, expectedPrev) {
+		result.BrokenEntryID = row.ID
+		result.Reason = ChainLinkBroken
+		if prevHash == nil {
+			result.Detail = fmt.Sprintf("entry %d doesn't start the chain", row.ID)
+		} else {
+			result.Detail = fmt.Sprintf("entry %d doesn't follow entry %d", row.ID, prevID)
+		}
+		return false
+	}
+
+	entry, transfer := chainEntry(row)
+	result.EntriesChecked++
+	if !bytes.Equal(row.Hash, EntryHash(key, row.PrevHash, entry, transfer)) {
+		result.BrokenEntryID = row.ID
+		result.Reason = ChainEntryModified
+		if transfer != nil {
+			result.Detail = fmt.Sprintf("entry %d or transfer %d was modified", row.ID, transfer.ID)
+		} else {
+			result.Detail = fmt.Sprintf("entry %d was modified", row.ID)
+		}
+		return false
+	}
+	return true
+}
+
+// chainEntry splits a row of the chain into its entry and transfer, the transfer is nil for adjustments
+func chainEntry(row ListEntryChainRow) (Entry, *Transfer) {
+	entry := Entry{
+		ID:         row.ID,
+		AccountID:  row.AccountID,
+		Amount:     row.Amount,
+		CreatedAt:  row.CreatedAt,
+		TransferID: row.TransferID,
+		PrevHash:   row.PrevHash,
+		Hash:       row.Hash,
+	}
+	if !row.TransferID.Valid {
+		return entry, nil
+	}
+
+	// A deleted transfer reads as zero values, which no longer match the hash
+	transfer := &Transfer{
+		ID:                 row.TransferID.Int64,
+		FromAccountID:      row.TransferFromAccountID.Int64,
+		ToAccountID:        row.TransferToAccountID.Int64,
+		Amount:             row.TransferAmount.Int64,
+		ToAmount:           row.TransferToAmount.Int64,
+		ExchangeRate:       row.TransferExchangeRate.String,
+		CreatedAt:          row.TransferCreatedAt.Time,
+		ReversesTransferID: row.TransferReversesTransferID,
+		Memo:               row.TransferMemo.String,
+		ExternalReference:  row.TransferExternalReference.String,
+		Metadata:           json.RawMessage(row.TransferMetadata),
+	}
+	return entry, transfer
+}
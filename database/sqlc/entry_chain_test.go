@@ -0,0 +1,208 @@
+package sqlc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/October-9th/simple-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyEntryChain(t *testing.T) {
+	store := NewStore(testDB)
+	account1, account2 := CreateRandomAccountPair(t)
+
+	// Transfers both ways run concurrently, the account locks keep each chain in order
+	n := 6
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		fromAccountID, toAccountID := account1.ID, account2.ID
+		if i%2 == 1 {
+			fromAccountID, toAccountID = account2.ID, account1.ID
+		}
+		go func() {
+			_, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: fromAccountID,
+				ToAccountID:   toAccountID,
+				Amount:        amountOf(account1, 10),
+			})
+			errs <- err
+		}()
+	}
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+	}
+
+	adjustment, err := store.AdjustBalanceTx(context.Background(), AdjustBalanceTxParams{
+		AccountID:  account1.ID,
+		Amount:     amountOf(account1, 5),
+		Reason:     "test",
+		AdjustedBy: "test",
+	})
+	require.NoError(t, err)
+	require.Equal(t, adjustment.Entry.Hash, adjustment.Account.EntryHash)
+
+	for _, accountID := range []int64{account1.ID, account2.ID} {
+		result, err := store.VerifyEntryChain(context.Background(), accountID)
+		require.NoError(t, err)
+		require.True(t, result.Intact, result.Detail)
+		require.Zero(t, result.BrokenEntryID)
+		require.Zero(t, result.UnsealedEntries)
+	}
+
+	result, err := store.VerifyEntryChain(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(n+1), result.EntriesChecked)
+
+	// Editing an amount breaks the chain at that entry
+	_, err = testDB.Exec("UPDATE entries SET amount = amount + 1 WHERE id = $1", adjustment.Entry.ID)
+	require.NoError(t, err)
+
+	result, err = store.VerifyEntryChain(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.False(t, result.Intact)
+	require.Equal(t, adjustment.Entry.ID, result.BrokenEntryID)
+	require.Equal(t, ChainEntryModified, result.Reason)
+}
+
+func TestVerifyEntryChainTransferModified(t *testing.T) {
+	store := NewStore(testDB)
+	account1, account2 := CreateRandomAccountPair(t)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amountOf(account1, 10),
+	})
+	require.NoError(t, err)
+
+	_, err = testDB.Exec("UPDATE transfer SET to_amount = to_amount + 1 WHERE id = $1", result.Transfer.ID)
+	require.NoError(t, err)
+
+	chain, err := store.VerifyEntryChain(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.False(t, chain.Intact)
+	require.Equal(t, result.ToEntry.ID, chain.BrokenEntryID)
+	require.Equal(t, ChainEntryModified, chain.Reason)
+}
+
+func TestVerifyEntryChainEntryDeleted(t *testing.T) {
+	store := NewStore(testDB)
+	account1, account2 := CreateRandomAccountPair(t)
+
+	var transfers []TransferTxResult
+	for i := 0; i < 3; i++ {
+		result, err := store.TransferTx(context.Background(), TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        amountOf(account1, 10),
+		})
+		require.NoError(t, err)
+		transfers = append(transfers, result)
+	}
+
+	// Removing the middle entry leaves the next one pointing at nothing
+	_, err := testDB.Exec("DELETE FROM entries WHERE id = $1", transfers[1].FromEntry.ID)
+	require.NoError(t, err)
+
+	result, err := store.VerifyEntryChain(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.False(t, result.Intact)
+	require.Equal(t, transfers[2].FromEntry.ID, result.BrokenEntryID)
+	require.Equal(t, ChainLinkBroken, result.Reason)
+
+	// Removing the latest entry is caught by the head kept on the account
+	_, err = testDB.Exec("DELETE FROM entries WHERE id = $1", transfers[2].ToEntry.ID)
+	require.NoError(t, err)
+
+	result, err = store.VerifyEntryChain(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.False(t, result.Intact)
+	require.Zero(t, result.BrokenEntryID)
+	require.Equal(t, ChainHeadMismatch, result.Reason)
+}
+
+func TestVerifyEntryChainUnsealed(t *testing.T) {
+	store := NewStore(testDB)
+	account1, account2 := CreateRandomAccountPair(t)
+
+	// Entries from before the chain existed are counted but can't be verified
+	CreateRandomEntry(t, account1)
+
+	transfer, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amountOf(account1, 10),
+	})
+	require.NoError(t, err)
+
+	result, err := store.VerifyEntryChain(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.True(t, result.Intact, result.Detail)
+	require.Equal(t, int64(1), result.UnsealedEntries)
+	require.Equal(t, int64(1), result.EntriesChecked)
+
+	// An entry slipped in once the chain began is not
+	entry := CreateRandomEntry(t, account1)
+	require.Greater(t, entry.ID, transfer.FromEntry.ID)
+
+	result, err = store.VerifyEntryChain(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.False(t, result.Intact)
+	require.Equal(t, entry.ID, result.BrokenEntryID)
+	require.Equal(t, ChainEntryUnsealed, result.Reason)
+}
+
+func TestTransferTxSameAccount(t *testing.T) {
+	store := NewStore(testDB)
+	account, other := CreateRandomAccountPair(t)
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account.ID,
+		ToAccountID:   other.ID,
+		Amount:        amountOf(account, 10),
+	})
+	require.NoError(t, err)
+
+	// Both entries would be sealed onto the account's chain from the same head
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account.ID,
+		ToAccountID:   account.ID,
+		Amount:        amountOf(account, 10),
+	})
+	require.ErrorIs(t, err, ErrSameAccount)
+
+	unchanged, err := store.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance-10, unchanged.Balance)
+
+	result, err := store.VerifyEntryChain(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.True(t, result.Intact, result.Detail)
+	require.Equal(t, int64(1), result.EntriesChecked)
+}
+
+func TestVerifyEntryChainKeyed(t *testing.T) {
+	store := NewStore(testDB, WithEntryHashKey([]byte(util.RandomString(32))))
+	account1, account2 := CreateRandomAccountPair(t)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amountOf(account1, 10),
+	})
+	require.NoError(t, err)
+
+	chain, err := store.VerifyEntryChain(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.True(t, chain.Intact, chain.Detail)
+
+	// Without the key, or with another one, the hashes can't be recomputed
+	for _, other := range []Store{NewStore(testDB), NewStore(testDB, WithEntryHashKey([]byte(util.RandomString(32))))} {
+		chain, err = other.VerifyEntryChain(context.Background(), account2.ID)
+		require.NoError(t, err)
+		require.False(t, chain.Intact)
+		require.Equal(t, result.ToEntry.ID, chain.BrokenEntryID)
+		require.Equal(t, ChainEntryModified, chain.Reason)
+	}
+}
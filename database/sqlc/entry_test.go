@@ -2,6 +2,7 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 	"time"
 
@@ -58,3 +59,160 @@ func TestGetListEntry(t *testing.T) {
 		require.Equal(t, entry.AccountID, account.ID)
 	}
 }
+
+func TestStatementTx(t *testing.T) {
+	store := NewStore(testDB)
+	account1, account2 := CreateRandomAccountPair(t)
+	admin := CreateRandomUser(t)
+
+	// An entry before the period only counts towards the opening balance
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amountOf(account1, 10),
+	})
+	require.NoError(t, err)
+
+	from := time.Now()
+	transfer, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amountOf(account1, 5),
+	})
+	require.NoError(t, err)
+	adjustment, err := store.AdjustBalanceTx(context.Background(), AdjustBalanceTxParams{
+		AccountID:  account1.ID,
+		Amount:     amountOf(account1, 3),
+		Reason:     util.RandomString(10),
+		AdjustedBy: admin.Username,
+	})
+	require.NoError(t, err)
+	to := time.Now()
+
+	result, err := store.StatementTx(context.Background(), StatementTxParams{
+		AccountID: account1.ID,
+		From:      from,
+		To:        to,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account1.ID, result.Account.ID)
+	require.Equal(t, int64(-10), result.OpeningBalance)
+	require.Equal(t, int64(-10-5+3), result.ClosingBalance)
+	require.Len(t, result.Entries, 2)
+
+	transferEntry := result.Entries[0]
+	require.Equal(t, transfer.FromEntry.ID, transferEntry.ID)
+	require.Equal(t, transfer.Transfer.ID, transferEntry.TransferID.Int64)
+	require.Equal(t, account2.ID, transferEntry.CounterpartyAccountID.Int64)
+	require.Equal(t, account2.Owner, transferEntry.CounterpartyOwner.String)
+
+	adjustmentEntry := result.Entries[1]
+	require.Equal(t, adjustment.Entry.ID, adjustmentEntry.ID)
+	require.False(t, adjustmentEntry.TransferID.Valid)
+	require.Equal(t, adjustment.Adjustment.Reason, adjustmentEntry.AdjustmentReason.String)
+
+	_, err = store.StatementTx(context.Background(), StatementTxParams{AccountID: -1, From: from, To: to})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestGetBalanceAsOf(t *testing.T) {
+	store := NewStore(testDB)
+	account1, account2 := CreateRandomAccountPair(t)
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amountOf(account1, 10),
+	})
+	require.NoError(t, err)
+	asOf := time.Now()
+
+	// A later transfer doesn't change the balance as of before it
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amountOf(account1, 5),
+	})
+	require.NoError(t, err)
+
+	balance, err := store.GetBalanceAsOf(context.Background(), GetBalanceAsOfParams{
+		AccountID: account1.ID,
+		AsOf:      asOf,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account1.ID, balance.AccountID)
+	require.Equal(t, account1.Currency, balance.Currency)
+	require.Equal(t, int64(-10), balance.Balance)
+
+	balances, err := store.ListBalancesAsOf(context.Background(), ListBalancesAsOfParams{
+		Owner: account2.Owner,
+		AsOf:  time.Now(),
+	})
+	require.NoError(t, err)
+	require.Len(t, balances, 1)
+	require.Equal(t, account2.ID, balances[0].AccountID)
+	require.Equal(t, int64(15), balances[0].Balance)
+
+	_, err = store.GetBalanceAsOf(context.Background(), GetBalanceAsOfParams{AccountID: -1, AsOf: asOf})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestSnapshotBalances(t *testing.T) {
+	store := NewStore(testDB)
+	account1, account2 := CreateRandomAccountPair(t)
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amountOf(account1, 10),
+	})
+	require.NoError(t, err)
+
+	// Today's snapshot cuts off at the coming midnight, so it covers the transfer above
+	arg := SnapshotBalancesParams{Date: time.Now().UTC(), BatchSize: 50}
+	written, err := store.SnapshotBalances(context.Background(), arg)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, written, int64(2))
+
+	day, cutoff := SnapshotDay(arg.Date)
+	snapshot, err := store.GetBalanceSnapshot(context.Background(), GetBalanceSnapshotParams{
+		AccountID:    account1.ID,
+		SnapshotDate: day,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(-10), snapshot.Balance)
+	require.WithinDuration(t, cutoff, snapshot.Cutoff, time.Second)
+
+	// A second run finds nothing left to do
+	written, err = store.SnapshotBalances(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, written)
+
+	// Balances after the cutoff start from the snapshot
+	balance, err := store.GetBalanceAsOf(context.Background(), GetBalanceAsOfParams{
+		AccountID: account2.ID,
+		AsOf:      cutoff.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(10), balance.Balance)
+}
+
+func TestSnapshotBalancesDayNotSettled(t *testing.T) {
+	store := NewStore(testDB)
+
+	// A transaction writing entries of the day and still open could add more before it commits
+	tx, err := testDB.Begin()
+	require.NoError(t, err)
+	require.NoError(t, New(tx).LockLedgerDay(context.Background()))
+
+	arg := SnapshotBalancesParams{Date: time.Now().UTC(), SettleTimeout: 100 * time.Millisecond}
+	_, err = store.SnapshotBalances(context.Background(), arg)
+	require.ErrorIs(t, err, ErrDayNotSettled)
+
+	// Those of other days don't hold the snapshot up
+	arg.Date = arg.Date.AddDate(0, 0, -1)
+	_, err = store.SnapshotBalances(context.Background(), arg)
+	require.NoError(t, err)
+
+	require.NoError(t, tx.Rollback())
+}
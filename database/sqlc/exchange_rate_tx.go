@@ -0,0 +1,92 @@
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/October-9th/simple-bank/fx"
+	"github.com/October-9th/simple-bank/money"
+)
+
+// ErrNoExchangeRate is returned by TransferTx when the accounts have different currencies
+// and no rate of the pair is in effect yet
+var ErrNoExchangeRate = errors.New("no exchange rate for currency pair")
+
+// ErrAmountTooSmallToConvert is returned by TransferTx when the converted amount rounds down to nothing
+var ErrAmountTooSmallToConvert = errors.New("transfer amount is too small to convert")
+
+// LoadExchangeRatesTx stores rates in a single transaction. Loading a rate of a pair
+// and effective time that already exists replaces its value, so loading the same file twice is harmless
+func (s *SQLStore) LoadExchangeRatesTx(ctx context.Context, rates []fx.Rate) ([]ExchangeRate, error) {
+	result := make([]ExchangeRate, 0, len(rates))
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		for _, rate := range rates {
+			exchangeRate, err := q.UpsertExchangeRate(ctx, UpsertExchangeRateParams{
+				BaseCurrency:  rate.Base,
+				QuoteCurrency: rate.Quote,
+				Rate:          rate.Rate,
+				EffectiveAt:   rate.EffectiveAt,
+			})
+			if err != nil {
+				return err
+			}
+			result = append(result, exchangeRate)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// convertTransferAmount returns the amount the destination account receives and the rate used.
+// Accounts sharing a currency use a rate of 1, otherwise the minor units of both currencies are taken into account
+func convertTransferAmount(ctx context.Context, q *Queries, arg TransferTxParams) (money.Amount, string, error) {
+	fromAccount, err := q.GetAccount(ctx, arg.FromAccountID)
+	if err != nil {
+		return money.Amount{}, "", err
+	}
+	if arg.Amount.Currency != fromAccount.Currency {
+		return money.Amount{}, "", fmt.Errorf("%w: amount is in %s, account [%d] holds %s",
+			money.ErrCurrencyMismatch, arg.Amount.Currency, fromAccount.ID, fromAccount.Currency)
+	}
+	toAccount, err := q.GetAccount(ctx, arg.ToAccountID)
+	if err != nil {
+		return money.Amount{}, "", err
+	}
+	if fromAccount.Currency == toAccount.Currency {
+		return money.Amount{Minor: arg.Amount.Minor, Currency: toAccount.Currency}, "1", nil
+	}
+
+	exchangeRate, err := q.GetExchangeRate(ctx, GetExchangeRateParams{
+		BaseCurrency:  fromAccount.Currency,
+		QuoteCurrency: toAccount.Currency,
+		AsOf:          time.Now(),
+	})
+	if err == sql.ErrNoRows {
+		return money.Amount{}, "", ErrNoExchangeRate
+	}
+	if err != nil {
+		return money.Amount{}, "", err
+	}
+
+	fromCurrency, err := q.GetCurrency(ctx, fromAccount.Currency)
+	if err != nil {
+		return money.Amount{}, "", err
+	}
+	toCurrency, err := q.GetCurrency(ctx, toAccount.Currency)
+	if err != nil {
+		return money.Amount{}, "", err
+	}
+
+	toMinor, err := fx.Convert(arg.Amount.Minor, exchangeRate.Rate, fromCurrency.MinorUnit, toCurrency.MinorUnit)
+	if err != nil {
+		return money.Amount{}, "", err
+	}
+	if toMinor <= 0 {
+		return money.Amount{}, "", ErrAmountTooSmallToConvert
+	}
+	return money.Amount{Minor: toMinor, Currency: toAccount.Currency}, exchangeRate.Rate, nil
+}
@@ -0,0 +1,95 @@
+package sqlc
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrIdempotencyKeyReused is returned when an idempotency key is used again with different parameters
+var ErrIdempotencyKeyReused = errors.New("idempotency key already used with a different request")
+
+// hashedTransferRequest is what requestHash hashes. The amount is kept in minor units, as it was hashed
+// before TransferTxParams carried its currency, so keys stored earlier still match.
+// The currency is always the source account's, so leaving it out loses nothing
+type hashedTransferRequest struct {
+	FromAccountID     int64             `json:"from_account_id"`
+	ToAccountID       int64             `json:"to_account_id"`
+	Amount            int64             `json:"amount"`
+	Memo              string            `json:"memo,omitempty"`
+	ExternalReference string            `json:"external_reference,omitempty"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+}
+
+// requestHash returns the sha256 of the transfer parameters, the idempotency fields themselves are not part of it
+func (arg TransferTxParams) requestHash() (string, error) {
+	data, err := json.Marshal(hashedTransferRequest{
+		FromAccountID:     arg.FromAccountID,
+		ToAccountID:       arg.ToAccountID,
+		Amount:            arg.Amount.Minor,
+		Memo:              arg.Memo,
+		ExternalReference: arg.ExternalReference,
+		Metadata:          arg.Metadata,
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// claimIdempotencyKey inserts the key for this request. If the key already exists the stored result
+// is decoded into result and replayed is true.
+// A concurrent transaction holding the same key makes the insert wait until it commits or rolls back,
+// so only one of them ever performs the transfer
+func claimIdempotencyKey(ctx context.Context, q *Queries, arg TransferTxParams, result *TransferTxResult) (replayed bool, err error) {
+	hash, err := arg.requestHash()
+	if err != nil {
+		return false, err
+	}
+
+	_, err = q.CreateIdempotencyKey(ctx, CreateIdempotencyKeyParams{
+		Username:    arg.Username,
+		Key:         arg.IdempotencyKey,
+		RequestHash: hash,
+	})
+	if err == nil {
+		return false, nil
+	}
+	if err != sql.ErrNoRows {
+		return false, err
+	}
+
+	// ON CONFLICT DO NOTHING returned no row, the key was used before
+	key, err := q.GetIdempotencyKey(ctx, GetIdempotencyKeyParams{
+		Username: arg.Username,
+		Key:      arg.IdempotencyKey,
+	})
+	if err != nil {
+		return false, err
+	}
+	if key.RequestHash != hash {
+		return false, ErrIdempotencyKeyReused
+	}
+	if err := json.Unmarshal(key.Response, result); err != nil {
+		return false, fmt.Errorf("couldn't decode stored result: %w", err)
+	}
+	return true, nil
+}
+
+// saveIdempotentResult stores the transfer result with the key claimed by claimIdempotencyKey
+func saveIdempotentResult(ctx context.Context, q *Queries, arg TransferTxParams, result TransferTxResult) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return q.UpdateIdempotencyKeyResponse(ctx, UpdateIdempotencyKeyResponseParams{
+		Username: arg.Username,
+		Key:      arg.IdempotencyKey,
+		Response: data,
+	})
+}
@@ -1,6 +1,7 @@
 package sqlc
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"os"
@@ -24,6 +25,10 @@ func TestMain(m *testing.M) {
 		log.Fatal("Couldn't connect to database: ", err)
 	}
 	testQueries = New(testDB)
+
+	if err := LoadCurrencies(context.Background(), testQueries); err != nil {
+		log.Fatal("Couldn't load currencies: ", err)
+	}
 	os.Exit(m.Run())
 
 }
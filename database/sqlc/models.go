@@ -5,6 +5,8 @@
 package sqlc
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,6 +18,48 @@ type Account struct {
 	Balance   int64
 	Currency  string
 	CreatedAt time.Time
+	// how far below zero the balance may go, must not be negative
+	OverdraftLimit int64
+	// set once the account is closed, closed accounts keep their history but take no more transfers
+	ClosedAt sql.NullTime
+	// active, frozen or closed, only active accounts send or receive money
+	Status string
+	// hash of the latest entry of the account, the head of its entry chain
+	EntryHash []byte
+}
+
+type BalanceAdjustment struct {
+	ID        int64
+	AccountID int64
+	EntryID   int64
+	// can be positive or negative, never zero
+	Amount     int64
+	Reason     string
+	AdjustedBy string
+	CreatedAt  time.Time
+}
+
+type BalanceSnapshot struct {
+	AccountID int64
+	// the UTC day the snapshot closes
+	SnapshotDate time.Time
+	// end of snapshot_date, balance is the sum of every entry created before it
+	Cutoff    time.Time
+	Balance   int64
+	CreatedAt time.Time
+}
+
+type Currency struct {
+	// ISO 4217 alphabetic code
+	Code string
+	// ISO 4217 numeric code, kept as text to preserve leading zeros
+	NumericCode string
+	Name        string
+	// number of decimal places, amounts are stored in units of 10^-minor_unit
+	MinorUnit int32
+	// only enabled currencies can be used for new accounts and transfers
+	Enabled   bool
+	CreatedAt time.Time
 }
 
 type Entry struct {
@@ -24,6 +68,71 @@ type Entry struct {
 	// can be positive or negative
 	Amount    int64
 	CreatedAt time.Time
+	// the transfer this entry belongs to, null for balance adjustments
+	TransferID sql.NullInt64
+	// hash of the previous entry of the account, null for entries written before the chain existed
+	PrevHash []byte
+	// SHA-256 over prev_hash, the entry and its transfer, null for entries written before the chain existed
+	Hash []byte
+}
+
+type ExchangeRate struct {
+	ID            int64
+	BaseCurrency  string
+	QuoteCurrency string
+	// units of quote currency for one unit of base currency, must be positive
+	Rate string
+	// the rate applies from this time until a later rate of the same pair takes over
+	EffectiveAt time.Time
+	CreatedAt   time.Time
+}
+
+type IdempotencyKey struct {
+	Username string
+	Key      string
+	// sha256 of the request the key was first used with
+	RequestHash string
+	// serialized result returned to replays of the same request
+	Response  json.RawMessage
+	CreatedAt time.Time
+}
+
+type OverdraftLimitChange struct {
+	ID            int64
+	AccountID     int64
+	PreviousLimit int64
+	NewLimit      int64
+	Reason        string
+	// username of the banker or admin who changed the limit
+	ChangedBy string
+	CreatedAt time.Time
+}
+
+type ReconciliationDiscrepancy struct {
+	ID    int64
+	RunID int64
+	// balance_mismatch, transfer_entries_mismatch, orphan_entry, adjustment_mismatch or snapshot_mismatch
+	Kind       string
+	AccountID  sql.NullInt64
+	TransferID sql.NullInt64
+	EntryID    sql.NullInt64
+	// the value the ledger implies, e.g. the sum of the entries
+	Expected int64
+	// the value found, e.g. accounts.balance
+	Actual int64
+	Detail string
+}
+
+type ReconciliationRun struct {
+	ID int64
+	// username of the admin who ran it, or the name of the job
+	StartedBy        string
+	AccountsChecked  int64
+	TransfersChecked int64
+	EntriesChecked   int64
+	DiscrepancyCount int64
+	StartedAt        time.Time
+	FinishedAt       time.Time
 }
 
 type Session struct {
@@ -35,15 +144,31 @@ type Session struct {
 	IsBlocked    bool
 	ExpiresAt    time.Time
 	CreatedAt    time.Time
+	// id of the login session every rotated session descends from
+	FamilyID uuid.UUID
+	// the refresh token was already exchanged for a new one
+	IsRotated bool
 }
 
 type Transfer struct {
 	ID            int64
 	FromAccountID int64
 	ToAccountID   int64
-	// must be positive
+	// must be positive, in the currency of the source account
 	Amount    int64
 	CreatedAt time.Time
+	// must be positive, in the currency of the destination account
+	ToAmount int64
+	// the rate used to convert amount into to_amount, 1 when both accounts share a currency
+	ExchangeRate string
+	// the transfer this one gives money back for, null for ordinary transfers
+	ReversesTransferID sql.NullInt64
+	// free text the sender wrote about the transfer
+	Memo string
+	// reference in another system, e.g. an invoice number
+	ExternalReference string
+	// string keys to string values set by the client
+	Metadata json.RawMessage
 }
 
 type User struct {
@@ -53,4 +178,6 @@ type User struct {
 	Email             string
 	CreatedAt         time.Time
 	PasswordChangedAt time.Time
+	// depositor, banker or admin
+	Role string
 }
@@ -6,28 +6,105 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
 	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error)
+	// Blocks every session of username except those of the family kept, and returns how many logins were revoked
+	BlockOtherUserSessions(ctx context.Context, arg BlockOtherUserSessionsParams) (int64, error)
+	BlockSession(ctx context.Context, id uuid.UUID) error
+	BlockSessionFamily(ctx context.Context, familyID uuid.UUID) error
+	BlockUserSessionFamily(ctx context.Context, arg BlockUserSessionFamilyParams) (int64, error)
+	BlockUserSessions(ctx context.Context, username string) error
+	CloseAccount(ctx context.Context, id int64) (Account, error)
+	CountLedgerRows(ctx context.Context) (CountLedgerRowsRow, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateBalanceAdjustment(ctx context.Context, arg CreateBalanceAdjustmentParams) (BalanceAdjustment, error)
+	// Snapshots up to batch_size accounts that existed by cutoff and have no snapshot of the day yet,
+	// starting from the latest earlier snapshot of each account. Running it again only fills the gaps
+	CreateBalanceSnapshots(ctx context.Context, arg CreateBalanceSnapshotsParams) ([]int64, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error)
+	CreateOverdraftLimitChange(ctx context.Context, arg CreateOverdraftLimitChangeParams) (OverdraftLimitChange, error)
+	CreateReconciliationDiscrepancy(ctx context.Context, arg CreateReconciliationDiscrepancyParams) (ReconciliationDiscrepancy, error)
+	CreateReconciliationRun(ctx context.Context, arg CreateReconciliationRunParams) (ReconciliationRun, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
-	DeleteAccount(ctx context.Context, id int64) error
 	GetAccount(ctx context.Context, id int64) (Account, error)
 	GetAccountForUpdated(ctx context.Context, id int64) (Account, error)
+	GetBalanceSnapshot(ctx context.Context, arg GetBalanceSnapshotParams) (BalanceSnapshot, error)
+	GetCurrency(ctx context.Context, code string) (Currency, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	// Returns the rate of a pair in effect at a given time
+	GetExchangeRate(ctx context.Context, arg GetExchangeRateParams) (ExchangeRate, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	GetReconciliationRun(ctx context.Context, id int64) (ReconciliationRun, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	// Tells whether the login the family descends from was revoked, by a logout, a revocation or a reused refresh token
+	IsSessionFamilyBlocked(ctx context.Context, familyID uuid.UUID) (bool, error)
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
+	// The sessions of username that can still be renewed, one per login: the last refresh token of each family,
+	// with the time of the login that started the family
+	ListActiveSessions(ctx context.Context, arg ListActiveSessionsParams) ([]ListActiveSessionsRow, error)
+	// Balance adjustments whose entry is on another account or of another amount
+	ListAdjustmentMismatches(ctx context.Context) ([]ListAdjustmentMismatchesRow, error)
+	ListBalanceAdjustments(ctx context.Context, arg ListBalanceAdjustmentsParams) ([]BalanceAdjustment, error)
+	// Accounts whose balance isn't the sum of their entries
+	ListBalanceMismatches(ctx context.Context) ([]ListBalanceMismatchesRow, error)
+	ListCurrencies(ctx context.Context) ([]Currency, error)
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	// Walks the chain of an account in the order it was written, with the transfer sealed into each entry
+	ListEntryChain(ctx context.Context, arg ListEntryChainParams) ([]ListEntryChainRow, error)
+	ListExchangeRates(ctx context.Context, arg ListExchangeRatesParams) ([]ExchangeRate, error)
+	// Entries that belong to neither a transfer nor a balance adjustment
+	ListOrphanEntries(ctx context.Context) ([]Entry, error)
+	ListOverdraftLimitChanges(ctx context.Context, arg ListOverdraftLimitChangesParams) ([]OverdraftLimitChange, error)
+	ListOwnerBalancesAsOf(ctx context.Context, arg ListOwnerBalancesAsOfParams) ([]ListOwnerBalancesAsOfRow, error)
+	ListReconciliationDiscrepancies(ctx context.Context, runID int64) ([]ReconciliationDiscrepancy, error)
+	ListReconciliationRuns(ctx context.Context, arg ListReconciliationRunsParams) ([]ReconciliationRun, error)
+	// Balance snapshots that no longer match the entries created before their cutoff
+	ListSnapshotMismatches(ctx context.Context) ([]ListSnapshotMismatchesRow, error)
+	ListStatementEntries(ctx context.Context, arg ListStatementEntriesParams) ([]ListStatementEntriesRow, error)
+	// Transfers that don't have exactly a debit of amount on the source account
+	// and a credit of to_amount on the destination account
+	ListTransferEntriesMismatches(ctx context.Context) ([]ListTransferEntriesMismatchesRow, error)
+	// The filters are optional: memo matches as a case insensitive LIKE pattern,
+	// external_reference exactly, and metadata matches transfers whose metadata contains it
 	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error)
-	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
+	// Taken by every transaction writing entries, shared with the others of the same UTC day. Its entries are stamped
+	// with the start of the transaction, the key is the day they belong to, in days since the epoch
+	LockLedgerDay(ctx context.Context) error
+	MarkSessionRotated(ctx context.Context, id uuid.UUID) (Session, error)
+	SealEntry(ctx context.Context, arg SealEntryParams) (Entry, error)
+	// Transfers sent or received by accounts of owner, newest first. Every filter is optional.
+	// A transfer matches when its sending side is owned and matches the filters, or its receiving side is:
+	// amounts and currency are those of that side and the counterparty is the other account.
+	// Pages continue strictly after the (cursor_created_at, cursor_id) of the last row of the previous page
+	SearchTransfers(ctx context.Context, arg SearchTransfersParams) ([]Transfer, error)
+	// Limits how long the statements of the current transaction wait for a lock
+	SetLockTimeout(ctx context.Context, timeout string) error
+	// Like SumBalanceBefore, but entries created at as_of are included
+	SumBalanceAsOf(ctx context.Context, arg SumBalanceAsOfParams) (int64, error)
+	// Starts from the latest snapshot taken by before and adds the entries created after it
+	SumBalanceBefore(ctx context.Context, arg SumBalanceBeforeParams) (int64, error)
+	// How much of a transfer has been given back so far, amount in the currency of its destination
+	// and to_amount in the currency of its source
+	SumTransferReversals(ctx context.Context, reversesTransferID sql.NullInt64) (SumTransferReversalsRow, error)
+	UpdateAccountEntryHash(ctx context.Context, arg UpdateAccountEntryHashParams) (Account, error)
+	UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error)
+	UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error)
+	UpdateIdempotencyKeyResponse(ctx context.Context, arg UpdateIdempotencyKeyResponseParams) error
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
+	UpsertExchangeRate(ctx context.Context, arg UpsertExchangeRateParams) (ExchangeRate, error)
+	// Waits, up to the lock timeout of the transaction, until no transaction writing entries of day is still open
+	WaitLedgerDay(ctx context.Context, day int32) error
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,50 @@
+package sqlc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcileTx(t *testing.T) {
+	store := NewStore(testDB)
+	account1, account2 := CreateRandomAccountPair(t)
+
+	transfer, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amountOf(account1, 10),
+	})
+	require.NoError(t, err)
+
+	// An entry written outside of a transfer or an adjustment leaves the ledger unbalanced
+	orphan := CreateRandomEntry(t, account1)
+
+	result, err := store.ReconcileTx(context.Background(), "test")
+	require.NoError(t, err)
+	require.NotZero(t, result.Run.ID)
+	require.Equal(t, "test", result.Run.StartedBy)
+	require.Equal(t, int64(len(result.Discrepancies)), result.Run.DiscrepancyCount)
+	require.Positive(t, result.Run.TransfersChecked)
+
+	var foundOrphan, foundBalance bool
+	for _, discrepancy := range result.Discrepancies {
+		require.Equal(t, result.Run.ID, discrepancy.RunID)
+		require.NotEqual(t, transfer.Transfer.ID, discrepancy.TransferID.Int64)
+
+		switch {
+		case discrepancy.Kind == DiscrepancyOrphanEntry && discrepancy.EntryID.Int64 == orphan.ID:
+			foundOrphan = true
+			require.Equal(t, orphan.Amount, discrepancy.Actual)
+		case discrepancy.Kind == DiscrepancyBalance && discrepancy.AccountID.Int64 == account1.ID:
+			foundBalance = true
+		}
+	}
+	require.True(t, foundOrphan)
+	require.True(t, foundBalance)
+
+	discrepancies, err := store.ListReconciliationDiscrepancies(context.Background(), result.Run.ID)
+	require.NoError(t, err)
+	require.Equal(t, result.Discrepancies, discrepancies)
+}
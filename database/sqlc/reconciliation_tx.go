@@ -0,0 +1,186 @@
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Kinds of discrepancy found by ReconcileTx
+const (
+	// DiscrepancyBalance is an account whose balance isn't the sum of its entries
+	DiscrepancyBalance = "balance_mismatch"
+	// DiscrepancyTransferEntries is a transfer without exactly one debit of amount and one credit of to_amount
+	DiscrepancyTransferEntries = "transfer_entries_mismatch"
+	// DiscrepancyOrphanEntry is an entry that belongs to neither a transfer nor a balance adjustment
+	DiscrepancyOrphanEntry = "orphan_entry"
+	// DiscrepancyAdjustment is a balance adjustment whose entry is on another account or of another amount
+	DiscrepancyAdjustment = "adjustment_mismatch"
+	// DiscrepancySnapshot is a balance snapshot that doesn't match the entries before its cutoff
+	DiscrepancySnapshot = "snapshot_mismatch"
+)
+
+// ReconcileTxResult is the result of the reconciliation transaction
+type ReconcileTxResult struct {
+	Run           ReconciliationRun           `json:"run"`
+	Discrepancies []ReconciliationDiscrepancy `json:"discrepancies"`
+}
+
+// ReconcileTx checks that the ledger balances and records the run with every discrepancy found.
+// All checks read the same repeatable read snapshot, so a transfer committing meanwhile is either
+// seen entirely or not at all and can't show up as a discrepancy. startedBy names who asked for the run
+func (s *SQLStore) ReconcileTx(ctx context.Context, startedBy string) (ReconcileTxResult, error) {
+	var txResult ReconcileTxResult
+	startedAt := time.Now()
+
+	var counts CountLedgerRowsRow
+	var found []CreateReconciliationDiscrepancyParams
+	opts := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	err := s.execTxOptions(ctx, opts, func(q *Queries) error {
+		var err error
+		counts, err = q.CountLedgerRows(ctx)
+		if err != nil {
+			return err
+		}
+		found, err = findDiscrepancies(ctx, q)
+		return err
+	})
+	if err != nil {
+		return txResult, err
+	}
+
+	err = s.execTx(ctx, func(q *Queries) error {
+		var err error
+		txResult.Run, err = q.CreateReconciliationRun(ctx, CreateReconciliationRunParams{
+			StartedBy:        startedBy,
+			AccountsChecked:  counts.Accounts,
+			TransfersChecked: counts.Transfers,
+			EntriesChecked:   counts.Entries,
+			DiscrepancyCount: int64(len(found)),
+			StartedAt:        startedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		txResult.Discrepancies = make([]ReconciliationDiscrepancy, 0, len(found))
+		for _, arg := range found {
+			arg.RunID = txResult.Run.ID
+			discrepancy, err := q.CreateReconciliationDiscrepancy(ctx, arg)
+			if err != nil {
+				return err
+			}
+			txResult.Discrepancies = append(txResult.Discrepancies, discrepancy)
+		}
+		return nil
+	})
+	return txResult, err
+}
+
+// findDiscrepancies runs every check and turns each finding into a discrepancy record, RunID is left unset
+func findDiscrepancies(ctx context.Context, q *Queries) ([]CreateReconciliationDiscrepancyParams, error) {
+	var found []CreateReconciliationDiscrepancyParams
+
+	balances, err := q.ListBalanceMismatches(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range balances {
+		found = append(found, CreateReconciliationDiscrepancyParams{
+			Kind:      DiscrepancyBalance,
+			AccountID: validID(row.AccountID),
+			Expected:  row.EntriesBalance,
+			Actual:    row.Balance,
+			Detail:    fmt.Sprintf("balance of account %d is %d but its entries sum to %d", row.AccountID, row.Balance, row.EntriesBalance),
+		})
+	}
+
+	transfers, err := q.ListTransferEntriesMismatches(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range transfers {
+		found = append(found, transferDiscrepancy(row))
+	}
+
+	orphans, err := q.ListOrphanEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range orphans {
+		found = append(found, CreateReconciliationDiscrepancyParams{
+			Kind:      DiscrepancyOrphanEntry,
+			AccountID: validID(entry.AccountID),
+			EntryID:   validID(entry.ID),
+			Expected:  0,
+			Actual:    entry.Amount,
+			Detail:    fmt.Sprintf("entry %d of account %d belongs to no transfer or adjustment", entry.ID, entry.AccountID),
+		})
+	}
+
+	adjustments, err := q.ListAdjustmentMismatches(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range adjustments {
+		found = append(found, CreateReconciliationDiscrepancyParams{
+			Kind:      DiscrepancyAdjustment,
+			AccountID: validID(row.AccountID),
+			EntryID:   validID(row.EntryID),
+			Expected:  row.Amount,
+			Actual:    row.EntryAmount,
+			Detail: fmt.Sprintf("adjustment %d of %d on account %d has entry of %d on account %d",
+				row.AdjustmentID, row.Amount, row.AccountID, row.EntryAmount, row.EntryAccountID),
+		})
+	}
+
+	snapshots, err := q.ListSnapshotMismatches(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range snapshots {
+		found = append(found, CreateReconciliationDiscrepancyParams{
+			Kind:      DiscrepancySnapshot,
+			AccountID: validID(row.AccountID),
+			Expected:  row.EntriesBalance,
+			Actual:    row.Balance,
+			Detail: fmt.Sprintf("snapshot of account %d on %s is %d but the entries before it sum to %d",
+				row.AccountID, row.SnapshotDate.Format(time.DateOnly), row.Balance, row.EntriesBalance),
+		})
+	}
+
+	return found, nil
+}
+
+// transferDiscrepancy reports the first thing wrong with the entries of a transfer
+func transferDiscrepancy(row ListTransferEntriesMismatchesRow) CreateReconciliationDiscrepancyParams {
+	discrepancy := CreateReconciliationDiscrepancyParams{
+		Kind:       DiscrepancyTransferEntries,
+		TransferID: validID(row.TransferID),
+	}
+
+	switch {
+	case row.EntryCount != 2:
+		discrepancy.Expected = 2
+		discrepancy.Actual = row.EntryCount
+		discrepancy.Detail = fmt.Sprintf("transfer %d has %d entries instead of 2", row.TransferID, row.EntryCount)
+	case row.FromEntriesAmount != -row.Amount:
+		discrepancy.AccountID = validID(row.FromAccountID)
+		discrepancy.Expected = -row.Amount
+		discrepancy.Actual = row.FromEntriesAmount
+		discrepancy.Detail = fmt.Sprintf("transfer %d debits account %d by %d instead of %d",
+			row.TransferID, row.FromAccountID, -row.FromEntriesAmount, row.Amount)
+	default:
+		discrepancy.AccountID = validID(row.ToAccountID)
+		discrepancy.Expected = row.ToAmount
+		discrepancy.Actual = row.ToEntriesAmount
+		discrepancy.Detail = fmt.Sprintf("transfer %d credits account %d with %d instead of %d",
+			row.TransferID, row.ToAccountID, row.ToEntriesAmount, row.ToAmount)
+	}
+	return discrepancy
+}
+
+func validID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: true}
+}
@@ -12,6 +12,81 @@ import (
 	"github.com/google/uuid"
 )
 
+const blockOtherUserSessions = `-- name: BlockOtherUserSessions :one
+WITH blocked AS (
+  UPDATE sessions
+  SET is_blocked = true
+  WHERE username = $1 AND family_id <> $2 AND is_blocked = false
+  RETURNING family_id
+)
+SELECT count(DISTINCT family_id) FROM blocked
+`
+
+type BlockOtherUserSessionsParams struct {
+	Username     string
+	KeptFamilyID uuid.UUID
+}
+
+// Blocks every session of username except those of the family kept, and returns how many logins were revoked
+func (q *Queries) BlockOtherUserSessions(ctx context.Context, arg BlockOtherUserSessionsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, blockOtherUserSessions, arg.Username, arg.KeptFamilyID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const blockSession = `-- name: BlockSession :exec
+UPDATE sessions
+SET is_blocked = true
+WHERE id = $1
+`
+
+func (q *Queries) BlockSession(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, blockSession, id)
+	return err
+}
+
+const blockSessionFamily = `-- name: BlockSessionFamily :exec
+UPDATE sessions
+SET is_blocked = true
+WHERE family_id = $1
+`
+
+func (q *Queries) BlockSessionFamily(ctx context.Context, familyID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, blockSessionFamily, familyID)
+	return err
+}
+
+const blockUserSessionFamily = `-- name: BlockUserSessionFamily :execrows
+UPDATE sessions
+SET is_blocked = true
+WHERE family_id = $1 AND username = $2 AND is_blocked = false
+`
+
+type BlockUserSessionFamilyParams struct {
+	FamilyID uuid.UUID
+	Username string
+}
+
+func (q *Queries) BlockUserSessionFamily(ctx context.Context, arg BlockUserSessionFamilyParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, blockUserSessionFamily, arg.FamilyID, arg.Username)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const blockUserSessions = `-- name: BlockUserSessions :exec
+UPDATE sessions
+SET is_blocked = true
+WHERE username = $1 AND is_blocked = false
+`
+
+func (q *Queries) BlockUserSessions(ctx context.Context, username string) error {
+	_, err := q.db.ExecContext(ctx, blockUserSessions, username)
+	return err
+}
+
 const createSession = `-- name: CreateSession :one
 INSERT INTO sessions(
         id,
@@ -20,10 +95,11 @@ INSERT INTO sessions(
         user_agent,
         client_ip,
         is_blocked,
-        expires_at
+        expires_at,
+        family_id
 )VALUES(
-    $1, $2, $3, $4, $5, $6, $7
-) RETURNING id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, family_id, is_rotated
 `
 
 type CreateSessionParams struct {
@@ -34,6 +110,7 @@ type CreateSessionParams struct {
 	ClientIp     string
 	IsBlocked    bool
 	ExpiresAt    time.Time
+	FamilyID     uuid.UUID
 }
 
 func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
@@ -45,6 +122,7 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		arg.ClientIp,
 		arg.IsBlocked,
 		arg.ExpiresAt,
+		arg.FamilyID,
 	)
 	var i Session
 	err := row.Scan(
@@ -56,13 +134,15 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		&i.IsBlocked,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.FamilyID,
+		&i.IsRotated,
 	)
 	return i, err
 }
 
 const getSession = `-- name: GetSession :one
 SELECT
-    id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at
+    id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, family_id, is_rotated
 FROM
     sessions
 WHERE
@@ -83,6 +163,115 @@ func (q *Queries) GetSession(ctx context.Context, id uuid.UUID) (Session, error)
 		&i.IsBlocked,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.FamilyID,
+		&i.IsRotated,
+	)
+	return i, err
+}
+
+const isSessionFamilyBlocked = `-- name: IsSessionFamilyBlocked :one
+SELECT EXISTS(
+  SELECT 1 FROM sessions
+  WHERE family_id = $1 AND is_blocked = true
+)
+`
+
+// Tells whether the login the family descends from was revoked, by a logout, a revocation or a reused refresh token
+func (q *Queries) IsSessionFamilyBlocked(ctx context.Context, familyID uuid.UUID) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isSessionFamilyBlocked, familyID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listActiveSessions = `-- name: ListActiveSessions :many
+SELECT s.id, s.username, s.refresh_token, s.user_agent, s.client_ip, s.is_blocked, s.expires_at, s.created_at, s.family_id, s.is_rotated, f.created_at AS signed_in_at
+FROM sessions s
+JOIN sessions f ON f.id = s.family_id
+WHERE s.username = $1
+  AND s.is_blocked = false
+  AND s.is_rotated = false
+  AND s.expires_at > $2
+ORDER BY s.created_at DESC
+`
+
+type ListActiveSessionsParams struct {
+	Username string
+	Now      time.Time
+}
+
+type ListActiveSessionsRow struct {
+	ID           uuid.UUID
+	Username     string
+	RefreshToken string
+	UserAgent    string
+	ClientIp     string
+	IsBlocked    bool
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+	FamilyID     uuid.UUID
+	IsRotated    bool
+	SignedInAt   time.Time
+}
+
+// The sessions of username that can still be renewed, one per login: the last refresh token of each family,
+// with the time of the login that started the family
+func (q *Queries) ListActiveSessions(ctx context.Context, arg ListActiveSessionsParams) ([]ListActiveSessionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listActiveSessions, arg.Username, arg.Now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListActiveSessionsRow{}
+	for rows.Next() {
+		var i ListActiveSessionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.RefreshToken,
+			&i.UserAgent,
+			&i.ClientIp,
+			&i.IsBlocked,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.FamilyID,
+			&i.IsRotated,
+			&i.SignedInAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markSessionRotated = `-- name: MarkSessionRotated :one
+UPDATE sessions
+SET is_rotated = true
+WHERE id = $1 AND is_rotated = false
+RETURNING id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, family_id, is_rotated
+`
+
+func (q *Queries) MarkSessionRotated(ctx context.Context, id uuid.UUID) (Session, error) {
+	row := q.db.QueryRowContext(ctx, markSessionRotated, id)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.RefreshToken,
+		&i.UserAgent,
+		&i.ClientIp,
+		&i.IsBlocked,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.FamilyID,
+		&i.IsRotated,
 	)
 	return i, err
 }
@@ -0,0 +1,188 @@
+package sqlc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/October-9th/simple-bank/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func CreateRandomSession(t *testing.T, user User) Session {
+	id := uuid.New()
+	arg := CreateSessionParams{
+		ID:           id,
+		Username:     user.Username,
+		RefreshToken: util.RandomString(32),
+		UserAgent:    util.RandomString(10),
+		ClientIp:     "127.0.0.1",
+		ExpiresAt:    time.Now().Add(time.Hour),
+		FamilyID:     id,
+	}
+	session, err := testQueries.CreateSession(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.ID, session.ID)
+	require.Equal(t, arg.FamilyID, session.FamilyID)
+	require.False(t, session.IsBlocked)
+	require.False(t, session.IsRotated)
+	return session
+}
+
+func TestRotateSessionTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := CreateRandomUser(t)
+	session := CreateRandomSession(t, user)
+
+	newSessionParams := func() CreateSessionParams {
+		return CreateSessionParams{
+			ID:           uuid.New(),
+			Username:     user.Username,
+			RefreshToken: util.RandomString(32),
+			ExpiresAt:    time.Now().Add(time.Hour),
+		}
+	}
+
+	result, err := store.RotateSessionTx(context.Background(), RotateSessionTxParams{
+		SessionID:  session.ID,
+		FamilyID:   session.FamilyID,
+		NewSession: newSessionParams(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, session.FamilyID, result.Session.FamilyID)
+
+	rotated, err := store.GetSession(context.Background(), session.ID)
+	require.NoError(t, err)
+	require.True(t, rotated.IsRotated)
+
+	// Rotating the old session again is a replay, the whole family gets blocked
+	_, err = store.RotateSessionTx(context.Background(), RotateSessionTxParams{
+		SessionID:  session.ID,
+		FamilyID:   session.FamilyID,
+		NewSession: newSessionParams(),
+	})
+	require.ErrorIs(t, err, ErrRefreshTokenReused)
+
+	for _, id := range []uuid.UUID{session.ID, result.Session.ID} {
+		blocked, err := store.GetSession(context.Background(), id)
+		require.NoError(t, err)
+		require.True(t, blocked.IsBlocked)
+	}
+}
+
+func TestRotateSessionTxFamilyDeadline(t *testing.T) {
+	store := NewStore(testDB)
+	user := CreateRandomUser(t)
+	login := CreateRandomSession(t, user)
+
+	// However often it is renewed, a family doesn't outlive its login
+	result, err := store.RotateSessionTx(context.Background(), RotateSessionTxParams{
+		SessionID: login.ID,
+		FamilyID:  login.FamilyID,
+		NewSession: CreateSessionParams{
+			ID:           uuid.New(),
+			Username:     user.Username,
+			RefreshToken: util.RandomString(32),
+			ExpiresAt:    time.Now().Add(24 * time.Hour),
+		},
+	})
+	require.NoError(t, err)
+	require.WithinDuration(t, login.ExpiresAt, result.Session.ExpiresAt, time.Millisecond)
+
+	// Once the login has expired the family can't be renewed anymore
+	id := uuid.New()
+	expired, err := testQueries.CreateSession(context.Background(), CreateSessionParams{
+		ID:           id,
+		Username:     user.Username,
+		RefreshToken: util.RandomString(32),
+		ExpiresAt:    time.Now().Add(-time.Minute),
+		FamilyID:     id,
+	})
+	require.NoError(t, err)
+
+	_, err = store.RotateSessionTx(context.Background(), RotateSessionTxParams{
+		SessionID: expired.ID,
+		FamilyID:  expired.FamilyID,
+		NewSession: CreateSessionParams{
+			ID:           uuid.New(),
+			Username:     user.Username,
+			RefreshToken: util.RandomString(32),
+			ExpiresAt:    time.Now().Add(time.Hour),
+		},
+	})
+	require.ErrorIs(t, err, ErrSessionFamilyExpired)
+
+	notRotated, err := store.GetSession(context.Background(), expired.ID)
+	require.NoError(t, err)
+	require.False(t, notRotated.IsRotated)
+}
+
+func TestListAndRevokeSessions(t *testing.T) {
+	store := NewStore(testDB)
+	user := CreateRandomUser(t)
+	current := CreateRandomSession(t, user)
+	other := CreateRandomSession(t, user)
+	CreateRandomSession(t, CreateRandomUser(t))
+
+	// A renewed session is listed once, with its last refresh token and the time of the login
+	renewed, err := store.RotateSessionTx(context.Background(), RotateSessionTxParams{
+		SessionID: other.ID,
+		FamilyID:  other.FamilyID,
+		NewSession: CreateSessionParams{
+			ID:           uuid.New(),
+			Username:     user.Username,
+			RefreshToken: util.RandomString(32),
+			ExpiresAt:    time.Now().Add(time.Hour),
+		},
+	})
+	require.NoError(t, err)
+
+	sessions, err := store.ListActiveSessions(context.Background(), ListActiveSessionsParams{
+		Username: user.Username,
+		Now:      time.Now(),
+	})
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	require.Equal(t, renewed.Session.ID, sessions[0].ID)
+	require.Equal(t, other.FamilyID, sessions[0].FamilyID)
+	require.WithinDuration(t, other.CreatedAt, sessions[0].SignedInAt, time.Second)
+	require.Equal(t, current.ID, sessions[1].ID)
+
+	// Sessions of someone else can't be revoked
+	blocked, err := store.BlockUserSessionFamily(context.Background(), BlockUserSessionFamilyParams{
+		FamilyID: current.FamilyID,
+		Username: util.RandomOwner(),
+	})
+	require.NoError(t, err)
+	require.Zero(t, blocked)
+
+	revoked, err := store.BlockOtherUserSessions(context.Background(), BlockOtherUserSessionsParams{
+		Username:     user.Username,
+		KeptFamilyID: current.FamilyID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), revoked)
+
+	sessions, err = store.ListActiveSessions(context.Background(), ListActiveSessionsParams{
+		Username: user.Username,
+		Now:      time.Now(),
+	})
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, current.ID, sessions[0].ID)
+
+	blocked, err = store.BlockUserSessionFamily(context.Background(), BlockUserSessionFamilyParams{
+		FamilyID: current.FamilyID,
+		Username: user.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), blocked)
+
+	sessions, err = store.ListActiveSessions(context.Background(), ListActiveSessionsParams{
+		Username: user.Username,
+		Now:      time.Now(),
+	})
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+}
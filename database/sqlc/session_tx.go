@@ -0,0 +1,77 @@
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrRefreshTokenReused is returned by RotateSessionTx when the session was already rotated,
+// which means an old refresh token is being replayed. The whole session family is blocked
+var ErrRefreshTokenReused = errors.New("refresh token has already been used")
+
+// ErrSessionFamilyExpired is returned by RotateSessionTx when the login the family descends from has expired,
+// the user has to log in again however recently the session was renewed
+var ErrSessionFamilyExpired = errors.New("session has expired, log in again")
+
+// RotateSessionTxParams contains the input parameters of the rotate session transaction
+type RotateSessionTxParams struct {
+	// The session of the refresh token being exchanged
+	SessionID uuid.UUID
+	FamilyID  uuid.UUID
+	// The session of the new refresh token, its FamilyID is set by the transaction
+	// and its ExpiresAt is capped to the one of the family
+	NewSession CreateSessionParams
+}
+
+// RotateSessionTxResult is the result of the rotate session transaction
+type RotateSessionTxResult struct {
+	Session Session
+}
+
+// RotateSessionTx marks a session as rotated and creates its successor in the same family.
+// If the session was already rotated, every session of the family is blocked instead.
+// No session outlives the first one of its family, the login, so renewing can't keep a family alive forever
+func (s *SQLStore) RotateSessionTx(ctx context.Context, arg RotateSessionTxParams) (RotateSessionTxResult, error) {
+	var txResult RotateSessionTxResult
+	reused := false
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		// Only one concurrent renewal can flip the flag, the others see no row
+		_, err := q.MarkSessionRotated(ctx, arg.SessionID)
+		if err == sql.ErrNoRows {
+			reused = true
+			return q.BlockSessionFamily(ctx, arg.FamilyID)
+		}
+		if err != nil {
+			return err
+		}
+
+		// The first session of the family has the id of the family
+		family, err := q.GetSession(ctx, arg.FamilyID)
+		if err != nil {
+			return err
+		}
+		if !family.ExpiresAt.After(time.Now()) {
+			return ErrSessionFamilyExpired
+		}
+		if arg.NewSession.ExpiresAt.After(family.ExpiresAt) {
+			arg.NewSession.ExpiresAt = family.ExpiresAt
+		}
+
+		arg.NewSession.FamilyID = arg.FamilyID
+		txResult.Session, err = q.CreateSession(ctx, arg.NewSession)
+		return err
+	})
+	if err != nil {
+		return txResult, err
+	}
+	// The block has to be committed, so the error is only reported afterwards
+	if reused {
+		return txResult, ErrRefreshTokenReused
+	}
+	return txResult, nil
+}
@@ -0,0 +1,65 @@
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// StatementTxParams contains the input parameters of the statement transaction.
+// The period includes From and excludes To
+type StatementTxParams struct {
+	AccountID int64     `json:"account_id"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+}
+
+// StatementTxResult is the result of the statement transaction
+type StatementTxResult struct {
+	Account        Account                   `json:"account"`
+	OpeningBalance int64                     `json:"opening_balance"` // sum of every entry before From
+	ClosingBalance int64                     `json:"closing_balance"` // opening balance plus the entries of the period
+	Entries        []ListStatementEntriesRow `json:"entries"`
+}
+
+// StatementTx reads the entries of an account in a period together with the balances around them.
+// The balances are summed from the latest balance snapshot and the entries after it rather than read from accounts.balance,
+// which only knows the present.
+// Everything is read from one repeatable read snapshot, so a transfer committed meanwhile can't unbalance the statement
+func (s *SQLStore) StatementTx(ctx context.Context, arg StatementTxParams) (StatementTxResult, error) {
+	var txResult StatementTxResult
+
+	opts := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	err := s.execTxOptions(ctx, opts, func(q *Queries) error {
+		var err error
+
+		txResult.Account, err = q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		txResult.OpeningBalance, err = q.SumBalanceBefore(ctx, SumBalanceBeforeParams{
+			AccountID: arg.AccountID,
+			Before:    arg.From,
+		})
+		if err != nil {
+			return err
+		}
+
+		txResult.Entries, err = q.ListStatementEntries(ctx, ListStatementEntriesParams{
+			AccountID: arg.AccountID,
+			FromTime:  arg.From,
+			ToTime:    arg.To,
+		})
+		if err != nil {
+			return err
+		}
+
+		txResult.ClosingBalance = txResult.OpeningBalance
+		for _, entry := range txResult.Entries {
+			txResult.ClosingBalance += entry.Amount
+		}
+		return nil
+	})
+	return txResult, err
+}
@@ -3,27 +3,84 @@ package sqlc
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+
+	"github.com/October-9th/simple-bank/fx"
+	"github.com/October-9th/simple-bank/money"
+	"github.com/lib/pq"
 )
 
+// ErrInsufficientFunds is returned by TransferTx when the transfer would take the
+// source account's balance below its overdraft limit
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// ErrInvalidAmount is returned when a transfer amount isn't positive or an adjustment amount is zero
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// ErrSameAccount is returned when a transfer's source and destination are the same account
+var ErrSameAccount = errors.New("cannot transfer to the same account")
+
+// asOverflow turns the error Postgres raises when a balance no longer fits in a bigint into money.ErrOverflow
+func asOverflow(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code.Name() == "numeric_value_out_of_range" {
+		return money.ErrOverflow
+	}
+	return err
+}
+
 // Store interface provides all function to execute database queries and transactions
 type Store interface {
 	Querier
 	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+	ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error)
+	RotateSessionTx(ctx context.Context, arg RotateSessionTxParams) (RotateSessionTxResult, error)
+	CloseAccountTx(ctx context.Context, accountID int64) (Account, error)
+	FreezeAccountTx(ctx context.Context, accountID int64) (Account, error)
+	UnfreezeAccountTx(ctx context.Context, accountID int64) (Account, error)
+	LoadExchangeRatesTx(ctx context.Context, rates []fx.Rate) ([]ExchangeRate, error)
+	AdjustBalanceTx(ctx context.Context, arg AdjustBalanceTxParams) (AdjustBalanceTxResult, error)
+	UpdateOverdraftLimitTx(ctx context.Context, arg UpdateOverdraftLimitTxParams) (UpdateOverdraftLimitTxResult, error)
+	StatementTx(ctx context.Context, arg StatementTxParams) (StatementTxResult, error)
+	GetBalanceAsOf(ctx context.Context, arg GetBalanceAsOfParams) (AccountBalance, error)
+	ListBalancesAsOf(ctx context.Context, arg ListBalancesAsOfParams) ([]AccountBalance, error)
+	SnapshotBalances(ctx context.Context, arg SnapshotBalancesParams) (int64, error)
+	ReconcileTx(ctx context.Context, startedBy string) (ReconcileTxResult, error)
+	VerifyEntryChain(ctx context.Context, accountID int64) (EntryChainResult, error)
 }
 
 // Store provides all function to execute SQL queries and transactions
 type SQLStore struct {
 	*Queries
 	db *sql.DB // Required for creating database transactions
+	// entryHashKey keys the hashes chaining the entries of each account
+	entryHashKey []byte
+}
+
+// StoreOption configures a SQLStore
+type StoreOption func(*SQLStore)
+
+// WithEntryHashKey keys the entry chain hashes with key, the same key must be used to verify them.
+// Without it the chain is a plain SHA-256 chain, which catches accidental changes but not a rewrite of the chain
+func WithEntryHashKey(key []byte) StoreOption {
+	return func(s *SQLStore) {
+		s.entryHashKey = key
+	}
 }
 
 // NewStore creates a new store
-func NewStore(db *sql.DB) Store {
-	return &SQLStore{
+func NewStore(db *sql.DB, options ...StoreOption) Store {
+	store := &SQLStore{
 		Queries: New(db),
 		db:      db,
 	}
+	for _, option := range options {
+		option(store)
+	}
+	return store
 }
 
 // This function take the context and a call back function as input,
@@ -31,7 +88,12 @@ func NewStore(db *sql.DB) Store {
 // and call the callback function with the created query -> finally commit or rollback the transaction
 // based on the error returnd by the callback function
 func (s *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
-	tx, err := s.db.BeginTx(ctx, nil)
+	return s.execTxOptions(ctx, nil, fn)
+}
+
+// execTxOptions is execTx with explicit transaction options, e.g. a read only snapshot
+func (s *SQLStore) execTxOptions(ctx context.Context, opts *sql.TxOptions, fn func(*Queries) error) error {
+	tx, err := s.db.BeginTx(ctx, opts)
 
 	if err != nil {
 		return err
@@ -55,7 +117,19 @@ func (s *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
 type TransferTxParams struct {
 	FromAccountID int64 `json:"from_account_id"`
 	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	// Amount is taken from the source account, so it has to be in its currency
+	Amount money.Amount `json:"amount"`
+
+	// Memo, ExternalReference and Metadata are optional details kept with the transfer.
+	// They are left out of the idempotency hash when empty, so keys stored before they existed still match
+	Memo              string            `json:"memo,omitempty"`
+	ExternalReference string            `json:"external_reference,omitempty"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+
+	// IdempotencyKey is optional. When set, a retry with the same key and parameters returns
+	// the original result instead of transferring again. Keys are scoped to Username
+	IdempotencyKey string `json:"-"`
+	Username       string `json:"-"`
 }
 
 // TransferTxResult is the result of the transfer transaction
@@ -68,51 +142,132 @@ type TransferTxResult struct {
 }
 
 // TransferTx performs a money transfer from one account to another
-// It creates a transfer record, add account entries and update account's balance within a single database transaction
+// It creates a transfer record, add account entries and update account's balance within a single database transaction.
+// Each entry is sealed onto its account's hash chain, see appendEntry.
+// When the accounts have different currencies, the destination is credited with the amount converted at the latest rate
 func (s *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
 	var txResult TransferTxResult
 
+	// A negative amount would silently move money the other way
+	if !arg.Amount.IsPositive() {
+		return txResult, fmt.Errorf("%w: transfer amount must be positive", ErrInvalidAmount)
+	}
+	// Both entries would be sealed onto the same chain from the same head, breaking it
+	if arg.FromAccountID == arg.ToAccountID {
+		return txResult, ErrSameAccount
+	}
+
 	err := s.execTx(ctx, func(q *Queries) error {
+		// Entries of the day can't be snapshotted while this transaction may still write some
+		if err := q.LockLedgerDay(ctx); err != nil {
+			return err
+		}
+
 		var err error
 
-		// First step create a transfer record
-		createTransferParams := CreateTransferParams(arg)
+		// Claim the idempotency key before touching any balance, a replay stops here
+		if arg.IdempotencyKey != "" {
+			replayed, err := claimIdempotencyKey(ctx, q, arg, &txResult)
+			if err != nil || replayed {
+				return err
+			}
+		}
 
-		txResult.Transfer, err = q.CreateTransfer(ctx, createTransferParams)
+		// Accounts in different currencies are credited with the converted amount
+		toAmount, exchangeRate, err := convertTransferAmount(ctx, q, arg)
 		if err != nil {
 			return err
 		}
 
-		// Second step add account entry
-		txResult.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountID,
-			Amount:    -arg.Amount,
-		})
+		metadata, err := transferMetadata(arg.Metadata)
 		if err != nil {
 			return err
 		}
 
-		txResult.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountID,
-			Amount:    arg.Amount,
+		// First step create a transfer record
+		txResult.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+			FromAccountID:     arg.FromAccountID,
+			ToAccountID:       arg.ToAccountID,
+			Amount:            arg.Amount.Minor,
+			ToAmount:          toAmount.Minor,
+			ExchangeRate:      exchangeRate,
+			Memo:              arg.Memo,
+			ExternalReference: arg.ExternalReference,
+			Metadata:          metadata,
 		})
 		if err != nil {
 			return err
 		}
 
-		// Last step updating account balance, invoking locking and preventing deadlock
+		// Second step updating account balance, invoking locking and preventing deadlock
 		// Step: get account -> update its balance
 
 		if arg.FromAccountID < arg.ToAccountID {
-			txResult.FromAccount, txResult.ToAccount, err = UpdateAccountBalance(arg.FromAccountID, arg.ToAccountID, -arg.Amount, arg.Amount, ctx, q)
+			txResult.FromAccount, txResult.ToAccount, err = UpdateAccountBalance(arg.FromAccountID, arg.ToAccountID, -arg.Amount.Minor, toAmount.Minor, ctx, q)
 		} else {
-			txResult.ToAccount, txResult.FromAccount, err = UpdateAccountBalance(arg.ToAccountID, arg.FromAccountID, arg.Amount, -arg.Amount, ctx, q)
+			txResult.ToAccount, txResult.FromAccount, err = UpdateAccountBalance(arg.ToAccountID, arg.FromAccountID, toAmount.Minor, -arg.Amount.Minor, ctx, q)
 		}
-		return err
+		if err != nil {
+			return asOverflow(err)
+		}
+
+		// Last step add account entries. They come after the balance update so both accounts are locked
+		// and no concurrent transfer can extend their chains in between
+		txResult.FromEntry, txResult.FromAccount, err = s.appendEntry(ctx, q, txResult.FromAccount, CreateEntryParams{
+			AccountID:  arg.FromAccountID,
+			Amount:     -arg.Amount.Minor,
+			TransferID: sql.NullInt64{Int64: txResult.Transfer.ID, Valid: true},
+		}, &txResult.Transfer)
+		if err != nil {
+			return err
+		}
+
+		txResult.ToEntry, txResult.ToAccount, err = s.appendEntry(ctx, q, txResult.ToAccount, CreateEntryParams{
+			AccountID:  arg.ToAccountID,
+			Amount:     toAmount.Minor,
+			TransferID: sql.NullInt64{Int64: txResult.Transfer.ID, Valid: true},
+		}, &txResult.Transfer)
+		if err != nil {
+			return err
+		}
+
+		// Both rows are locked by now, so the updated balance is the one every concurrent transfer will see.
+		// Returning an error rolls the whole transaction back, including the transfer and entries
+		// Frozen and closed accounts can neither send nor receive
+		if err := checkAccountActive(txResult.FromAccount); err != nil {
+			return err
+		}
+		if err := checkAccountActive(txResult.ToAccount); err != nil {
+			return err
+		}
+		if txResult.FromAccount.Balance < -txResult.FromAccount.OverdraftLimit {
+			return ErrInsufficientFunds
+		}
+
+		if arg.IdempotencyKey != "" {
+			return saveIdempotentResult(ctx, q, arg, txResult)
+		}
+		return nil
 	})
 	return txResult, err
 
 }
+
+// transferMetadata encodes the metadata of a transfer for its jsonb column, no metadata is stored as an empty object
+func transferMetadata(metadata map[string]string) (json.RawMessage, error) {
+	if len(metadata) == 0 {
+		return json.RawMessage("{}"), nil
+	}
+	return json.Marshal(metadata)
+}
+
+// ContainsPattern turns text into a LIKE pattern matching any value containing it, wildcards in text match literally
+func ContainsPattern(text string) string {
+	return "%" + likeEscaper.Replace(text) + "%"
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 func UpdateAccountBalance(
 	accountID_1,
 	accountID_2 int64,
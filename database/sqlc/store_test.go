@@ -5,7 +5,11 @@ import (
 	"fmt"
 	"log"
 	"testing"
+	"time"
 
+	"github.com/October-9th/simple-bank/fx"
+	"github.com/October-9th/simple-bank/money"
+	"github.com/October-9th/simple-bank/util"
 	"github.com/stretchr/testify/require"
 )
 
@@ -13,7 +17,7 @@ func TestTransferTx(t *testing.T) {
 
 	store := NewStore(testDB)
 	// Create account for transaction
-	account_1, account_2 := CreateRandomAccount(t), CreateRandomAccount(t)
+	account_1, account_2 := CreateRandomAccountPair(t)
 	fmt.Println(">> Before: ", account_1.Balance, account_2.Balance)
 	// Run n concurrent transfer transactions
 	n := 10
@@ -36,7 +40,7 @@ func TestTransferTx(t *testing.T) {
 			result, err := store.TransferTx(context.Background(), TransferTxParams{
 				FromAccountID: account_1.ID,
 				ToAccountID:   account_2.ID,
-				Amount:        amount,
+				Amount:        amountOf(account_1, amount),
 			})
 			errors <- err
 			results <- result
@@ -65,6 +69,7 @@ func TestTransferTx(t *testing.T) {
 		require.NotEmpty(t, fromEntry)
 		require.Equal(t, account_1.ID, fromEntry.AccountID)
 		require.Equal(t, -amount, fromEntry.Amount)
+		require.Equal(t, transfer.ID, fromEntry.TransferID.Int64)
 		require.NotZero(t, fromEntry.ID)
 		require.NotZero(t, fromEntry.CreatedAt)
 		_, err = store.GetEntry(context.Background(), fromEntry.ID)
@@ -75,6 +80,7 @@ func TestTransferTx(t *testing.T) {
 		require.NotEmpty(t, toEntry)
 		require.Equal(t, account_2.ID, toEntry.AccountID)
 		require.Equal(t, amount, toEntry.Amount)
+		require.Equal(t, transfer.ID, toEntry.TransferID.Int64)
 		require.NotZero(t, toEntry.ID)
 		require.NotZero(t, toEntry.CreatedAt)
 		_, err = store.GetEntry(context.Background(), toEntry.ID)
@@ -118,7 +124,7 @@ func TestTransferTxDeadlock(t *testing.T) {
 	store := NewStore(testDB)
 
 	// Create account for transaction
-	account_1, account_2 := CreateRandomAccount(t), CreateRandomAccount(t)
+	account_1, account_2 := CreateRandomAccountPair(t)
 	log.Println(">> Before: ", account_1.Balance, account_2.Balance)
 	// Run n concurrent transfer transactions
 	n := 10
@@ -142,7 +148,7 @@ func TestTransferTxDeadlock(t *testing.T) {
 			_, err := store.TransferTx(context.Background(), TransferTxParams{
 				FromAccountID: fromAccountId,
 				ToAccountID:   toAccountId,
-				Amount:        amount,
+				Amount:        amountOf(account_1, amount),
 			})
 
 			errors <- err
@@ -165,3 +171,136 @@ func TestTransferTxDeadlock(t *testing.T) {
 	require.Equal(t, account_1.Balance, updatedAccount_1.Balance)
 	require.Equal(t, account_2.Balance, updatedAccount_2.Balance)
 }
+
+func TestTransferTxInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+
+	account_1, account_2 := CreateRandomAccountPair(t)
+
+	// Transfer one more than the balance, no overdraft allowed by default
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account_1.ID,
+		ToAccountID:   account_2.ID,
+		Amount:        amountOf(account_1, account_1.Balance+1),
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// Nothing should have been written
+	updatedAccount_1, err := store.GetAccount(context.Background(), account_1.ID)
+	require.NoError(t, err)
+	updatedAccount_2, err := store.GetAccount(context.Background(), account_2.ID)
+	require.NoError(t, err)
+	require.Equal(t, account_1.Balance, updatedAccount_1.Balance)
+	require.Equal(t, account_2.Balance, updatedAccount_2.Balance)
+
+	// With an overdraft limit the same transfer goes through
+	_, err = store.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{
+		ID:             account_1.ID,
+		OverdraftLimit: 1,
+	})
+	require.NoError(t, err)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account_1.ID,
+		ToAccountID:   account_2.ID,
+		Amount:        amountOf(account_1, account_1.Balance+1),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(-1), result.FromAccount.Balance)
+}
+
+func TestTransferTxIdempotent(t *testing.T) {
+	store := NewStore(testDB)
+
+	account_1, account_2 := CreateRandomAccountPair(t)
+	arg := TransferTxParams{
+		FromAccountID:  account_1.ID,
+		ToAccountID:    account_2.ID,
+		Amount:         amountOf(account_1, 10),
+		IdempotencyKey: util.RandomString(16),
+		Username:       account_1.Owner,
+	}
+
+	// Run the same request concurrently, only one of them should move money
+	n := 5
+	errs := make(chan error)
+	results := make(chan TransferTxResult)
+	for i := 0; i < n; i++ {
+		go func() {
+			result, err := store.TransferTx(context.Background(), arg)
+			errs <- err
+			results <- result
+		}()
+	}
+
+	var first TransferTxResult
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+		result := <-results
+		if i == 0 {
+			first = result
+		}
+		require.Equal(t, first.Transfer.ID, result.Transfer.ID)
+	}
+
+	updatedAccount_1, err := store.GetAccount(context.Background(), account_1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account_1.Balance-arg.Amount.Minor, updatedAccount_1.Balance)
+
+	// Same key with a different amount is rejected
+	arg.Amount = amountOf(account_1, 20)
+	_, err = store.TransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrIdempotencyKeyReused)
+}
+
+func TestTransferTxExchangeRate(t *testing.T) {
+	store := NewStore(testDB)
+	account_1 := CreateRandomAccountWithCurrency(t, util.USD)
+	account_2 := CreateRandomAccountWithCurrency(t, util.EUR)
+
+	// A rate that is not in effect yet must not be used
+	rates, err := store.LoadExchangeRatesTx(context.Background(), []fx.Rate{
+		{Base: util.USD, Quote: util.EUR, Rate: "0.5", EffectiveAt: time.Now().Add(-time.Second)},
+		{Base: util.USD, Quote: util.EUR, Rate: "0.9", EffectiveAt: time.Now().AddDate(100, 0, 0)},
+	})
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account_1.ID,
+		ToAccountID:   account_2.ID,
+		Amount:        amountOf(account_1, 10),
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, int64(10), result.Transfer.Amount)
+	require.Equal(t, int64(5), result.Transfer.ToAmount)
+	require.Equal(t, "0.5000000000", result.Transfer.ExchangeRate)
+	require.Equal(t, int64(-10), result.FromEntry.Amount)
+	require.Equal(t, int64(5), result.ToEntry.Amount)
+	require.Equal(t, account_1.Balance-10, result.FromAccount.Balance)
+	require.Equal(t, account_2.Balance+5, result.ToAccount.Balance)
+
+	// Rates are quoted for major units, 0.50 USD at 145.5 is 72.75 JPY which rounds to 73
+	account_3 := CreateRandomAccountWithCurrency(t, util.JPY)
+	_, err = store.LoadExchangeRatesTx(context.Background(), []fx.Rate{
+		{Base: util.USD, Quote: util.JPY, Rate: "145.5", EffectiveAt: time.Now().Add(-time.Second)},
+	})
+	require.NoError(t, err)
+
+	result, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account_1.ID,
+		ToAccountID:   account_3.ID,
+		Amount:        amountOf(account_1, 50),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(73), result.Transfer.ToAmount)
+
+	// The amount is in the source account's currency, not the destination's
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account_1.ID,
+		ToAccountID:   account_2.ID,
+		Amount:        amountOf(account_2, 10),
+	})
+	require.ErrorIs(t, err, money.ErrCurrencyMismatch)
+}
@@ -7,26 +7,50 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 )
 
 const createTransfer = `-- name: CreateTransfer :one
 INSERT INTO transfer (
   from_account_id,
   to_account_id,
-  amount
+  amount,
+  to_amount,
+  exchange_rate,
+  reverses_transfer_id,
+  memo,
+  external_reference,
+  metadata
 ) VALUES (
-  $1, $2, $3
-) RETURNING id, from_account_id, to_account_id, amount, created_at
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+) RETURNING id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, reverses_transfer_id, memo, external_reference, metadata
 `
 
 type CreateTransferParams struct {
-	FromAccountID int64
-	ToAccountID   int64
-	Amount        int64
+	FromAccountID      int64
+	ToAccountID        int64
+	Amount             int64
+	ToAmount           int64
+	ExchangeRate       string
+	ReversesTransferID sql.NullInt64
+	Memo               string
+	ExternalReference  string
+	Metadata           json.RawMessage
 }
 
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
-	row := q.db.QueryRowContext(ctx, createTransfer, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.ToAmount,
+		arg.ExchangeRate,
+		arg.ReversesTransferID,
+		arg.Memo,
+		arg.ExternalReference,
+		arg.Metadata,
+	)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -34,12 +58,18 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.ReversesTransferID,
+		&i.Memo,
+		&i.ExternalReference,
+		&i.Metadata,
 	)
 	return i, err
 }
 
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfer
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, reverses_transfer_id, memo, external_reference, metadata FROM transfer
 WHERE id = $1 LIMIT 1
 `
 
@@ -52,33 +82,178 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.ReversesTransferID,
+		&i.Memo,
+		&i.ExternalReference,
+		&i.Metadata,
+	)
+	return i, err
+}
+
+const getTransferForUpdate = `-- name: GetTransferForUpdate :one
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, reverses_transfer_id, memo, external_reference, metadata FROM transfer
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, getTransferForUpdate, id)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.ReversesTransferID,
+		&i.Memo,
+		&i.ExternalReference,
+		&i.Metadata,
 	)
 	return i, err
 }
 
 const listTransfers = `-- name: ListTransfers :many
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfer
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, reverses_transfer_id, memo, external_reference, metadata FROM transfer
 WHERE 
-    from_account_id = $1 OR
-    to_account_id = $2
+    (from_account_id = $1 OR
+    to_account_id = $2)
+    AND ($3::varchar IS NULL OR memo ILIKE $3)
+    AND ($4::varchar IS NULL OR external_reference = $4)
+    AND ($5::text IS NULL OR metadata @> $5::text::jsonb)
 ORDER BY id
-LIMIT $3
-OFFSET $4
+LIMIT $7
+OFFSET $6
 `
 
 type ListTransfersParams struct {
-	FromAccountID int64
-	ToAccountID   int64
-	Limit         int32
-	Offset        int32
+	FromAccountID     int64
+	ToAccountID       int64
+	Memo              sql.NullString
+	ExternalReference sql.NullString
+	Metadata          sql.NullString
+	Offset            int32
+	Limit             int32
 }
 
+// The filters are optional: memo matches as a case insensitive LIKE pattern,
+// external_reference exactly, and metadata matches transfers whose metadata contains it
 func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error) {
 	rows, err := q.db.QueryContext(ctx, listTransfers,
 		arg.FromAccountID,
 		arg.ToAccountID,
-		arg.Limit,
+		arg.Memo,
+		arg.ExternalReference,
+		arg.Metadata,
 		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transfer{}
+	for rows.Next() {
+		var i Transfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.ToAmount,
+			&i.ExchangeRate,
+			&i.ReversesTransferID,
+			&i.Memo,
+			&i.ExternalReference,
+			&i.Metadata,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchTransfers = `-- name: SearchTransfers :many
+SELECT t.id, t.from_account_id, t.to_account_id, t.amount, t.created_at, t.to_amount, t.exchange_rate, t.reverses_transfer_id, t.memo, t.external_reference, t.metadata FROM transfer t
+JOIN accounts fa ON fa.id = t.from_account_id
+JOIN accounts ta ON ta.id = t.to_account_id
+WHERE (
+    (
+      $1::varchar IS DISTINCT FROM 'received'
+      AND fa.owner = $2
+      AND ($3::bigint IS NULL OR t.from_account_id = $3::bigint)
+      AND ($4::bigint IS NULL OR t.to_account_id = $4::bigint)
+      AND ($5::bigint IS NULL OR t.amount >= $5::bigint)
+      AND ($6::bigint IS NULL OR t.amount <= $6::bigint)
+      AND ($7::varchar IS NULL OR fa.currency = $7::varchar)
+    ) OR (
+      $1::varchar IS DISTINCT FROM 'sent'
+      AND ta.owner = $2
+      AND ($3::bigint IS NULL OR t.to_account_id = $3::bigint)
+      AND ($4::bigint IS NULL OR t.from_account_id = $4::bigint)
+      AND ($5::bigint IS NULL OR t.to_amount >= $5::bigint)
+      AND ($6::bigint IS NULL OR t.to_amount <= $6::bigint)
+      AND ($7::varchar IS NULL OR ta.currency = $7::varchar)
+    )
+  )
+  AND ($8::timestamptz IS NULL OR t.created_at >= $8::timestamptz)
+  AND ($9::timestamptz IS NULL OR t.created_at < $9::timestamptz)
+  AND ($10::varchar IS NULL OR t.memo ILIKE $10::varchar)
+  AND (
+    $11::timestamptz IS NULL
+    OR (t.created_at, t.id) < ($11::timestamptz, $12::bigint)
+  )
+ORDER BY t.created_at DESC, t.id DESC
+LIMIT $13
+`
+
+type SearchTransfersParams struct {
+	Direction             sql.NullString
+	Owner                 string
+	AccountID             sql.NullInt64
+	CounterpartyAccountID sql.NullInt64
+	MinAmount             sql.NullInt64
+	MaxAmount             sql.NullInt64
+	Currency              sql.NullString
+	FromTime              sql.NullTime
+	ToTime                sql.NullTime
+	Memo                  sql.NullString
+	CursorCreatedAt       sql.NullTime
+	CursorID              sql.NullInt64
+	LimitCount            int32
+}
+
+// Transfers sent or received by accounts of owner, newest first. Every filter is optional.
+// A transfer matches when its sending side is owned and matches the filters, or its receiving side is:
+// amounts and currency are those of that side and the counterparty is the other account.
+// Pages continue strictly after the (cursor_created_at, cursor_id) of the last row of the previous page
+func (q *Queries) SearchTransfers(ctx context.Context, arg SearchTransfersParams) ([]Transfer, error) {
+	rows, err := q.db.QueryContext(ctx, searchTransfers,
+		arg.Direction,
+		arg.Owner,
+		arg.AccountID,
+		arg.CounterpartyAccountID,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.Currency,
+		arg.FromTime,
+		arg.ToTime,
+		arg.Memo,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.LimitCount,
 	)
 	if err != nil {
 		return nil, err
@@ -93,6 +268,12 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.ToAmount,
+			&i.ExchangeRate,
+			&i.ReversesTransferID,
+			&i.Memo,
+			&i.ExternalReference,
+			&i.Metadata,
 		); err != nil {
 			return nil, err
 		}
@@ -106,3 +287,25 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 	}
 	return items, nil
 }
+
+const sumTransferReversals = `-- name: SumTransferReversals :one
+SELECT
+  COALESCE(SUM(amount), 0)::bigint AS amount,
+  COALESCE(SUM(to_amount), 0)::bigint AS to_amount
+FROM transfer
+WHERE reverses_transfer_id = $1
+`
+
+type SumTransferReversalsRow struct {
+	Amount   int64
+	ToAmount int64
+}
+
+// How much of a transfer has been given back so far, amount in the currency of its destination
+// and to_amount in the currency of its source
+func (q *Queries) SumTransferReversals(ctx context.Context, reversesTransferID sql.NullInt64) (SumTransferReversalsRow, error) {
+	row := q.db.QueryRowContext(ctx, sumTransferReversals, reversesTransferID)
+	var i SumTransferReversalsRow
+	err := row.Scan(&i.Amount, &i.ToAmount)
+	return i, err
+}
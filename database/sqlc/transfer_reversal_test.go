@@ -0,0 +1,136 @@
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/October-9th/simple-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestTransfer(t *testing.T, store Store, amount int64) (TransferTxResult, Account, Account) {
+	account1, account2 := CreateRandomAccountPair(t)
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amountOf(account1, amount),
+	})
+	require.NoError(t, err)
+	return result, account1, account2
+}
+
+func TestReverseTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+	transfer, account1, account2 := createTestTransfer(t, store, 100)
+
+	result, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, transfer.Transfer.ID, result.Original.ID)
+	require.Zero(t, result.Remaining)
+
+	reversal := result.Transfer
+	require.Equal(t, sql.NullInt64{Int64: transfer.Transfer.ID, Valid: true}, reversal.ReversesTransferID)
+	require.Equal(t, account2.ID, reversal.FromAccountID)
+	require.Equal(t, account1.ID, reversal.ToAccountID)
+	require.Equal(t, int64(100), reversal.Amount)
+	require.Equal(t, int64(100), reversal.ToAmount)
+
+	require.Equal(t, reversal.ID, result.FromEntry.TransferID.Int64)
+	require.Equal(t, int64(-100), result.FromEntry.Amount)
+	require.Equal(t, int64(100), result.ToEntry.Amount)
+
+	// Both balances are back where they started
+	require.Equal(t, account1.Balance, result.ToAccount.Balance)
+	require.Equal(t, account2.Balance, result.FromAccount.Balance)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+	})
+	require.ErrorIs(t, err, ErrTransferReversed)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: reversal.ID,
+	})
+	require.ErrorIs(t, err, ErrReverseReversal)
+
+	chain, err := store.VerifyEntryChain(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.True(t, chain.Intact, chain.Detail)
+}
+
+func TestReverseTransferTxPartial(t *testing.T) {
+	store := NewStore(testDB)
+	transfer, _, _ := createTestTransfer(t, store, 100)
+
+	result, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+		Amount:     30,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(30), result.Transfer.Amount)
+	require.Equal(t, int64(70), result.Remaining)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+		Amount:     71,
+	})
+	require.ErrorIs(t, err, ErrReversalExceedsTransfer)
+
+	result, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(70), result.Transfer.Amount)
+	require.Zero(t, result.Remaining)
+}
+
+func TestReverseTransferTxConcurrent(t *testing.T) {
+	store := NewStore(testDB)
+	transfer, _, account2 := createTestTransfer(t, store, 100)
+
+	// Only three reversals of 30 fit in 100, the others must be refused
+	n := 5
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+				TransferID: transfer.Transfer.ID,
+				Amount:     30,
+			})
+			errs <- err
+		}()
+	}
+
+	succeeded := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			succeeded++
+			continue
+		}
+		require.ErrorIs(t, err, ErrReversalExceedsTransfer)
+	}
+	require.Equal(t, 3, succeeded)
+
+	account, err := store.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Equal(t, transfer.ToAccount.Balance-90, account.Balance)
+}
+
+func TestReversalRefund(t *testing.T) {
+	// 1000 sent as 1087 at 1.087
+	original := Transfer{Amount: 1000, ToAmount: 1087, ExchangeRate: "1.087"}
+
+	refund := reversalRefund(original, SumTransferReversalsRow{}, 500, 1087)
+	require.Equal(t, int64(459), refund)
+
+	// The last reversal refunds whatever is still owed
+	refund = reversalRefund(original, SumTransferReversalsRow{Amount: 500, ToAmount: 459}, 587, 587)
+	require.Equal(t, int64(541), refund)
+
+	refund = reversalRefund(original, SumTransferReversalsRow{}, util.RandomInt(1, 1086), 1087)
+	require.Less(t, refund, original.Amount)
+}
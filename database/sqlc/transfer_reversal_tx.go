@@ -0,0 +1,170 @@
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/October-9th/simple-bank/fx"
+)
+
+// ErrReversalExceedsTransfer is returned when a reversal would give back more than what is left of the transfer
+var ErrReversalExceedsTransfer = errors.New("reversal exceeds what is left of the transfer")
+
+// ErrTransferReversed is returned when the whole transfer has already been given back
+var ErrTransferReversed = errors.New("transfer is already fully reversed")
+
+// ErrReverseReversal is returned when asked to reverse a reversal, the original transfer can simply be sent again
+var ErrReverseReversal = errors.New("a reversal can't be reversed")
+
+// ReverseTransferTxParams contains the input parameters of the reverse transfer transaction
+type ReverseTransferTxParams struct {
+	TransferID int64 `json:"transfer_id"`
+	// Amount is taken back from the recipient, in the currency of the transfer's destination.
+	// Zero reverses whatever is left of the transfer
+	Amount int64 `json:"amount"`
+}
+
+// ReverseTransferTxResult is the result of the reverse transfer transaction
+type ReverseTransferTxResult struct {
+	// TransferTxResult holds the reversal, a transfer from the original recipient back to the original sender
+	TransferTxResult
+	Original Transfer `json:"original"` // the transfer being reversed
+	// Remaining is what can still be reversed, in the currency of the original transfer's destination
+	Remaining int64 `json:"remaining"`
+}
+
+// ReverseTransferTx gives back all or part of a transfer. The reversal is a transfer in the opposite direction,
+// linked to the original through reverses_transfer_id, with its own pair of entries.
+// The sender gets back the share of the original amount that was reversed, at the original rate, so reversing a
+// transfer entirely returns exactly what was sent whatever the rates are now.
+// The original transfer is locked first, so concurrent reversals of it can't give back more than it moved
+func (s *SQLStore) ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error) {
+	var txResult ReverseTransferTxResult
+
+	if arg.Amount < 0 {
+		return txResult, fmt.Errorf("%w: reversal amount must not be negative", ErrInvalidAmount)
+	}
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		// Entries of the day can't be snapshotted while this transaction may still write some
+		if err := q.LockLedgerDay(ctx); err != nil {
+			return err
+		}
+
+		var err error
+
+		txResult.Original, err = q.GetTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+		original := txResult.Original
+		if original.ReversesTransferID.Valid {
+			return ErrReverseReversal
+		}
+		// Transfers to the same account are refused now, those made before can't be reversed either
+		if original.FromAccountID == original.ToAccountID {
+			return ErrSameAccount
+		}
+
+		reversed, err := q.SumTransferReversals(ctx, sql.NullInt64{Int64: original.ID, Valid: true})
+		if err != nil {
+			return err
+		}
+		remaining := original.ToAmount - reversed.Amount
+		if remaining <= 0 {
+			return ErrTransferReversed
+		}
+
+		amount := arg.Amount
+		if amount == 0 {
+			amount = remaining
+		}
+		if amount > remaining {
+			return fmt.Errorf("%w: %d left to reverse", ErrReversalExceedsTransfer, remaining)
+		}
+		refund := reversalRefund(original, reversed, amount, remaining)
+		if refund <= 0 {
+			return fmt.Errorf("%w: reversal amount is too small to refund", ErrInvalidAmount)
+		}
+
+		exchangeRate, err := fx.Invert(original.ExchangeRate)
+		if err != nil {
+			return err
+		}
+
+		txResult.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+			FromAccountID:      original.ToAccountID,
+			ToAccountID:        original.FromAccountID,
+			Amount:             amount,
+			ToAmount:           refund,
+			ExchangeRate:       exchangeRate,
+			ReversesTransferID: sql.NullInt64{Int64: original.ID, Valid: true},
+			Memo:               fmt.Sprintf("reversal of transfer %d", original.ID),
+			ExternalReference:  original.ExternalReference,
+			Metadata:           original.Metadata,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Same lock order as TransferTx, the account with the lower id first
+		reversal := txResult.Transfer
+		if reversal.FromAccountID < reversal.ToAccountID {
+			txResult.FromAccount, txResult.ToAccount, err = UpdateAccountBalance(reversal.FromAccountID, reversal.ToAccountID, -amount, refund, ctx, q)
+		} else {
+			txResult.ToAccount, txResult.FromAccount, err = UpdateAccountBalance(reversal.ToAccountID, reversal.FromAccountID, refund, -amount, ctx, q)
+		}
+		if err != nil {
+			return asOverflow(err)
+		}
+
+		txResult.FromEntry, txResult.FromAccount, err = s.appendEntry(ctx, q, txResult.FromAccount, CreateEntryParams{
+			AccountID:  reversal.FromAccountID,
+			Amount:     -amount,
+			TransferID: sql.NullInt64{Int64: reversal.ID, Valid: true},
+		}, &txResult.Transfer)
+		if err != nil {
+			return err
+		}
+
+		txResult.ToEntry, txResult.ToAccount, err = s.appendEntry(ctx, q, txResult.ToAccount, CreateEntryParams{
+			AccountID:  reversal.ToAccountID,
+			Amount:     refund,
+			TransferID: sql.NullInt64{Int64: reversal.ID, Valid: true},
+		}, &txResult.Transfer)
+		if err != nil {
+			return err
+		}
+
+		// The same rules as any transfer, the recipient may have spent the money already
+		if err := checkAccountActive(txResult.FromAccount); err != nil {
+			return err
+		}
+		if err := checkAccountActive(txResult.ToAccount); err != nil {
+			return err
+		}
+		if txResult.FromAccount.Balance < -txResult.FromAccount.OverdraftLimit {
+			return ErrInsufficientFunds
+		}
+
+		txResult.Remaining = remaining - amount
+		return nil
+	})
+	return txResult, err
+}
+
+// reversalRefund returns how much of the original amount goes back to the sender when amount of the remaining
+// to_amount is reversed. Partial reversals refund their share rounded down, the last one refunds everything
+// still owed so the rounding never adds up to more or less than the original amount
+func reversalRefund(original Transfer, reversed SumTransferReversalsRow, amount, remaining int64) int64 {
+	if amount == remaining {
+		return original.Amount - reversed.ToAmount
+	}
+
+	refund := new(big.Int).Mul(big.NewInt(original.Amount), big.NewInt(amount))
+	refund.Quo(refund, big.NewInt(original.ToAmount))
+	return refund.Int64()
+}
@@ -2,6 +2,8 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -16,6 +18,9 @@ func CreateRandomTransfer(t *testing.T, sender, receiver Account) Transfer {
 		FromAccountID: sender.ID,
 		ToAccountID:   receiver.ID,
 		Amount:        amount,
+		ToAmount:      amount,
+		ExchangeRate:  "1",
+		Metadata:      json.RawMessage("{}"),
 	})
 
 	require.NoError(t, err)
@@ -69,3 +74,126 @@ func TestListTransfer(t *testing.T) {
 		require.Equal(t, transfer.ToAccountID, receiver.ID)
 	}
 }
+
+func TestListTransfersFilters(t *testing.T) {
+	store := NewStore(testDB)
+	sender, receiver := CreateRandomAccountPair(t)
+	reference := "INV-" + util.RandomString(8)
+
+	details := []TransferTxParams{
+		{Memo: "Rent 100% paid", ExternalReference: reference, Metadata: map[string]string{"category": "rent"}},
+		{Memo: "groceries", Metadata: map[string]string{"category": "food", "store": "corner"}},
+		{},
+	}
+	var transfers []Transfer
+	for _, arg := range details {
+		arg.FromAccountID = sender.ID
+		arg.ToAccountID = receiver.ID
+		arg.Amount = amountOf(sender, 10)
+		result, err := store.TransferTx(context.Background(), arg)
+		require.NoError(t, err)
+		require.Equal(t, arg.Memo, result.Transfer.Memo)
+		require.Equal(t, arg.ExternalReference, result.Transfer.ExternalReference)
+		transfers = append(transfers, result.Transfer)
+	}
+
+	list := func(arg ListTransfersParams) []Transfer {
+		arg.FromAccountID = sender.ID
+		arg.ToAccountID = sender.ID
+		arg.Limit = 10
+		transfers, err := testQueries.ListTransfers(context.Background(), arg)
+		require.NoError(t, err)
+		return transfers
+	}
+
+	require.Len(t, list(ListTransfersParams{}), 3)
+
+	byMemo := list(ListTransfersParams{Memo: sql.NullString{String: ContainsPattern("100%"), Valid: true}})
+	require.Len(t, byMemo, 1)
+	require.Equal(t, transfers[0].ID, byMemo[0].ID)
+
+	// Wildcards in the search text match literally
+	require.Empty(t, list(ListTransfersParams{Memo: sql.NullString{String: ContainsPattern("_"), Valid: true}}))
+
+	byReference := list(ListTransfersParams{ExternalReference: sql.NullString{String: reference, Valid: true}})
+	require.Len(t, byReference, 1)
+	require.Equal(t, transfers[0].ID, byReference[0].ID)
+
+	byMetadata := list(ListTransfersParams{Metadata: sql.NullString{String: `{"category": "food"}`, Valid: true}})
+	require.Len(t, byMetadata, 1)
+	require.Equal(t, transfers[1].ID, byMetadata[0].ID)
+	require.JSONEq(t, `{"category": "food", "store": "corner"}`, string(byMetadata[0].Metadata))
+
+	require.JSONEq(t, `{}`, string(transfers[2].Metadata))
+}
+
+func TestSearchTransfers(t *testing.T) {
+	store := NewStore(testDB)
+	account, counterparty := CreateRandomAccountPair(t)
+
+	sent, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account.ID,
+		ToAccountID:   counterparty.ID,
+		Amount:        amountOf(account, 10),
+		Memo:          "dinner",
+	})
+	require.NoError(t, err)
+	received, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: counterparty.ID,
+		ToAccountID:   account.ID,
+		Amount:        amountOf(counterparty, 25),
+	})
+	require.NoError(t, err)
+
+	search := func(arg SearchTransfersParams) []Transfer {
+		arg.Owner = account.Owner
+		if arg.LimitCount == 0 {
+			arg.LimitCount = 10
+		}
+		transfers, err := testQueries.SearchTransfers(context.Background(), arg)
+		require.NoError(t, err)
+		return transfers
+	}
+
+	// Newest first
+	all := search(SearchTransfersParams{})
+	require.Len(t, all, 2)
+	require.Equal(t, received.Transfer.ID, all[0].ID)
+	require.Equal(t, sent.Transfer.ID, all[1].ID)
+
+	bySent := search(SearchTransfersParams{Direction: sql.NullString{String: "sent", Valid: true}})
+	require.Len(t, bySent, 1)
+	require.Equal(t, sent.Transfer.ID, bySent[0].ID)
+
+	byReceived := search(SearchTransfersParams{
+		Direction:             sql.NullString{String: "received", Valid: true},
+		CounterpartyAccountID: sql.NullInt64{Int64: counterparty.ID, Valid: true},
+	})
+	require.Len(t, byReceived, 1)
+	require.Equal(t, received.Transfer.ID, byReceived[0].ID)
+
+	byAmount := search(SearchTransfersParams{MinAmount: sql.NullInt64{Int64: 20, Valid: true}})
+	require.Len(t, byAmount, 1)
+	require.Equal(t, received.Transfer.ID, byAmount[0].ID)
+
+	byMemo := search(SearchTransfersParams{Memo: sql.NullString{String: ContainsPattern("DIN"), Valid: true}})
+	require.Len(t, byMemo, 1)
+	require.Equal(t, sent.Transfer.ID, byMemo[0].ID)
+
+	require.Empty(t, search(SearchTransfersParams{FromTime: sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true}}))
+
+	// The next page starts after the last row of the previous one
+	page := search(SearchTransfersParams{LimitCount: 1})
+	require.Len(t, page, 1)
+	page = search(SearchTransfersParams{
+		LimitCount:      1,
+		CursorCreatedAt: sql.NullTime{Time: page[0].CreatedAt, Valid: true},
+		CursorID:        sql.NullInt64{Int64: page[0].ID, Valid: true},
+	})
+	require.Len(t, page, 1)
+	require.Equal(t, sent.Transfer.ID, page[0].ID)
+
+	// Accounts of other users are never searched
+	other := search(SearchTransfersParams{AccountID: sql.NullInt64{Int64: counterparty.ID, Valid: true}})
+	require.Empty(t, other)
+}
@@ -18,7 +18,7 @@ INSERT INTO users(
 )VALUES(
     $1, $2, $3, $4
 ) 
-RETURNING username, hashed_password, full_name, email, created_at, password_changed_at
+RETURNING username, hashed_password, full_name, email, created_at, password_changed_at, role
 `
 
 type CreateUserParams struct {
@@ -43,12 +43,13 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.CreatedAt,
 		&i.PasswordChangedAt,
+		&i.Role,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT username, hashed_password, full_name, email, created_at, password_changed_at FROM users
+SELECT username, hashed_password, full_name, email, created_at, password_changed_at, role FROM users
 WHERE username = $1 LIMIT 1
 `
 
@@ -62,6 +63,34 @@ func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
 		&i.Email,
 		&i.CreatedAt,
 		&i.PasswordChangedAt,
+		&i.Role,
+	)
+	return i, err
+}
+
+const updateUserRole = `-- name: UpdateUserRole :one
+UPDATE users
+SET role = $1
+WHERE username = $2
+RETURNING username, hashed_password, full_name, email, created_at, password_changed_at, role
+`
+
+type UpdateUserRoleParams struct {
+	Role     string
+	Username string
+}
+
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserRole, arg.Role, arg.Username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.CreatedAt,
+		&i.PasswordChangedAt,
+		&i.Role,
 	)
 	return i, err
 }
@@ -50,3 +50,22 @@ func TestGetUser(t *testing.T) {
 	require.WithinDuration(t, user.CreatedAt, user_test.CreatedAt, time.Second)
 	require.WithinDuration(t, user.PasswordChangedAt, user_test.PasswordChangedAt, time.Second)
 }
+
+func TestUpdateUserRole(t *testing.T) {
+	user := CreateRandomUser(t)
+	require.Equal(t, util.DepositorRole, user.Role)
+
+	updated, err := testQueries.UpdateUserRole(context.Background(), UpdateUserRoleParams{
+		Username: user.Username,
+		Role:     util.BankerRole,
+	})
+	require.NoError(t, err)
+	require.Equal(t, util.BankerRole, updated.Role)
+
+	// The database refuses a role the application doesn't know
+	_, err = testQueries.UpdateUserRole(context.Background(), UpdateUserRoleParams{
+		Username: user.Username,
+		Role:     "superuser",
+	})
+	require.Error(t, err)
+}
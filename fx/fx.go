@@ -0,0 +1,173 @@
+// Package fx converts amounts between currencies and reads exchange rates from CSV files
+package fx
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/October-9th/simple-bank/util"
+)
+
+// ErrInvalidRate is returned when a rate is not a positive decimal number
+var ErrInvalidRate = errors.New("rate must be a positive decimal number with at most 10 fractional digits")
+
+// Rates are stored as numeric(20,10), so at most 10 integer and 10 fractional digits
+var isValidRate = regexp.MustCompile(`^[0-9]{1,10}(\.[0-9]{1,10})?$`).MatchString
+
+// Rate is the price of one unit of Base in units of Quote from EffectiveAt on
+type Rate struct {
+	Base        string    `json:"base_currency"`
+	Quote       string    `json:"quote_currency"`
+	Rate        string    `json:"rate"`
+	EffectiveAt time.Time `json:"effective_at"`
+}
+
+// Validate checks that both currencies are supported and different, and that the rate is valid
+func (r Rate) Validate() error {
+	if !util.IsSupportedCurrency(r.Base) {
+		return fmt.Errorf("unsupported base currency %q", r.Base)
+	}
+	if !util.IsSupportedCurrency(r.Quote) {
+		return fmt.Errorf("unsupported quote currency %q", r.Quote)
+	}
+	if r.Base == r.Quote {
+		return fmt.Errorf("base and quote currency must be different")
+	}
+	if r.EffectiveAt.IsZero() {
+		return fmt.Errorf("effective time is required")
+	}
+	return ValidateRate(r.Rate)
+}
+
+// ValidateRate checks that rate is a positive decimal number that fits the database column
+func ValidateRate(rate string) error {
+	if !isValidRate(rate) {
+		return ErrInvalidRate
+	}
+	value, ok := new(big.Rat).SetString(rate)
+	if !ok || value.Sign() <= 0 {
+		return ErrInvalidRate
+	}
+	return nil
+}
+
+// Convert turns an amount of minor units of one currency into minor units of another.
+// Rates are quoted for major units, so the result is scaled by the difference of the minor unit exponents,
+// then rounded half away from zero. The math is exact, so the same inputs always give the same result
+func Convert(amount int64, rate string, fromMinorUnit, toMinorUnit int32) (int64, error) {
+	value, ok := new(big.Rat).SetString(rate)
+	if !ok || value.Sign() <= 0 {
+		return 0, ErrInvalidRate
+	}
+
+	value.Mul(value, new(big.Rat).SetInt64(amount))
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(absInt32(toMinorUnit-fromMinorUnit))), nil)
+	if toMinorUnit > fromMinorUnit {
+		value.Mul(value, new(big.Rat).SetInt(scale))
+	} else {
+		value.Quo(value, new(big.Rat).SetInt(scale))
+	}
+
+	// Rounding half away from zero: add half a unit to the absolute value, then truncate
+	num := new(big.Int).Abs(value.Num())
+	den := value.Denom()
+	num.Mul(num, big.NewInt(2))
+	num.Add(num, den)
+	num.Quo(num, new(big.Int).Mul(den, big.NewInt(2)))
+	if value.Sign() < 0 {
+		num.Neg(num)
+	}
+
+	if !num.IsInt64() {
+		return 0, fmt.Errorf("converted amount overflows")
+	}
+	return num.Int64(), nil
+}
+
+// Invert returns the rate of the opposite direction, 1/rate rounded half away from zero to 10 fractional digits.
+// It fails when the inverse doesn't fit the database column, which only happens for extreme rates
+func Invert(rate string) (string, error) {
+	value, ok := new(big.Rat).SetString(rate)
+	if !ok || value.Sign() <= 0 {
+		return "", ErrInvalidRate
+	}
+
+	inverse := new(big.Rat).Inv(value).FloatString(10)
+	inverse = strings.TrimRight(strings.TrimRight(inverse, "0"), ".")
+	if err := ValidateRate(inverse); err != nil {
+		return "", err
+	}
+	return inverse, nil
+}
+
+func absInt32(value int32) int32 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+// csvHeader is the header every rates file starts with
+var csvHeader = []string{"base_currency", "quote_currency", "rate", "effective_at"}
+
+// ReadCSV reads rates from r. The first line must be the header
+// base_currency,quote_currency,rate,effective_at and effective_at is in RFC 3339 format
+func ReadCSV(r io.Reader) ([]Rate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(csvHeader)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read header: %w", err)
+	}
+	if strings.Join(header, ",") != strings.Join(csvHeader, ",") {
+		return nil, fmt.Errorf("header must be %s", strings.Join(csvHeader, ","))
+	}
+
+	rates := []Rate{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		effectiveAt, err := time.Parse(time.RFC3339, record[3])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid effective_at: %w", line, err)
+		}
+		rate := Rate{
+			Base:        strings.ToUpper(record[0]),
+			Quote:       strings.ToUpper(record[1]),
+			Rate:        record[2],
+			EffectiveAt: effectiveAt,
+		}
+		if err := rate.Validate(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+// ReadCSVFile reads rates from the CSV file at path, see ReadCSV for the format
+func ReadCSVFile(path string) ([]Rate, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadCSV(file)
+}
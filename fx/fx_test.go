@@ -0,0 +1,138 @@
+package fx
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/October-9th/simple-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvert(t *testing.T) {
+	testCases := []struct {
+		amount   int64
+		rate     string
+		expected int64
+	}{
+		{100, "1", 100},
+		{100, "0.92", 92},
+		{1000, "1.3245", 1325},
+		{1000, "1.3244", 1324},
+		{1, "0.5", 1},
+		{1, "0.4999999999", 0},
+		{-1000, "1.3245", -1325},
+	}
+
+	for _, tc := range testCases {
+		converted, err := Convert(tc.amount, tc.rate, 2, 2)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, converted, "%d x %s", tc.amount, tc.rate)
+	}
+
+	_, err := Convert(100, "0", 2, 2)
+	require.ErrorIs(t, err, ErrInvalidRate)
+
+	_, err = Convert(100, "abc", 2, 2)
+	require.ErrorIs(t, err, ErrInvalidRate)
+
+	_, err = Convert(1<<62, "9999999999", 2, 2)
+	require.Error(t, err)
+}
+
+func TestConvertMinorUnits(t *testing.T) {
+	testCases := []struct {
+		amount        int64
+		rate          string
+		fromMinorUnit int32
+		toMinorUnit   int32
+		expected      int64
+	}{
+		// 10.00 USD at 145.5 JPY per USD is 1455 JPY
+		{1000, "145.5", 2, 0, 1455},
+		// 1455 JPY at 0.0068728522 USD per JPY is 10.00 USD
+		{1455, "0.0068728522", 0, 2, 1000},
+		// 10.00 USD at 0.307 KWD per USD is 3.070 KWD
+		{1000, "0.307", 2, 3, 3070},
+		// 3.070 KWD at 3.2573289902 USD per KWD is 10.00 USD
+		{3070, "3.2573289902", 3, 2, 1000},
+		// 1 JPY at 0.0021 KWD per JPY is 0.002 KWD
+		{1, "0.0021", 0, 3, 2},
+	}
+
+	for _, tc := range testCases {
+		converted, err := Convert(tc.amount, tc.rate, tc.fromMinorUnit, tc.toMinorUnit)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, converted, "%d x %s", tc.amount, tc.rate)
+	}
+}
+
+func TestInvert(t *testing.T) {
+	testCases := []struct {
+		rate     string
+		expected string
+	}{
+		{"1", "1"},
+		{"1.0000000000", "1"},
+		{"0.5", "2"},
+		{"1.087", "0.9199632015"},
+		{"145.5", "0.0068728522"},
+		{"3", "0.3333333333"},
+	}
+
+	for _, tc := range testCases {
+		inverse, err := Invert(tc.rate)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, inverse, tc.rate)
+	}
+
+	_, err := Invert("0")
+	require.ErrorIs(t, err, ErrInvalidRate)
+
+	// Too large for numeric(20,10) once inverted
+	_, err = Invert("0.0000000001")
+	require.ErrorIs(t, err, ErrInvalidRate)
+}
+
+func TestValidateRate(t *testing.T) {
+	require.NoError(t, ValidateRate("0.92"))
+	require.NoError(t, ValidateRate("1"))
+	require.ErrorIs(t, ValidateRate("0"), ErrInvalidRate)
+	require.ErrorIs(t, ValidateRate("-1"), ErrInvalidRate)
+	require.ErrorIs(t, ValidateRate("1/3"), ErrInvalidRate)
+	require.ErrorIs(t, ValidateRate("1.00000000001"), ErrInvalidRate)
+}
+
+func TestReadCSVFile(t *testing.T) {
+	rates, err := ReadCSVFile("testdata/rates.csv")
+	require.NoError(t, err)
+	require.Len(t, rates, 6)
+
+	require.Equal(t, Rate{
+		Base:        util.USD,
+		Quote:       util.EUR,
+		Rate:        "0.92",
+		EffectiveAt: time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC),
+	}, rates[0])
+}
+
+func TestReadCSVInvalid(t *testing.T) {
+	testCases := []struct {
+		name string
+		data string
+	}{
+		{"MissingHeader", "USD,EUR,0.92,2023-07-01T00:00:00Z\n"},
+		{"UnsupportedCurrency", "base_currency,quote_currency,rate,effective_at\nUSD,XXX,0.92,2023-07-01T00:00:00Z\n"},
+		{"SameCurrency", "base_currency,quote_currency,rate,effective_at\nUSD,USD,1,2023-07-01T00:00:00Z\n"},
+		{"InvalidRate", "base_currency,quote_currency,rate,effective_at\nUSD,EUR,-0.92,2023-07-01T00:00:00Z\n"},
+		{"InvalidTime", "base_currency,quote_currency,rate,effective_at\nUSD,EUR,0.92,2023-07-01\n"},
+		{"MissingField", "base_currency,quote_currency,rate,effective_at\nUSD,EUR,0.92\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ReadCSV(strings.NewReader(tc.data))
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/October-9th/simple-bank/token"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	authorizationHeader            = "authorization"
+	grpcGatewayAuthorizationHeader = "grpcgateway-authorization"
+	authorizationBearer            = "bearer"
+)
+
+// authorizationPayloadKey is the context key the auth interceptor stores the verified payload under
+type authorizationPayloadKey struct{}
+
+// authorizeUser verifies the access token sent in the authorization metadata, which must grant scope,
+// and returns its payload. The error is a status error, Unauthenticated unless the session couldn't be checked
+func (server *Server) authorizeUser(ctx context.Context, scope string) (*token.Payload, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, unauthenticatedError(fmt.Errorf("missing metadata"))
+	}
+
+	// Requests coming through the http gateway may carry the header with the gateway prefix
+	values := md.Get(authorizationHeader)
+	if len(values) == 0 {
+		values = md.Get(grpcGatewayAuthorizationHeader)
+	}
+	if len(values) == 0 {
+		return nil, unauthenticatedError(fmt.Errorf("missing authorization header"))
+	}
+
+	// The header has the form "Bearer <token>"
+	fields := strings.Fields(values[0])
+	if len(fields) < 2 {
+		return nil, unauthenticatedError(fmt.Errorf("invalid authorization header format"))
+	}
+
+	authType := strings.ToLower(fields[0])
+	if authType != authorizationBearer {
+		return nil, unauthenticatedError(fmt.Errorf("unsupported authorization type: %s", authType))
+	}
+
+	payload, err := server.tokenMaker.VerifyToken(fields[1], token.TokenTypeAccess, scope)
+	if err != nil {
+		return nil, unauthenticatedError(fmt.Errorf("invalid access token: %s", err))
+	}
+
+	// Revoking a session blocks its refresh tokens, its access tokens stop working right away too
+	if payload.SessionID != uuid.Nil {
+		blocked, err := server.store.IsSessionFamilyBlocked(ctx, payload.SessionID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "couldn't check session: %s", err)
+		}
+		if blocked {
+			return nil, unauthenticatedError(fmt.Errorf("session has been revoked"))
+		}
+	}
+	return payload, nil
+}
+
+// payloadFromContext returns the payload stored in the context by the auth interceptor
+func payloadFromContext(ctx context.Context) (*token.Payload, error) {
+	payload, ok := ctx.Value(authorizationPayloadKey{}).(*token.Payload)
+	if !ok {
+		return nil, fmt.Errorf("missing authorization payload")
+	}
+	return payload, nil
+}
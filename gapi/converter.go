@@ -1,8 +1,13 @@
 package gapi
 
 import (
+	"encoding/json"
+
 	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/fx"
+	"github.com/October-9th/simple-bank/money"
 	"github.com/October-9th/simple-bank/pb"
+	"github.com/google/uuid"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -11,7 +16,161 @@ func convertUser(user sqlc.User) *pb.User {
 		Username:          user.Username,
 		FullName:          user.FullName,
 		Email:             user.Email,
+		Role:              user.Role,
 		PasswordChangedAt: timestamppb.New(user.PasswordChangedAt),
 		CreatedAt:         timestamppb.New(user.CreatedAt),
 	}
 }
+
+func convertMoney(amount money.Amount) *pb.Money {
+	return &pb.Money{
+		Currency:   amount.Currency,
+		MinorUnits: amount.Minor,
+	}
+}
+
+func convertAccount(account sqlc.Account) *pb.Account {
+	rsp := &pb.Account{
+		Id:             account.ID,
+		Owner:          account.Owner,
+		Balance:        account.Balance,
+		Currency:       account.Currency,
+		OverdraftLimit: account.OverdraftLimit,
+		Status:         account.Status,
+		BalanceMoney:   convertMoney(money.Amount{Minor: account.Balance, Currency: account.Currency}),
+		CreatedAt:      timestamppb.New(account.CreatedAt),
+	}
+	if account.ClosedAt.Valid {
+		rsp.ClosedAt = timestamppb.New(account.ClosedAt.Time)
+	}
+	return rsp
+}
+
+func convertTransfer(transfer sqlc.Transfer) *pb.Transfer {
+	return &pb.Transfer{
+		Id:                 transfer.ID,
+		FromAccountId:      transfer.FromAccountID,
+		ToAccountId:        transfer.ToAccountID,
+		Amount:             transfer.Amount,
+		ToAmount:           transfer.ToAmount,
+		ExchangeRate:       transfer.ExchangeRate,
+		CreatedAt:          timestamppb.New(transfer.CreatedAt),
+		ReversesTransferId: transfer.ReversesTransferID.Int64,
+		Memo:               transfer.Memo,
+		ExternalReference:  transfer.ExternalReference,
+		Metadata:           convertMetadata(transfer.Metadata),
+	}
+}
+
+// convertMetadata decodes the metadata column, only TransferTx writes it so it always holds strings
+func convertMetadata(metadata json.RawMessage) map[string]string {
+	var rsp map[string]string
+	_ = json.Unmarshal(metadata, &rsp)
+	return rsp
+}
+
+func convertEntry(entry sqlc.Entry) *pb.Entry {
+	return &pb.Entry{
+		Id:        entry.ID,
+		AccountId: entry.AccountID,
+		Amount:    entry.Amount,
+		CreatedAt: timestamppb.New(entry.CreatedAt),
+	}
+}
+
+func convertBalanceAdjustment(adjustment sqlc.BalanceAdjustment) *pb.BalanceAdjustment {
+	return &pb.BalanceAdjustment{
+		Id:         adjustment.ID,
+		AccountId:  adjustment.AccountID,
+		EntryId:    adjustment.EntryID,
+		Amount:     adjustment.Amount,
+		Reason:     adjustment.Reason,
+		AdjustedBy: adjustment.AdjustedBy,
+		CreatedAt:  timestamppb.New(adjustment.CreatedAt),
+	}
+}
+
+func convertOverdraftLimitChange(change sqlc.OverdraftLimitChange) *pb.OverdraftLimitChange {
+	return &pb.OverdraftLimitChange{
+		Id:            change.ID,
+		AccountId:     change.AccountID,
+		PreviousLimit: change.PreviousLimit,
+		NewLimit:      change.NewLimit,
+		Reason:        change.Reason,
+		ChangedBy:     change.ChangedBy,
+		CreatedAt:     timestamppb.New(change.CreatedAt),
+	}
+}
+
+func convertExchangeRate(rate sqlc.ExchangeRate) *pb.ExchangeRate {
+	return &pb.ExchangeRate{
+		Id:            rate.ID,
+		BaseCurrency:  rate.BaseCurrency,
+		QuoteCurrency: rate.QuoteCurrency,
+		Rate:          rate.Rate,
+		EffectiveAt:   timestamppb.New(rate.EffectiveAt),
+		CreatedAt:     timestamppb.New(rate.CreatedAt),
+	}
+}
+
+func convertRateFromProto(rate *pb.ExchangeRate) fx.Rate {
+	return fx.Rate{
+		Base:        rate.GetBaseCurrency(),
+		Quote:       rate.GetQuoteCurrency(),
+		Rate:        rate.GetRate(),
+		EffectiveAt: rate.GetEffectiveAt().AsTime(),
+	}
+}
+
+func convertAccountBalance(balance sqlc.AccountBalance) *pb.AccountBalance {
+	return &pb.AccountBalance{
+		AccountId: balance.AccountID,
+		Balance:   convertMoney(money.Amount{Minor: balance.Balance, Currency: balance.Currency}),
+		AsOf:      timestamppb.New(balance.AsOf),
+	}
+}
+
+func convertReconciliationRun(run sqlc.ReconciliationRun) *pb.ReconciliationRun {
+	return &pb.ReconciliationRun{
+		Id:               run.ID,
+		StartedBy:        run.StartedBy,
+		AccountsChecked:  run.AccountsChecked,
+		TransfersChecked: run.TransfersChecked,
+		EntriesChecked:   run.EntriesChecked,
+		DiscrepancyCount: run.DiscrepancyCount,
+		StartedAt:        timestamppb.New(run.StartedAt),
+		FinishedAt:       timestamppb.New(run.FinishedAt),
+	}
+}
+
+func convertDiscrepancy(discrepancy sqlc.ReconciliationDiscrepancy) *pb.Discrepancy {
+	return &pb.Discrepancy{
+		Kind:       discrepancy.Kind,
+		AccountId:  discrepancy.AccountID.Int64,
+		TransferId: discrepancy.TransferID.Int64,
+		EntryId:    discrepancy.EntryID.Int64,
+		Expected:   discrepancy.Expected,
+		Actual:     discrepancy.Actual,
+		Detail:     discrepancy.Detail,
+	}
+}
+
+func convertDiscrepancies(discrepancies []sqlc.ReconciliationDiscrepancy) []*pb.Discrepancy {
+	converted := make([]*pb.Discrepancy, 0, len(discrepancies))
+	for _, discrepancy := range discrepancies {
+		converted = append(converted, convertDiscrepancy(discrepancy))
+	}
+	return converted
+}
+
+func convertSession(session sqlc.ListActiveSessionsRow, currentID uuid.UUID) *pb.Session {
+	return &pb.Session{
+		Id:           session.FamilyID.String(),
+		UserAgent:    session.UserAgent,
+		ClientIp:     session.ClientIp,
+		SignedInAt:   timestamppb.New(session.SignedInAt),
+		LastActiveAt: timestamppb.New(session.CreatedAt),
+		ExpiresAt:    timestamppb.New(session.ExpiresAt),
+		Current:      session.FamilyID == currentID,
+	}
+}
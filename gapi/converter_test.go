@@ -0,0 +1,18 @@
+package gapi
+
+import (
+	"testing"
+
+	"github.com/October-9th/simple-bank/money"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertMoney(t *testing.T) {
+	amount, err := money.New(-1234, util.JPY)
+	require.NoError(t, err)
+
+	converted := convertMoney(amount)
+	require.Equal(t, util.JPY, converted.GetCurrency())
+	require.Equal(t, int64(-1234), converted.GetMinorUnits())
+}
@@ -23,3 +23,11 @@ func InvalidArgumentError(violations []*errdetails.BadRequest_FieldViolation) er
 	}
 	return statusDeatails.Err()
 }
+
+func unauthenticatedError(err error) error {
+	return status.Errorf(codes.Unauthenticated, "unauthorized: %s", err)
+}
+
+func permissionDeniedError(err error) error {
+	return status.Errorf(codes.PermissionDenied, "%s", err)
+}
@@ -0,0 +1,85 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/token"
+	"google.golang.org/grpc"
+)
+
+// publicMethods are the RPCs that can be called without an access token
+var publicMethods = map[string]bool{
+	pb.GoBank_CreateUser_FullMethodName:       true,
+	pb.GoBank_LoginUser_FullMethodName:        true,
+	pb.GoBank_RenewAccessToken_FullMethodName: true,
+}
+
+// readMethods are the RPCs that only read, the access token needs the read scope for them
+// and the write scope for every other one
+var readMethods = map[string]bool{
+	pb.GoBank_GetAccount_FullMethodName:          true,
+	pb.GoBank_ListAccounts_FullMethodName:        true,
+	pb.GoBank_GetAccountStatement_FullMethodName: true,
+	pb.GoBank_GetBalanceAsOf_FullMethodName:      true,
+	pb.GoBank_ListBalancesAsOf_FullMethodName:    true,
+	pb.GoBank_ListTransfers_FullMethodName:       true,
+	pb.GoBank_SearchTransfers_FullMethodName:     true,
+	pb.GoBank_ListEntries_FullMethodName:         true,
+	pb.GoBank_GetReconciliation_FullMethodName:   true,
+	pb.GoBank_ListReconciliations_FullMethodName: true,
+	pb.GoBank_VerifyEntryChain_FullMethodName:    true,
+	pb.GoBank_ListSessions_FullMethodName:        true,
+}
+
+// requiredScope returns the scope the access token needs to call method
+func requiredScope(method string) string {
+	if readMethods[method] {
+		return token.ScopeRead
+	}
+	return token.ScopeWrite
+}
+
+// UnaryAuthInterceptor verifies the access token of every unary call except the public ones,
+// and stores its payload in the context for the handler
+func (server *Server) UnaryAuthInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if publicMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		payload, err := server.authorizeUser(ctx, requiredScope(info.FullMethod))
+		if err != nil {
+			return nil, err
+		}
+		return handler(context.WithValue(ctx, authorizationPayloadKey{}, payload), req)
+	}
+}
+
+// StreamAuthInterceptor does the same as UnaryAuthInterceptor for streaming calls
+func (server *Server) StreamAuthInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if publicMethods[info.FullMethod] {
+			return handler(srv, stream)
+		}
+
+		payload, err := server.authorizeUser(stream.Context(), requiredScope(info.FullMethod))
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authorizedStream{
+			ServerStream: stream,
+			ctx:          context.WithValue(stream.Context(), authorizationPayloadKey{}, payload),
+		})
+	}
+}
+
+// authorizedStream overrides the context of a stream so the handler can read the payload
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *authorizedStream) Context() context.Context {
+	return stream.ctx
+}
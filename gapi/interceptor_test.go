@@ -0,0 +1,233 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	mockdb "github.com/October-9th/simple-bank/database/mock"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/token"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func newContextWithBearerToken(t *testing.T, tokenMaker token.Maker, header string, username string, duration time.Duration, options ...token.Option) context.Context {
+	// The access token of a logged in user unless options say otherwise
+	options = append([]token.Option{token.WithScopes(token.DefaultScopes...)}, options...)
+	accessToken, _, err := tokenMaker.CreateToken(username, util.DepositorRole, duration, options...)
+	require.NoError(t, err)
+
+	md := metadata.MD{
+		header: []string{fmt.Sprintf("%s %s", authorizationBearer, accessToken)},
+	}
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+func TestUnaryAuthInterceptor(t *testing.T) {
+	username := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		method        string
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, payload *token.Payload, err error)
+	}{
+		{
+			name:   "OK",
+			method: pb.GoBank_GetAccount_FullMethodName,
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, authorizationHeader, username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				require.NoError(t, err)
+				require.Equal(t, username, payload.Username)
+			},
+		},
+		{
+			name:   "GatewayHeader",
+			method: pb.GoBank_GetAccount_FullMethodName,
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, grpcGatewayAuthorizationHeader, username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				require.NoError(t, err)
+				require.Equal(t, username, payload.Username)
+			},
+		},
+		{
+			name:   "PublicMethod",
+			method: pb.GoBank_LoginUser_FullMethodName,
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				require.NoError(t, err)
+				require.Nil(t, payload)
+			},
+		},
+		{
+			name:   "NoAuthorization",
+			method: pb.GoBank_GetAccount_FullMethodName,
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name:   "UnsupportedAuthorization",
+			method: pb.GoBank_GetAccount_FullMethodName,
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				md := metadata.MD{authorizationHeader: []string{"basic abc"}}
+				return metadata.NewIncomingContext(context.Background(), md)
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name:   "ExpiredToken",
+			method: pb.GoBank_GetAccount_FullMethodName,
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, authorizationHeader, username, -time.Minute)
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name:   "RefreshToken",
+			method: pb.GoBank_GetAccount_FullMethodName,
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, authorizationHeader, username, time.Minute,
+					token.WithType(token.TokenTypeRefresh))
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name:   "ReadOnlyRead",
+			method: pb.GoBank_SearchTransfers_FullMethodName,
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, authorizationHeader, username, time.Minute,
+					token.WithScopes(token.ScopeRead))
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				require.NoError(t, err)
+				require.Equal(t, username, payload.Username)
+			},
+		},
+		{
+			name:   "ReadOnlyWrite",
+			method: pb.GoBank_CreateTransfer_FullMethodName,
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, authorizationHeader, username, time.Minute,
+					token.WithScopes(token.ScopeRead))
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t, nil)
+			interceptor := server.UnaryAuthInterceptor()
+
+			// The handler reports the payload it finds in the context
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				payload, _ := payloadFromContext(ctx)
+				return payload, nil
+			}
+
+			ctx := tc.buildContext(t, server.tokenMaker)
+			rsp, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tc.method}, handler)
+
+			payload, _ := rsp.(*token.Payload)
+			tc.checkResponse(t, payload, err)
+		})
+	}
+}
+
+func TestUnaryAuthInterceptorRevokedSession(t *testing.T) {
+	username := util.RandomOwner()
+	familyID := uuid.New()
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, payload *token.Payload, err error)
+	}{
+		{
+			name: "Active",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().IsSessionFamilyBlocked(gomock.Any(), gomock.Eq(familyID)).Times(1).Return(false, nil)
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				require.NoError(t, err)
+				require.Equal(t, familyID, payload.SessionID)
+			},
+		},
+		{
+			name: "Revoked",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().IsSessionFamilyBlocked(gomock.Any(), gomock.Eq(familyID)).Times(1).Return(true, nil)
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+				require.Nil(t, payload)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().IsSessionFamilyBlocked(gomock.Any(), gomock.Any()).Times(1).Return(false, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				// A failed lookup isn't the caller's fault, it's not reported as a bad token
+				require.Equal(t, codes.Internal, status.Code(err))
+				require.Nil(t, payload)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			interceptor := server.UnaryAuthInterceptor()
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				payload, _ := payloadFromContext(ctx)
+				return payload, nil
+			}
+
+			ctx := newContextWithBearerToken(t, server.tokenMaker, authorizationHeader, username, time.Minute,
+				token.WithSessionID(familyID))
+			rsp, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: pb.GoBank_GetAccount_FullMethodName}, handler)
+
+			payload, _ := rsp.(*token.Payload)
+			tc.checkResponse(t, payload, err)
+		})
+	}
+}
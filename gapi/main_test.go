@@ -0,0 +1,42 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/token"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, store sqlc.Store) *Server {
+	config := util.Config{
+		TokenSymmectricKey:   util.RandomString(32),
+		AccessTokenDuration:  time.Minute,
+		RefreshTokenDuration: time.Hour,
+	}
+
+	server, err := NewServer(config, store)
+	require.NoError(t, err)
+
+	return server
+}
+
+// newContextWithPayload returns the context the auth interceptor hands to a handler for a user logged in with role
+func newContextWithPayload(t *testing.T, username string, role string) context.Context {
+	payload, err := token.NewPayLoad(username, role, time.Minute, token.WithScopes(token.DefaultScopes...))
+	require.NoError(t, err)
+	return context.WithValue(context.Background(), authorizationPayloadKey{}, payload)
+}
+
+func randomAccount(owner string) sqlc.Account {
+	return sqlc.Account{
+		ID:       util.RandomInt(1, 1000),
+		Owner:    owner,
+		Balance:  util.RandomMoney(),
+		Currency: util.RandomCurrency(),
+		Status:   util.AccountActive,
+	}
+}
@@ -2,7 +2,12 @@ package gapi
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
 
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 )
@@ -16,26 +21,85 @@ const (
 	userAgentHeader            = "user-agent"
 	grpcGatewayUserAgentHeader = "grpcgateway-user-agent"
 	xForwardedForHeader        = "x-forwarded-for"
+	gatewayMarkerHeader        = "x-gateway-marker"
 )
 
+// gatewayMarker is sent by the http gateway with every call it forwards. It is random for each process,
+// so only a gateway running in the same process as the server can send it
+var gatewayMarker = newGatewayMarker()
+
+func newGatewayMarker() string {
+	marker := make([]byte, 32)
+	if _, err := rand.Read(marker); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(marker)
+}
+
 func (server *Server) extractMetadata(ctx context.Context) *Metadata {
 	mtdt := &Metadata{}
-	if md, ok := metadata.FromIncomingContext(ctx); ok {
+	md, _ := metadata.FromIncomingContext(ctx)
 
-		if userAgent := md.Get(grpcGatewayUserAgentHeader); len(userAgent) != 0 {
-			mtdt.UserAgent = userAgent[0]
-		}
+	if userAgent := md.Get(userAgentHeader); len(userAgent) != 0 {
+		mtdt.UserAgent = userAgent[0]
+	}
 
-		if userAgent := md.Get(userAgentHeader); len(userAgent) != 0 {
-			mtdt.UserAgent = userAgent[0]
+	// The gateway's own user agent would hide the client's one
+	if userAgent := md.Get(grpcGatewayUserAgentHeader); len(userAgent) != 0 {
+		mtdt.UserAgent = userAgent[0]
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return mtdt
+	}
+	mtdt.ClientIP = p.Addr.String()
+
+	// Calls forwarded by the http gateway come from the gateway itself, the client is in x-forwarded-for.
+	// Anyone else could write any address in it, so it is only read for calls of the gateway
+	if fromGateway(md) {
+		if clientIP := forwardedClientIP(md.Get(xForwardedForHeader)); clientIP != "" {
+			mtdt.ClientIP = clientIP
 		}
+	}
+	return mtdt
+}
 
-		if clientIP := md.Get(xForwardedForHeader); len(clientIP) != 0 {
-			mtdt.ClientIP = clientIP[0]
+// fromGateway tells whether the call was forwarded by the http gateway of this process.
+// Being on the same host isn't enough, any local process could dial the server
+func fromGateway(md metadata.MD) bool {
+	for _, marker := range md.Get(gatewayMarkerHeader) {
+		if subtle.ConstantTimeCompare([]byte(marker), []byte(gatewayMarker)) == 1 {
+			return true
 		}
 	}
-	if p, ok := peer.FromContext(ctx); ok {
-		mtdt.ClientIP = p.Addr.String()
+	return false
+}
+
+// GatewayDialOption marks the calls of the http gateway, so the server reads the client address
+// the gateway puts in x-forwarded-for. The gateway has to run in the same process as the server
+func GatewayDialOption() grpc.DialOption {
+	return grpc.WithPerRPCCredentials(gatewayCredentials{})
+}
+
+// gatewayCredentials adds the gateway marker to the metadata of every call
+type gatewayCredentials struct{}
+
+func (gatewayCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{gatewayMarkerHeader: gatewayMarker}, nil
+}
+
+// The gateway dials the server without TLS
+func (gatewayCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+// forwardedClientIP returns the last hop of x-forwarded-for. The gateway appends the address the request
+// came from to whatever the client sent, so the hops before it are the client's own words
+func forwardedClientIP(values []string) string {
+	if len(values) == 0 {
+		return ""
 	}
-	return mtdt
+	hops := strings.Split(values[len(values)-1], ",")
+	return strings.TrimSpace(hops[len(hops)-1])
 }
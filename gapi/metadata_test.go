@@ -0,0 +1,95 @@
+package gapi
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestExtractMetadata(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	testCases := []struct {
+		name     string
+		peer     string
+		md       metadata.MD
+		clientIP string
+	}{
+		{
+			name:     "Direct",
+			peer:     "203.0.113.7:51000",
+			md:       metadata.Pairs(userAgentHeader, "grpc-go"),
+			clientIP: "203.0.113.7:51000",
+		},
+		{
+			// A direct caller can't choose the address stored with its session
+			name:     "DirectSpoofedForwardedFor",
+			peer:     "203.0.113.7:51000",
+			md:       metadata.Pairs(xForwardedForHeader, "198.51.100.1"),
+			clientIP: "203.0.113.7:51000",
+		},
+		{
+			// Nor can another process of the gateway's host
+			name:     "LoopbackWithoutMarker",
+			peer:     "127.0.0.1:51000",
+			md:       metadata.Pairs(xForwardedForHeader, "198.51.100.1"),
+			clientIP: "127.0.0.1:51000",
+		},
+		{
+			name:     "WrongMarker",
+			peer:     "127.0.0.1:51000",
+			md:       metadata.Pairs(gatewayMarkerHeader, newGatewayMarker(), xForwardedForHeader, "198.51.100.1"),
+			clientIP: "127.0.0.1:51000",
+		},
+		{
+			name:     "Gateway",
+			peer:     "127.0.0.1:51000",
+			md:       metadata.Pairs(gatewayMarkerHeader, gatewayMarker, xForwardedForHeader, "203.0.113.7"),
+			clientIP: "203.0.113.7",
+		},
+		{
+			// The gateway appends the real address to the header the client sent
+			name:     "GatewaySpoofedForwardedFor",
+			peer:     "[::1]:51000",
+			md:       metadata.Pairs(gatewayMarkerHeader, gatewayMarker, xForwardedForHeader, "198.51.100.1, 203.0.113.7"),
+			clientIP: "203.0.113.7",
+		},
+		{
+			// A client forwarding a marker of its own through the gateway doesn't hide the real one
+			name:     "GatewayWithClientMarker",
+			peer:     "10.0.0.5:51000",
+			md:       metadata.Pairs(gatewayMarkerHeader, "guess", gatewayMarkerHeader, gatewayMarker, xForwardedForHeader, "203.0.113.7"),
+			clientIP: "203.0.113.7",
+		},
+		{
+			name:     "GatewayWithoutForwardedFor",
+			peer:     "127.0.0.1:51000",
+			md:       metadata.Pairs(gatewayMarkerHeader, gatewayMarker),
+			clientIP: "127.0.0.1:51000",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			addr, err := net.ResolveTCPAddr("tcp", tc.peer)
+			require.NoError(t, err)
+
+			ctx := metadata.NewIncomingContext(context.Background(), tc.md)
+			ctx = peer.NewContext(ctx, &peer.Peer{Addr: addr})
+			require.Equal(t, tc.clientIP, server.extractMetadata(ctx).ClientIP)
+		})
+	}
+}
+
+func TestGatewayDialOption(t *testing.T) {
+	// The marker the gateway sends is the one the server checks
+	md, err := gatewayCredentials{}.GetRequestMetadata(context.Background())
+	require.NoError(t, err)
+	require.True(t, fromGateway(metadata.New(md)))
+	require.NotNil(t, GatewayDialOption())
+}
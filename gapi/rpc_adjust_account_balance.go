@@ -0,0 +1,74 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/October-9th/simple-bank/authz"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/money"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) AdjustAccountBalance(ctx context.Context, req *pb.AdjustAccountBalanceRequest) (*pb.AdjustAccountBalanceResponse, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateAdjustAccountBalanceRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	account, err := server.getAuthorizedAccount(ctx, req.GetAccountId(), authPayload, authz.AdjustBalance)
+	if err != nil {
+		return nil, err
+	}
+
+	amount, err := money.New(req.GetAmount(), account.Currency)
+	if err != nil {
+		if errors.Is(err, money.ErrOverflow) {
+			return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "%s", err)
+	}
+
+	result, err := server.store.AdjustBalanceTx(ctx, sqlc.AdjustBalanceTxParams{
+		AccountID:  req.GetAccountId(),
+		Amount:     amount,
+		Reason:     req.GetReason(),
+		AdjustedBy: authPayload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, sqlc.ErrAccountClosed) || errors.Is(err, sqlc.ErrInsufficientFunds) || errors.Is(err, money.ErrOverflow) ||
+			errors.Is(err, money.ErrCurrencyMismatch) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to adjust balance: %s", err)
+	}
+
+	rsp := &pb.AdjustAccountBalanceResponse{
+		Adjustment: convertBalanceAdjustment(result.Adjustment),
+		Entry:      convertEntry(result.Entry),
+		Account:    convertAccount(result.Account),
+	}
+	return rsp, nil
+}
+
+func validateAdjustAccountBalanceRequest(req *pb.AdjustAccountBalanceRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validate.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+	if err := validate.ValidateAdjustmentAmount(req.GetAmount()); err != nil {
+		violations = append(violations, fieldViolation("amount", err))
+	}
+	if err := validate.ValidateString(req.GetReason(), 1, 200); err != nil {
+		violations = append(violations, fieldViolation("reason", err))
+	}
+	return violations
+}
@@ -0,0 +1,220 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"testing"
+
+	mockdb "github.com/October-9th/simple-bank/database/mock"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/money"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdjustAccountBalanceAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	account := randomAccount(owner)
+	amount := int64(-25)
+	reason := "chargeback"
+
+	adjusted := account
+	adjusted.Balance += amount
+	result := sqlc.AdjustBalanceTxResult{
+		Account: adjusted,
+		Entry:   sqlc.Entry{ID: 3, AccountID: account.ID, Amount: amount},
+		Adjustment: sqlc.BalanceAdjustment{
+			ID:         1,
+			AccountID:  account.ID,
+			EntryID:    3,
+			Amount:     amount,
+			Reason:     reason,
+			AdjustedBy: "admin",
+		},
+	}
+	newRequest := func() *pb.AdjustAccountBalanceRequest {
+		return &pb.AdjustAccountBalanceRequest{AccountId: account.ID, Amount: amount, Reason: reason}
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.AdjustAccountBalanceRequest
+		buildContext  func(t *testing.T) context.Context
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, rsp *pb.AdjustAccountBalanceResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  newRequest(),
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "admin", util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := sqlc.AdjustBalanceTxParams{
+					AccountID:  account.ID,
+					Amount:     money.Amount{Minor: amount, Currency: account.Currency},
+					Reason:     reason,
+					AdjustedBy: "admin",
+				}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().AdjustBalanceTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.AdjustAccountBalanceResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, adjusted.Balance, rsp.GetAccount().GetBalance())
+				require.Equal(t, amount, rsp.GetEntry().GetAmount())
+				require.Equal(t, "admin", rsp.GetAdjustment().GetAdjustedBy())
+			},
+		},
+		{
+			name: "NotAdmin",
+			req:  newRequest(),
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().AdjustBalanceTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.AdjustAccountBalanceResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "NotFound",
+			req:  newRequest(),
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "admin", util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(sqlc.Account{}, sql.ErrNoRows)
+				store.EXPECT().AdjustBalanceTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.AdjustAccountBalanceResponse, err error) {
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name: "InsufficientFunds",
+			req:  newRequest(),
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "admin", util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().AdjustBalanceTx(gomock.Any(), gomock.Any()).Times(1).
+					Return(sqlc.AdjustBalanceTxResult{}, sqlc.ErrInsufficientFunds)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.AdjustAccountBalanceResponse, err error) {
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		},
+		{
+			name: "AccountClosed",
+			req:  newRequest(),
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "admin", util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().AdjustBalanceTx(gomock.Any(), gomock.Any()).Times(1).
+					Return(sqlc.AdjustBalanceTxResult{}, sqlc.ErrAccountClosed)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.AdjustAccountBalanceResponse, err error) {
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		},
+		{
+			name: "InternalError",
+			req:  newRequest(),
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "admin", util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().AdjustBalanceTx(gomock.Any(), gomock.Any()).Times(1).
+					Return(sqlc.AdjustBalanceTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.AdjustAccountBalanceResponse, err error) {
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+		{
+			name: "ZeroAmount",
+			req:  &pb.AdjustAccountBalanceRequest{AccountId: account.ID, Amount: 0, Reason: reason},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "admin", util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().AdjustBalanceTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.AdjustAccountBalanceResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			// An amount that can't be negated never reaches the store
+			name: "AmountOverflows",
+			req:  &pb.AdjustAccountBalanceRequest{AccountId: account.ID, Amount: math.MinInt64, Reason: reason},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "admin", util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().AdjustBalanceTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.AdjustAccountBalanceResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "MissingReason",
+			req:  &pb.AdjustAccountBalanceRequest{AccountId: account.ID, Amount: amount},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "admin", util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().AdjustBalanceTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.AdjustAccountBalanceResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "NoAuthorization",
+			req:  newRequest(),
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().AdjustBalanceTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.AdjustAccountBalanceResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			rsp, err := server.AdjustAccountBalance(tc.buildContext(t), tc.req)
+			tc.checkResponse(t, rsp, err)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/October-9th/simple-bank/authz"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CloseAccount(ctx context.Context, req *pb.CloseAccountRequest) (*pb.CloseAccountResponse, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateCloseAccountRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	if _, err := server.getAuthorizedAccount(ctx, req.GetId(), authPayload, authz.ManageAccount); err != nil {
+		return nil, err
+	}
+
+	account, err := server.store.CloseAccountTx(ctx, req.GetId())
+	if err != nil {
+		if errors.Is(err, sqlc.ErrAccountClosed) || errors.Is(err, sqlc.ErrNonZeroBalance) || errors.Is(err, sqlc.ErrInvalidStatusTransition) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to close account: %s", err)
+	}
+
+	rsp := &pb.CloseAccountResponse{
+		Account: convertAccount(account),
+	}
+	return rsp, nil
+}
+
+func validateCloseAccountRequest(req *pb.CloseAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validate.ValidateID(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	return violations
+}
@@ -0,0 +1,56 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/validate"
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateAccount(ctx context.Context, req *pb.CreateAccountRequest) (*pb.CreateAccountResponse, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateCreateAccountRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	arg := sqlc.CreateAccountParams{
+		Owner:    authPayload.Username,
+		Currency: req.GetCurrency(),
+		Balance:  0,
+	}
+
+	account, err := server.store.CreateAccount(ctx, arg)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				return nil, status.Errorf(codes.AlreadyExists, "account with this currency already exists: %s", err)
+			case "foreign_key_violation":
+				return nil, status.Errorf(codes.FailedPrecondition, "owner doesn't exist: %s", err)
+			}
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create account: %s", err)
+	}
+
+	rsp := &pb.CreateAccountResponse{
+		Account: convertAccount(account),
+	}
+	return rsp, nil
+}
+
+func validateCreateAccountRequest(req *pb.CreateAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validate.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+	return violations
+}
@@ -0,0 +1,132 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	mockdb "github.com/October-9th/simple-bank/database/mock"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCreateAccountAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	account := randomAccount(owner)
+	account.Balance = 0
+
+	testCases := []struct {
+		name          string
+		req           *pb.CreateAccountRequest
+		buildContext  func(t *testing.T) context.Context
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, rsp *pb.CreateAccountResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.CreateAccountRequest{Currency: account.Currency},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				// The account is always opened for the authenticated user
+				arg := sqlc.CreateAccountParams{Owner: owner, Currency: account.Currency, Balance: 0}
+				store.EXPECT().CreateAccount(gomock.Any(), gomock.Eq(arg)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateAccountResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, account.ID, rsp.GetAccount().GetId())
+				require.Equal(t, owner, rsp.GetAccount().GetOwner())
+			},
+		},
+		{
+			name: "DuplicateCurrency",
+			req:  &pb.CreateAccountRequest{Currency: account.Currency},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(1).
+					Return(sqlc.Account{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateAccountResponse, err error) {
+				require.Equal(t, codes.AlreadyExists, status.Code(err))
+			},
+		},
+		{
+			name: "OwnerNotFound",
+			req:  &pb.CreateAccountRequest{Currency: account.Currency},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(1).
+					Return(sqlc.Account{}, &pq.Error{Code: "23503"})
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateAccountResponse, err error) {
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		},
+		{
+			name: "InternalError",
+			req:  &pb.CreateAccountRequest{Currency: account.Currency},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(1).Return(sqlc.Account{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateAccountResponse, err error) {
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+		{
+			name: "InvalidCurrency",
+			req:  &pb.CreateAccountRequest{Currency: "XYZ"},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateAccountResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "NoAuthorization",
+			req:  &pb.CreateAccountRequest{Currency: account.Currency},
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateAccountResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			rsp, err := server.CreateAccount(tc.buildContext(t), tc.req)
+			tc.checkResponse(t, rsp, err)
+		})
+	}
+}
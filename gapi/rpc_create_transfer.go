@@ -0,0 +1,127 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/October-9th/simple-bank/authz"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/money"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/October-9th/simple-bank/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateTransfer(ctx context.Context, req *pb.CreateTransferRequest) (*pb.CreateTransferResponse, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateCreateTransferRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	fromAccount, err := server.getAuthorizedAccount(ctx, req.GetFromAccountId(), authPayload, authz.TransferFrom)
+	if err != nil {
+		return nil, err
+	}
+	if fromAccount.Status != util.AccountActive {
+		return nil, status.Errorf(codes.FailedPrecondition, "account [%d] is %s", fromAccount.ID, fromAccount.Status)
+	}
+	// The amount is in the currency of the source account, the destination may use another one
+	if fromAccount.Currency != req.GetCurrency() {
+		return nil, status.Errorf(codes.InvalidArgument, "account [%d] currency mismatch: %s vs %s", fromAccount.ID, fromAccount.Currency, req.GetCurrency())
+	}
+
+	toAccount, err := server.store.GetAccount(ctx, req.GetToAccountId())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "account doesn't exist")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+	if toAccount.Status != util.AccountActive {
+		return nil, status.Errorf(codes.FailedPrecondition, "account [%d] is %s", toAccount.ID, toAccount.Status)
+	}
+
+	amount, err := money.New(req.GetAmount(), req.GetCurrency())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+	}
+
+	arg := sqlc.TransferTxParams{
+		FromAccountID:     req.GetFromAccountId(),
+		ToAccountID:       req.GetToAccountId(),
+		Amount:            amount,
+		Memo:              req.GetMemo(),
+		ExternalReference: req.GetExternalReference(),
+		Metadata:          req.GetMetadata(),
+	}
+	if req.GetIdempotencyKey() != "" {
+		arg.IdempotencyKey = req.GetIdempotencyKey()
+		arg.Username = authPayload.Username
+	}
+
+	result, err := server.store.TransferTx(ctx, arg)
+	if err != nil {
+		if errors.Is(err, sqlc.ErrInsufficientFunds) || errors.Is(err, sqlc.ErrAccountClosed) || errors.Is(err, sqlc.ErrAccountFrozen) ||
+			errors.Is(err, sqlc.ErrNoExchangeRate) || errors.Is(err, money.ErrOverflow) || errors.Is(err, sqlc.ErrSameAccount) ||
+			errors.Is(err, money.ErrCurrencyMismatch) || errors.Is(err, sqlc.ErrAmountTooSmallToConvert) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		if errors.Is(err, sqlc.ErrIdempotencyKeyReused) {
+			return nil, status.Errorf(codes.AlreadyExists, "%s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to transfer: %s", err)
+	}
+
+	rsp := &pb.CreateTransferResponse{
+		Transfer:    convertTransfer(result.Transfer),
+		FromAccount: convertAccount(result.FromAccount),
+		ToAccount:   convertAccount(result.ToAccount),
+		FromEntry:   convertEntry(result.FromEntry),
+		ToEntry:     convertEntry(result.ToEntry),
+	}
+	return rsp, nil
+}
+
+func validateCreateTransferRequest(req *pb.CreateTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validate.ValidateID(req.GetFromAccountId()); err != nil {
+		violations = append(violations, fieldViolation("from_account_id", err))
+	}
+	if err := validate.ValidateID(req.GetToAccountId()); err != nil {
+		violations = append(violations, fieldViolation("to_account_id", err))
+	}
+	if err := validate.ValidateTransferAccounts(req.GetFromAccountId(), req.GetToAccountId()); err != nil {
+		violations = append(violations, fieldViolation("to_account_id", err))
+	}
+	if err := validate.ValidateAmount(req.GetAmount()); err != nil {
+		violations = append(violations, fieldViolation("amount", err))
+	}
+	if err := validate.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+	if req.GetIdempotencyKey() != "" {
+		if err := validate.ValidateIdempotencyKey(req.GetIdempotencyKey()); err != nil {
+			violations = append(violations, fieldViolation("idempotency_key", err))
+		}
+	}
+	if err := validate.ValidateMemo(req.GetMemo()); err != nil {
+		violations = append(violations, fieldViolation("memo", err))
+	}
+	if req.GetExternalReference() != "" {
+		if err := validate.ValidateExternalReference(req.GetExternalReference()); err != nil {
+			violations = append(violations, fieldViolation("external_reference", err))
+		}
+	}
+	if err := validate.ValidateMetadata(req.GetMetadata()); err != nil {
+		violations = append(violations, fieldViolation("metadata", err))
+	}
+	return violations
+}
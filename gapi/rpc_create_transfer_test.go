@@ -0,0 +1,208 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	mockdb "github.com/October-9th/simple-bank/database/mock"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/money"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCreateTransferAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	fromAccount := randomAccount(owner)
+	fromAccount.ID = 1
+	toAccount := randomAccount(util.RandomOwner())
+	toAccount.ID = 2
+	toAccount.Currency = fromAccount.Currency
+	frozenAccount := fromAccount
+	frozenAccount.Status = util.AccountFrozen
+	amount := int64(10)
+
+	newRequest := func() *pb.CreateTransferRequest {
+		return &pb.CreateTransferRequest{
+			FromAccountId: fromAccount.ID,
+			ToAccountId:   toAccount.ID,
+			Amount:        amount,
+			Currency:      fromAccount.Currency,
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		req           func() *pb.CreateTransferRequest
+		buildContext  func(t *testing.T) context.Context
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, rsp *pb.CreateTransferResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  newRequest,
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				arg := sqlc.TransferTxParams{
+					FromAccountID: fromAccount.ID,
+					ToAccountID:   toAccount.ID,
+					Amount:        money.Amount{Minor: amount, Currency: fromAccount.Currency},
+				}
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(sqlc.TransferTxResult{
+					Transfer: sqlc.Transfer{ID: 7, FromAccountID: fromAccount.ID, ToAccountID: toAccount.ID, Amount: amount},
+				}, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int64(7), rsp.GetTransfer().GetId())
+				require.Equal(t, amount, rsp.GetTransfer().GetAmount())
+			},
+		},
+		{
+			// Staff can read every account, but only send money from their own
+			name: "BankerFromOtherUserAccount",
+			req:  newRequest,
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, util.RandomOwner(), util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateTransferResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "FromAccountNotFound",
+			req:  newRequest,
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(sqlc.Account{}, sql.ErrNoRows)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateTransferResponse, err error) {
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name: "ToAccountNotFound",
+			req:  newRequest,
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(sqlc.Account{}, sql.ErrNoRows)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateTransferResponse, err error) {
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name: "FrozenFromAccount",
+			req:  newRequest,
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(frozenAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateTransferResponse, err error) {
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		},
+		{
+			name: "InsufficientFunds",
+			req:  newRequest,
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(sqlc.TransferTxResult{}, sqlc.ErrInsufficientFunds)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateTransferResponse, err error) {
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		},
+		{
+			name: "AmountTooSmallToConvert",
+			req:  newRequest,
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(sqlc.TransferTxResult{}, sqlc.ErrAmountTooSmallToConvert)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateTransferResponse, err error) {
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		},
+		{
+			name: "SameAccount",
+			req: func() *pb.CreateTransferRequest {
+				req := newRequest()
+				req.ToAccountId = req.FromAccountId
+				return req
+			},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateTransferResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "NoAuthorization",
+			req:  newRequest,
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateTransferResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			rsp, err := server.CreateTransfer(tc.buildContext(t), tc.req())
+			tc.checkResponse(t, rsp, err)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/October-9th/simple-bank/authz"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/token"
+	"github.com/October-9th/simple-bank/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) FreezeAccount(ctx context.Context, req *pb.FreezeAccountRequest) (*pb.FreezeAccountResponse, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateAccountStatusRequest(req.GetId())
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	account, err := server.changeAccountStatus(ctx, req.GetId(), authPayload, server.store.FreezeAccountTx)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.FreezeAccountResponse{
+		Account: convertAccount(account),
+	}
+	return rsp, nil
+}
+
+func (server *Server) UnfreezeAccount(ctx context.Context, req *pb.UnfreezeAccountRequest) (*pb.UnfreezeAccountResponse, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateAccountStatusRequest(req.GetId())
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	account, err := server.changeAccountStatus(ctx, req.GetId(), authPayload, server.store.UnfreezeAccountTx)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.UnfreezeAccountResponse{
+		Account: convertAccount(account),
+	}
+	return rsp, nil
+}
+
+// changeAccountStatus checks that an admin is asking before applying change.
+// The returned error is already a gRPC status
+func (server *Server) changeAccountStatus(
+	ctx context.Context,
+	accountID int64,
+	authPayload *token.Payload,
+	change func(context.Context, int64) (sqlc.Account, error),
+) (sqlc.Account, error) {
+	if _, err := server.getAuthorizedAccount(ctx, accountID, authPayload, authz.FreezeAccount); err != nil {
+		return sqlc.Account{}, err
+	}
+
+	account, err := change(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, sqlc.ErrAccountClosed) || errors.Is(err, sqlc.ErrInvalidStatusTransition) {
+			return account, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		return account, status.Errorf(codes.Internal, "failed to change account status: %s", err)
+	}
+	return account, nil
+}
+
+func validateAccountStatusRequest(accountID int64) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validate.ValidateID(accountID); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	return violations
+}
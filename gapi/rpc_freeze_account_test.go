@@ -0,0 +1,149 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	mockdb "github.com/October-9th/simple-bank/database/mock"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFreezeAccountAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	account := randomAccount(owner)
+
+	frozen := account
+	frozen.Status = util.AccountFrozen
+
+	testCases := []struct {
+		name          string
+		req           *pb.FreezeAccountRequest
+		buildContext  func(t *testing.T) context.Context
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, rsp *pb.FreezeAccountResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.FreezeAccountRequest{Id: account.ID},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "admin", util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().FreezeAccountTx(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(frozen, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.FreezeAccountResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, util.AccountFrozen, rsp.GetAccount().GetStatus())
+			},
+		},
+		{
+			// Owners can't freeze their own account, nor can bankers
+			name: "NotAdmin",
+			req:  &pb.FreezeAccountRequest{Id: account.ID},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().FreezeAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.FreezeAccountResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "NotFound",
+			req:  &pb.FreezeAccountRequest{Id: account.ID},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "admin", util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(sqlc.Account{}, sql.ErrNoRows)
+				store.EXPECT().FreezeAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.FreezeAccountResponse, err error) {
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name: "AlreadyFrozen",
+			req:  &pb.FreezeAccountRequest{Id: account.ID},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "admin", util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(frozen, nil)
+				store.EXPECT().FreezeAccountTx(gomock.Any(), gomock.Eq(account.ID)).Times(1).
+					Return(frozen, sqlc.ErrInvalidStatusTransition)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.FreezeAccountResponse, err error) {
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		},
+		{
+			name: "InternalError",
+			req:  &pb.FreezeAccountRequest{Id: account.ID},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "admin", util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().FreezeAccountTx(gomock.Any(), gomock.Any()).Times(1).Return(sqlc.Account{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.FreezeAccountResponse, err error) {
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+		{
+			name: "InvalidID",
+			req:  &pb.FreezeAccountRequest{Id: 0},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "admin", util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().FreezeAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.FreezeAccountResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "NoAuthorization",
+			req:  &pb.FreezeAccountRequest{Id: account.ID},
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().FreezeAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.FreezeAccountResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			rsp, err := server.FreezeAccount(tc.buildContext(t), tc.req)
+			tc.checkResponse(t, rsp, err)
+		})
+	}
+}
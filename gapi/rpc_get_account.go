@@ -0,0 +1,61 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/October-9th/simple-bank/authz"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/token"
+	"github.com/October-9th/simple-bank/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) GetAccount(ctx context.Context, req *pb.GetAccountRequest) (*pb.GetAccountResponse, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateGetAccountRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	account, err := server.getAuthorizedAccount(ctx, req.GetId(), authPayload, authz.ReadAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.GetAccountResponse{
+		Account: convertAccount(account),
+	}
+	return rsp, nil
+}
+
+func validateGetAccountRequest(req *pb.GetAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validate.ValidateID(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	return violations
+}
+
+// getAuthorizedAccount fetches an account and makes sure the authenticated user may perform action on it.
+// The returned error is already a gRPC status
+func (server *Server) getAuthorizedAccount(ctx context.Context, accountID int64, authPayload *token.Payload, action authz.Action) (sqlc.Account, error) {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return account, status.Errorf(codes.NotFound, "account doesn't exist")
+		}
+		return account, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	if err := authz.Authorize(authPayload, action, account.Owner); err != nil {
+		return account, permissionDeniedError(err)
+	}
+	return account, nil
+}
@@ -0,0 +1,87 @@
+package gapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/October-9th/simple-bank/authz"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/statement"
+	"github.com/October-9th/simple-bank/validate"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetAccountStatement renders the statement as a raw body, so the gateway serves CSV and OFX files as they are
+func (server *Server) GetAccountStatement(ctx context.Context, req *pb.GetAccountStatementRequest) (*httpbody.HttpBody, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateGetAccountStatementRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	if _, err := server.getAuthorizedAccount(ctx, req.GetAccountId(), authPayload, authz.ReadAccount); err != nil {
+		return nil, err
+	}
+
+	from := req.GetFrom().AsTime()
+	to := req.GetTo().AsTime()
+	result, err := server.store.StatementTx(ctx, sqlc.StatementTxParams{
+		AccountID: req.GetAccountId(),
+		From:      from,
+		To:        to,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get statement: %s", err)
+	}
+
+	stmt, err := statement.New(result, from, to, time.Now())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to build statement: %s", err)
+	}
+
+	format := req.GetFormat()
+	if format == "" {
+		format = statement.FormatJSON
+	}
+	var body bytes.Buffer
+	if err := statement.Write(&body, format, stmt); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to render statement: %s", err)
+	}
+
+	rsp := &httpbody.HttpBody{
+		ContentType: statement.ContentType(format),
+		Data:        body.Bytes(),
+	}
+	return rsp, nil
+}
+
+func validateGetAccountStatementRequest(req *pb.GetAccountStatementRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validate.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+	if req.GetFrom() == nil {
+		violations = append(violations, fieldViolation("from", fmt.Errorf("is required")))
+	}
+	if req.GetTo() == nil {
+		violations = append(violations, fieldViolation("to", fmt.Errorf("is required")))
+	}
+	if req.GetFrom() != nil && req.GetTo() != nil {
+		if err := validate.ValidateStatementPeriod(req.GetFrom().AsTime(), req.GetTo().AsTime()); err != nil {
+			violations = append(violations, fieldViolation("to", err))
+		}
+	}
+	if req.GetFormat() != "" && !statement.IsSupportedFormat(req.GetFormat()) {
+		violations = append(violations, fieldViolation("format", statement.ErrUnknownFormat))
+	}
+	return violations
+}
@@ -0,0 +1,204 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/October-9th/simple-bank/database/mock"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/statement"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestGetAccountStatementAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	account := randomAccount(owner)
+	from := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	result := sqlc.StatementTxResult{
+		Account:        account,
+		OpeningBalance: 100,
+		ClosingBalance: 75,
+		Entries: []sqlc.ListStatementEntriesRow{
+			{ID: 1, AccountID: account.ID, Amount: -25, CreatedAt: from.Add(time.Hour)},
+		},
+	}
+	newRequest := func(format string) *pb.GetAccountStatementRequest {
+		return &pb.GetAccountStatementRequest{
+			AccountId: account.ID,
+			From:      timestamppb.New(from),
+			To:        timestamppb.New(to),
+			Format:    format,
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.GetAccountStatementRequest
+		buildContext  func(t *testing.T) context.Context
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, rsp *httpbody.HttpBody, err error)
+	}{
+		{
+			// JSON is the default format
+			name: "OK",
+			req:  newRequest(""),
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := sqlc.StatementTxParams{AccountID: account.ID, From: from, To: to}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().StatementTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *httpbody.HttpBody, err error) {
+				require.NoError(t, err)
+				require.Equal(t, statement.ContentType(statement.FormatJSON), rsp.GetContentType())
+				require.NotEmpty(t, rsp.GetData())
+			},
+		},
+		{
+			name: "CSV",
+			req:  newRequest(statement.FormatCSV),
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().StatementTx(gomock.Any(), gomock.Any()).Times(1).Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *httpbody.HttpBody, err error) {
+				require.NoError(t, err)
+				require.Equal(t, statement.ContentType(statement.FormatCSV), rsp.GetContentType())
+				require.NotEmpty(t, rsp.GetData())
+			},
+		},
+		{
+			name: "UnauthorizedUser",
+			req:  newRequest(""),
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, util.RandomOwner(), util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().StatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *httpbody.HttpBody, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "NotFound",
+			req:  newRequest(""),
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(sqlc.Account{}, sql.ErrNoRows)
+				store.EXPECT().StatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *httpbody.HttpBody, err error) {
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name: "InternalError",
+			req:  newRequest(""),
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().StatementTx(gomock.Any(), gomock.Any()).Times(1).Return(sqlc.StatementTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, rsp *httpbody.HttpBody, err error) {
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+		{
+			name: "UnknownFormat",
+			req:  newRequest("pdf"),
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().StatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *httpbody.HttpBody, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "PeriodEndsBeforeItStarts",
+			req: &pb.GetAccountStatementRequest{
+				AccountId: account.ID,
+				From:      timestamppb.New(to),
+				To:        timestamppb.New(from),
+			},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().StatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *httpbody.HttpBody, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "MissingPeriod",
+			req:  &pb.GetAccountStatementRequest{AccountId: account.ID},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().StatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *httpbody.HttpBody, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "NoAuthorization",
+			req:  newRequest(""),
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().StatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *httpbody.HttpBody, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			rsp, err := server.GetAccountStatement(tc.buildContext(t), tc.req)
+			tc.checkResponse(t, rsp, err)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	mockdb "github.com/October-9th/simple-bank/database/mock"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetAccountAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	account := randomAccount(owner)
+
+	testCases := []struct {
+		name          string
+		req           *pb.GetAccountRequest
+		buildContext  func(t *testing.T) context.Context
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, rsp *pb.GetAccountResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.GetAccountRequest{Id: account.ID},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.GetAccountResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, account.ID, rsp.GetAccount().GetId())
+				require.Equal(t, owner, rsp.GetAccount().GetOwner())
+				require.Equal(t, account.Balance, rsp.GetAccount().GetBalance())
+			},
+		},
+		{
+			// Staff can read the accounts of every user
+			name: "Banker",
+			req:  &pb.GetAccountRequest{Id: account.ID},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, util.RandomOwner(), util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.GetAccountResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, account.ID, rsp.GetAccount().GetId())
+			},
+		},
+		{
+			name: "OtherUserAccount",
+			req:  &pb.GetAccountRequest{Id: account.ID},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, util.RandomOwner(), util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.GetAccountResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+				require.Nil(t, rsp)
+			},
+		},
+		{
+			name: "NotFound",
+			req:  &pb.GetAccountRequest{Id: account.ID},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(sqlc.Account{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.GetAccountResponse, err error) {
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name: "InternalError",
+			req:  &pb.GetAccountRequest{Id: account.ID},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(1).Return(sqlc.Account{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.GetAccountResponse, err error) {
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+		{
+			name: "InvalidID",
+			req:  &pb.GetAccountRequest{Id: 0},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.GetAccountResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "NoAuthorization",
+			req:  &pb.GetAccountRequest{Id: account.ID},
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.GetAccountResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			rsp, err := server.GetAccount(tc.buildContext(t), tc.req)
+			tc.checkResponse(t, rsp, err)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/October-9th/simple-bank/authz"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) GetBalanceAsOf(ctx context.Context, req *pb.GetBalanceAsOfRequest) (*pb.GetBalanceAsOfResponse, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateGetBalanceAsOfRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	if _, err := server.getAuthorizedAccount(ctx, req.GetAccountId(), authPayload, authz.ReadAccount); err != nil {
+		return nil, err
+	}
+
+	balance, err := server.store.GetBalanceAsOf(ctx, sqlc.GetBalanceAsOfParams{
+		AccountID: req.GetAccountId(),
+		AsOf:      req.GetAsOf().AsTime(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get balance: %s", err)
+	}
+
+	rsp := &pb.GetBalanceAsOfResponse{
+		Balance: convertAccountBalance(balance),
+	}
+	return rsp, nil
+}
+
+func validateGetBalanceAsOfRequest(req *pb.GetBalanceAsOfRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validate.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+	if req.GetAsOf() == nil {
+		violations = append(violations, fieldViolation("as_of", fmt.Errorf("is required")))
+	}
+	return violations
+}
@@ -0,0 +1,276 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/October-9th/simple-bank/database/mock"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestGetBalanceAsOfAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	account := randomAccount(owner)
+	asOf := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	balance := sqlc.AccountBalance{
+		AccountID: account.ID,
+		Currency:  account.Currency,
+		Balance:   util.RandomMoney(),
+		AsOf:      asOf,
+	}
+	newRequest := func() *pb.GetBalanceAsOfRequest {
+		return &pb.GetBalanceAsOfRequest{AccountId: account.ID, AsOf: timestamppb.New(asOf)}
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.GetBalanceAsOfRequest
+		buildContext  func(t *testing.T) context.Context
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, rsp *pb.GetBalanceAsOfResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  newRequest(),
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := sqlc.GetBalanceAsOfParams{AccountID: account.ID, AsOf: asOf}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetBalanceAsOf(gomock.Any(), gomock.Eq(arg)).Times(1).Return(balance, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.GetBalanceAsOfResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, account.ID, rsp.GetBalance().GetAccountId())
+				require.Equal(t, balance.Balance, rsp.GetBalance().GetBalance().GetMinorUnits())
+				require.Equal(t, account.Currency, rsp.GetBalance().GetBalance().GetCurrency())
+				require.True(t, asOf.Equal(rsp.GetBalance().GetAsOf().AsTime()))
+			},
+		},
+		{
+			name: "UnauthorizedUser",
+			req:  newRequest(),
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, util.RandomOwner(), util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetBalanceAsOf(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.GetBalanceAsOfResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "NotFound",
+			req:  newRequest(),
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(sqlc.Account{}, sql.ErrNoRows)
+				store.EXPECT().GetBalanceAsOf(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.GetBalanceAsOfResponse, err error) {
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name: "InternalError",
+			req:  newRequest(),
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetBalanceAsOf(gomock.Any(), gomock.Any()).Times(1).Return(sqlc.AccountBalance{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.GetBalanceAsOfResponse, err error) {
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+		{
+			name: "MissingAsOf",
+			req:  &pb.GetBalanceAsOfRequest{AccountId: account.ID},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetBalanceAsOf(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.GetBalanceAsOfResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "NoAuthorization",
+			req:  newRequest(),
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetBalanceAsOf(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.GetBalanceAsOfResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			rsp, err := server.GetBalanceAsOf(tc.buildContext(t), tc.req)
+			tc.checkResponse(t, rsp, err)
+		})
+	}
+}
+
+func TestListBalancesAsOfAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	asOf := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	n := 3
+	balances := make([]sqlc.AccountBalance, n)
+	for i := range balances {
+		account := randomAccount(owner)
+		balances[i] = sqlc.AccountBalance{
+			AccountID: account.ID,
+			Currency:  account.Currency,
+			Balance:   account.Balance,
+			AsOf:      asOf,
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.ListBalancesAsOfRequest
+		buildContext  func(t *testing.T) context.Context
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, rsp *pb.ListBalancesAsOfResponse, err error)
+	}{
+		{
+			// Without an owner the balances of the caller are listed
+			name: "OK",
+			req:  &pb.ListBalancesAsOfRequest{AsOf: timestamppb.New(asOf)},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := sqlc.ListBalancesAsOfParams{Owner: owner, AsOf: asOf}
+				store.EXPECT().ListBalancesAsOf(gomock.Any(), gomock.Eq(arg)).Times(1).Return(balances, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ListBalancesAsOfResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, rsp.GetBalances(), n)
+				for i, balance := range rsp.GetBalances() {
+					require.Equal(t, balances[i].AccountID, balance.GetAccountId())
+					require.Equal(t, balances[i].Balance, balance.GetBalance().GetMinorUnits())
+				}
+			},
+		},
+		{
+			name: "StaffListsAnotherOwner",
+			req:  &pb.ListBalancesAsOfRequest{Owner: owner, AsOf: timestamppb.New(asOf)},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "banker", util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := sqlc.ListBalancesAsOfParams{Owner: owner, AsOf: asOf}
+				store.EXPECT().ListBalancesAsOf(gomock.Any(), gomock.Eq(arg)).Times(1).Return(balances, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ListBalancesAsOfResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, rsp.GetBalances(), n)
+			},
+		},
+		{
+			name: "DepositorListsAnotherOwner",
+			req:  &pb.ListBalancesAsOfRequest{Owner: owner, AsOf: timestamppb.New(asOf)},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, util.RandomOwner(), util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListBalancesAsOf(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ListBalancesAsOfResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "InternalError",
+			req:  &pb.ListBalancesAsOfRequest{AsOf: timestamppb.New(asOf)},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListBalancesAsOf(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ListBalancesAsOfResponse, err error) {
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+		{
+			name: "MissingAsOf",
+			req:  &pb.ListBalancesAsOfRequest{},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListBalancesAsOf(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ListBalancesAsOfResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "NoAuthorization",
+			req:  &pb.ListBalancesAsOfRequest{AsOf: timestamppb.New(asOf)},
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListBalancesAsOf(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ListBalancesAsOfResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			rsp, err := server.ListBalancesAsOf(tc.buildContext(t), tc.req)
+			tc.checkResponse(t, rsp, err)
+		})
+	}
+}
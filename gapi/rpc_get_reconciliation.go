@@ -0,0 +1,55 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/October-9th/simple-bank/authz"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) GetReconciliation(ctx context.Context, req *pb.GetReconciliationRequest) (*pb.GetReconciliationResponse, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if err := authz.Authorize(authPayload, authz.Reconcile, ""); err != nil {
+		return nil, permissionDeniedError(err)
+	}
+
+	violations := validateGetReconciliationRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	run, err := server.store.GetReconciliationRun(ctx, req.GetId())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "reconciliation run doesn't exist")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get reconciliation run: %s", err)
+	}
+
+	discrepancies, err := server.store.ListReconciliationDiscrepancies(ctx, run.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list discrepancies: %s", err)
+	}
+
+	rsp := &pb.GetReconciliationResponse{
+		Run:           convertReconciliationRun(run),
+		Discrepancies: convertDiscrepancies(discrepancies),
+	}
+	return rsp, nil
+}
+
+func validateGetReconciliationRequest(req *pb.GetReconciliationRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validate.ValidateID(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	return violations
+}
@@ -0,0 +1,66 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/October-9th/simple-bank/authz"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListAccounts(ctx context.Context, req *pb.ListAccountsRequest) (*pb.ListAccountsResponse, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateListAccountsRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	owner := authPayload.Username
+	if req.GetOwner() != "" {
+		owner = req.GetOwner()
+	}
+	if err := authz.Authorize(authPayload, authz.ReadAccount, owner); err != nil {
+		return nil, permissionDeniedError(err)
+	}
+
+	arg := sqlc.ListAccountsParams{
+		Owner:  owner,
+		Limit:  req.GetPageSize(),
+		Offset: (req.GetPageId() - 1) * req.GetPageSize(),
+	}
+	accounts, err := server.store.ListAccounts(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list accounts: %s", err)
+	}
+
+	rsp := &pb.ListAccountsResponse{
+		Accounts: make([]*pb.Account, 0, len(accounts)),
+	}
+	for _, account := range accounts {
+		rsp.Accounts = append(rsp.Accounts, convertAccount(account))
+	}
+	return rsp, nil
+}
+
+func validateListAccountsRequest(req *pb.ListAccountsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.GetOwner() != "" {
+		if err := validate.ValidateUsername(req.GetOwner()); err != nil {
+			violations = append(violations, fieldViolation("owner", err))
+		}
+	}
+	if err := validate.ValidatePageID(req.GetPageId()); err != nil {
+		violations = append(violations, fieldViolation("page_id", err))
+	}
+	if err := validate.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+	return violations
+}
@@ -0,0 +1,128 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	mockdb "github.com/October-9th/simple-bank/database/mock"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestListAccountsAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	accounts := []sqlc.Account{randomAccount(owner), randomAccount(owner)}
+
+	testCases := []struct {
+		name          string
+		req           *pb.ListAccountsRequest
+		buildContext  func(t *testing.T) context.Context
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, rsp *pb.ListAccountsResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.ListAccountsRequest{PageId: 2, PageSize: 5},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := sqlc.ListAccountsParams{Owner: owner, Limit: 5, Offset: 5}
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Eq(arg)).Times(1).Return(accounts, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ListAccountsResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, rsp.GetAccounts(), len(accounts))
+			},
+		},
+		{
+			name: "BankerListsOtherUser",
+			req:  &pb.ListAccountsRequest{PageId: 1, PageSize: 5, Owner: owner},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, util.RandomOwner(), util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := sqlc.ListAccountsParams{Owner: owner, Limit: 5, Offset: 0}
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Eq(arg)).Times(1).Return(accounts, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ListAccountsResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, rsp.GetAccounts(), len(accounts))
+			},
+		},
+		{
+			name: "DepositorListsOtherUser",
+			req:  &pb.ListAccountsRequest{PageId: 1, PageSize: 5, Owner: owner},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, util.RandomOwner(), util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ListAccountsResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "InternalError",
+			req:  &pb.ListAccountsRequest{PageId: 1, PageSize: 5},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ListAccountsResponse, err error) {
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+		{
+			name: "InvalidPageSize",
+			req:  &pb.ListAccountsRequest{PageId: 1, PageSize: 100},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ListAccountsResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "NoAuthorization",
+			req:  &pb.ListAccountsRequest{PageId: 1, PageSize: 5},
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ListAccountsResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			rsp, err := server.ListAccounts(tc.buildContext(t), tc.req)
+			tc.checkResponse(t, rsp, err)
+		})
+	}
+}
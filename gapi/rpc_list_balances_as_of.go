@@ -0,0 +1,62 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/October-9th/simple-bank/authz"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListBalancesAsOf(ctx context.Context, req *pb.ListBalancesAsOfRequest) (*pb.ListBalancesAsOfResponse, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateListBalancesAsOfRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	owner := authPayload.Username
+	if req.GetOwner() != "" {
+		owner = req.GetOwner()
+	}
+	if err := authz.Authorize(authPayload, authz.ReadAccount, owner); err != nil {
+		return nil, permissionDeniedError(err)
+	}
+
+	balances, err := server.store.ListBalancesAsOf(ctx, sqlc.ListBalancesAsOfParams{
+		Owner: owner,
+		AsOf:  req.GetAsOf().AsTime(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list balances: %s", err)
+	}
+
+	rsp := &pb.ListBalancesAsOfResponse{
+		Balances: make([]*pb.AccountBalance, 0, len(balances)),
+	}
+	for _, balance := range balances {
+		rsp.Balances = append(rsp.Balances, convertAccountBalance(balance))
+	}
+	return rsp, nil
+}
+
+func validateListBalancesAsOfRequest(req *pb.ListBalancesAsOfRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.GetOwner() != "" {
+		if err := validate.ValidateUsername(req.GetOwner()); err != nil {
+			violations = append(violations, fieldViolation("owner", err))
+		}
+	}
+	if req.GetAsOf() == nil {
+		violations = append(violations, fieldViolation("as_of", fmt.Errorf("is required")))
+	}
+	return violations
+}
@@ -0,0 +1,60 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/October-9th/simple-bank/authz"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListEntries(ctx context.Context, req *pb.ListEntriesRequest) (*pb.ListEntriesResponse, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateListEntriesRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	if _, err := server.getAuthorizedAccount(ctx, req.GetAccountId(), authPayload, authz.ReadAccount); err != nil {
+		return nil, err
+	}
+
+	arg := sqlc.ListEntriesParams{
+		AccountID: req.GetAccountId(),
+		Limit:     req.GetPageSize(),
+		Offset:    (req.GetPageId() - 1) * req.GetPageSize(),
+	}
+	entries, err := server.store.ListEntries(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list entries: %s", err)
+	}
+
+	rsp := &pb.ListEntriesResponse{
+		Entries: make([]*pb.Entry, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		rsp.Entries = append(rsp.Entries, convertEntry(entry))
+	}
+	return rsp, nil
+}
+
+func validateListEntriesRequest(req *pb.ListEntriesRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validate.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+	if err := validate.ValidatePageID(req.GetPageId()); err != nil {
+		violations = append(violations, fieldViolation("page_id", err))
+	}
+	if err := validate.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+	return violations
+}
@@ -0,0 +1,55 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/October-9th/simple-bank/authz"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListReconciliations(ctx context.Context, req *pb.ListReconciliationsRequest) (*pb.ListReconciliationsResponse, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if err := authz.Authorize(authPayload, authz.Reconcile, ""); err != nil {
+		return nil, permissionDeniedError(err)
+	}
+
+	violations := validateListReconciliationsRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	runs, err := server.store.ListReconciliationRuns(ctx, sqlc.ListReconciliationRunsParams{
+		Limit:  req.GetPageSize(),
+		Offset: (req.GetPageId() - 1) * req.GetPageSize(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list reconciliation runs: %s", err)
+	}
+
+	rsp := &pb.ListReconciliationsResponse{
+		Runs: make([]*pb.ReconciliationRun, 0, len(runs)),
+	}
+	for _, run := range runs {
+		rsp.Runs = append(rsp.Runs, convertReconciliationRun(run))
+	}
+	return rsp, nil
+}
+
+func validateListReconciliationsRequest(req *pb.ListReconciliationsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validate.ValidatePageID(req.GetPageId()); err != nil {
+		violations = append(violations, fieldViolation("page_id", err))
+	}
+	if err := validate.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+	return violations
+}
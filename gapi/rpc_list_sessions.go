@@ -0,0 +1,33 @@
+package gapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListSessions lists the active sessions of the authenticated user, the most recently used first
+func (server *Server) ListSessions(ctx context.Context, req *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	sessions, err := server.store.ListActiveSessions(ctx, sqlc.ListActiveSessionsParams{
+		Username: authPayload.Username,
+		Now:      time.Now(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list sessions: %s", err)
+	}
+
+	rsp := &pb.ListSessionsResponse{}
+	for _, session := range sessions {
+		rsp.Sessions = append(rsp.Sessions, convertSession(session, authPayload.SessionID))
+	}
+	return rsp, nil
+}
@@ -0,0 +1,126 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/October-9th/simple-bank/database/mock"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/token"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestListSessionsAPI(t *testing.T) {
+	username := util.RandomOwner()
+
+	n := 3
+	sessions := make([]sqlc.ListActiveSessionsRow, n)
+	for i := range sessions {
+		sessions[i] = sqlc.ListActiveSessionsRow{
+			ID:         uuid.New(),
+			Username:   username,
+			UserAgent:  util.RandomString(10),
+			ClientIp:   "203.0.113.7",
+			FamilyID:   uuid.New(),
+			SignedInAt: time.Now().Add(-time.Hour),
+			CreatedAt:  time.Now(),
+			ExpiresAt:  time.Now().Add(time.Hour),
+		}
+	}
+	current := sessions[1].FamilyID
+
+	testCases := []struct {
+		name          string
+		buildContext  func(t *testing.T) context.Context
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, rsp *pb.ListSessionsResponse, err error)
+	}{
+		{
+			name: "OK",
+			buildContext: func(t *testing.T) context.Context {
+				payload, err := token.NewPayLoad(username, util.DepositorRole, time.Minute,
+					token.WithScopes(token.DefaultScopes...), token.WithSessionID(current))
+				require.NoError(t, err)
+				return context.WithValue(context.Background(), authorizationPayloadKey{}, payload)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListActiveSessions(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, arg sqlc.ListActiveSessionsParams) ([]sqlc.ListActiveSessionsRow, error) {
+						require.Equal(t, username, arg.Username)
+						require.WithinDuration(t, time.Now(), arg.Now, time.Second)
+						return sessions, nil
+					})
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ListSessionsResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, rsp.GetSessions(), n)
+				for i, session := range rsp.GetSessions() {
+					require.Equal(t, sessions[i].FamilyID.String(), session.GetId())
+					require.Equal(t, sessions[i].UserAgent, session.GetUserAgent())
+					require.Equal(t, sessions[i].FamilyID == current, session.GetCurrent())
+				}
+			},
+		},
+		{
+			name: "NoSessions",
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, username, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListActiveSessions(gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ListSessionsResponse, err error) {
+				require.NoError(t, err)
+				require.Empty(t, rsp.GetSessions())
+			},
+		},
+		{
+			name: "InternalError",
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, username, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListActiveSessions(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ListSessionsResponse, err error) {
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+		{
+			name: "NoAuthorization",
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListActiveSessions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ListSessionsResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			rsp, err := server.ListSessions(tc.buildContext(t), &pb.ListSessionsRequest{})
+			tc.checkResponse(t, rsp, err)
+		})
+	}
+}
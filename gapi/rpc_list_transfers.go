@@ -0,0 +1,88 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/October-9th/simple-bank/authz"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListTransfers(ctx context.Context, req *pb.ListTransfersRequest) (*pb.ListTransfersResponse, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateListTransfersRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	if _, err := server.getAuthorizedAccount(ctx, req.GetAccountId(), authPayload, authz.ReadAccount); err != nil {
+		return nil, err
+	}
+
+	// Transfers sent or received by the account
+	arg := sqlc.ListTransfersParams{
+		FromAccountID: req.GetAccountId(),
+		ToAccountID:   req.GetAccountId(),
+		Limit:         req.GetPageSize(),
+		Offset:        (req.GetPageId() - 1) * req.GetPageSize(),
+	}
+	if req.GetMemo() != "" {
+		arg.Memo = sql.NullString{String: sqlc.ContainsPattern(req.GetMemo()), Valid: true}
+	}
+	if req.GetExternalReference() != "" {
+		arg.ExternalReference = sql.NullString{String: req.GetExternalReference(), Valid: true}
+	}
+	if len(req.GetMetadata()) > 0 {
+		metadata, err := json.Marshal(req.GetMetadata())
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to encode metadata: %s", err)
+		}
+		arg.Metadata = sql.NullString{String: string(metadata), Valid: true}
+	}
+	transfers, err := server.store.ListTransfers(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list transfers: %s", err)
+	}
+
+	rsp := &pb.ListTransfersResponse{
+		Transfers: make([]*pb.Transfer, 0, len(transfers)),
+	}
+	for _, transfer := range transfers {
+		rsp.Transfers = append(rsp.Transfers, convertTransfer(transfer))
+	}
+	return rsp, nil
+}
+
+func validateListTransfersRequest(req *pb.ListTransfersRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validate.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+	if err := validate.ValidatePageID(req.GetPageId()); err != nil {
+		violations = append(violations, fieldViolation("page_id", err))
+	}
+	if err := validate.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+	if err := validate.ValidateMemo(req.GetMemo()); err != nil {
+		violations = append(violations, fieldViolation("memo", err))
+	}
+	if req.GetExternalReference() != "" {
+		if err := validate.ValidateExternalReference(req.GetExternalReference()); err != nil {
+			violations = append(violations, fieldViolation("external_reference", err))
+		}
+	}
+	if err := validate.ValidateMetadata(req.GetMetadata()); err != nil {
+		violations = append(violations, fieldViolation("metadata", err))
+	}
+	return violations
+}
@@ -0,0 +1,64 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/October-9th/simple-bank/authz"
+	"github.com/October-9th/simple-bank/fx"
+	"github.com/October-9th/simple-bank/pb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) LoadExchangeRates(ctx context.Context, req *pb.LoadExchangeRatesRequest) (*pb.LoadExchangeRatesResponse, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if err := authz.Authorize(authPayload, authz.ManageExchangeRates, ""); err != nil {
+		return nil, permissionDeniedError(err)
+	}
+
+	violations := validateLoadExchangeRatesRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	rates := make([]fx.Rate, len(req.GetRates()))
+	for i, rate := range req.GetRates() {
+		rates[i] = convertRateFromProto(rate)
+	}
+
+	loaded, err := server.store.LoadExchangeRatesTx(ctx, rates)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to load exchange rates: %s", err)
+	}
+
+	rsp := &pb.LoadExchangeRatesResponse{
+		Rates: make([]*pb.ExchangeRate, len(loaded)),
+	}
+	for i, rate := range loaded {
+		rsp.Rates[i] = convertExchangeRate(rate)
+	}
+	return rsp, nil
+}
+
+func validateLoadExchangeRatesRequest(req *pb.LoadExchangeRatesRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if len(req.GetRates()) < 1 || len(req.GetRates()) > 1000 {
+		violations = append(violations, fieldViolation("rates", fmt.Errorf("must contain from 1-1000 rates")))
+		return violations
+	}
+	for i, rate := range req.GetRates() {
+		if rate.GetEffectiveAt() == nil {
+			violations = append(violations, fieldViolation(fmt.Sprintf("rates[%d].effective_at", i), fmt.Errorf("is required")))
+			continue
+		}
+		if err := convertRateFromProto(rate).Validate(); err != nil {
+			violations = append(violations, fieldViolation(fmt.Sprintf("rates[%d]", i), err))
+		}
+	}
+	return violations
+}
@@ -0,0 +1,194 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/October-9th/simple-bank/database/mock"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/fx"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestLoadExchangeRatesAPI(t *testing.T) {
+	effectiveAt := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	newRate := func(base, quote, rate string) *pb.ExchangeRate {
+		return &pb.ExchangeRate{
+			BaseCurrency:  base,
+			QuoteCurrency: quote,
+			Rate:          rate,
+			EffectiveAt:   timestamppb.New(effectiveAt),
+		}
+	}
+	newRequest := func() *pb.LoadExchangeRatesRequest {
+		return &pb.LoadExchangeRatesRequest{Rates: []*pb.ExchangeRate{
+			newRate(util.USD, util.EUR, "0.92"),
+			newRate(util.EUR, util.USD, "1.087"),
+		}}
+	}
+
+	rates := []fx.Rate{
+		{Base: util.USD, Quote: util.EUR, Rate: "0.92", EffectiveAt: effectiveAt},
+		{Base: util.EUR, Quote: util.USD, Rate: "1.087", EffectiveAt: effectiveAt},
+	}
+	loaded := make([]sqlc.ExchangeRate, len(rates))
+	for i, rate := range rates {
+		loaded[i] = sqlc.ExchangeRate{
+			ID:            int64(i + 1),
+			BaseCurrency:  rate.Base,
+			QuoteCurrency: rate.Quote,
+			Rate:          rate.Rate,
+			EffectiveAt:   rate.EffectiveAt,
+			CreatedAt:     time.Now(),
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.LoadExchangeRatesRequest
+		buildContext  func(t *testing.T) context.Context
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, rsp *pb.LoadExchangeRatesResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  newRequest(),
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "admin", util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().LoadExchangeRatesTx(gomock.Any(), gomock.Eq(rates)).Times(1).Return(loaded, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.LoadExchangeRatesResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, rsp.GetRates(), len(rates))
+				for i, rate := range rsp.GetRates() {
+					require.Equal(t, loaded[i].BaseCurrency, rate.GetBaseCurrency())
+					require.Equal(t, loaded[i].QuoteCurrency, rate.GetQuoteCurrency())
+					require.Equal(t, loaded[i].Rate, rate.GetRate())
+				}
+			},
+		},
+		{
+			name: "NotAdmin",
+			req:  newRequest(),
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "banker", util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().LoadExchangeRatesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.LoadExchangeRatesResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "InternalError",
+			req:  newRequest(),
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "admin", util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().LoadExchangeRatesTx(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.LoadExchangeRatesResponse, err error) {
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+		{
+			name: "NoRates",
+			req:  &pb.LoadExchangeRatesRequest{},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "admin", util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().LoadExchangeRatesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.LoadExchangeRatesResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			// One invalid rate rejects the whole file
+			name: "InvalidRate",
+			req: &pb.LoadExchangeRatesRequest{Rates: []*pb.ExchangeRate{
+				newRate(util.USD, util.EUR, "0.92"),
+				newRate(util.USD, util.EUR, "-1"),
+			}},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "admin", util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().LoadExchangeRatesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.LoadExchangeRatesResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "SameCurrency",
+			req:  &pb.LoadExchangeRatesRequest{Rates: []*pb.ExchangeRate{newRate(util.USD, util.USD, "1")}},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "admin", util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().LoadExchangeRatesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.LoadExchangeRatesResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "MissingEffectiveAt",
+			req: &pb.LoadExchangeRatesRequest{Rates: []*pb.ExchangeRate{
+				{BaseCurrency: util.USD, QuoteCurrency: util.EUR, Rate: "0.92"},
+			}},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "admin", util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().LoadExchangeRatesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.LoadExchangeRatesResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "NoAuthorization",
+			req:  newRequest(),
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().LoadExchangeRatesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.LoadExchangeRatesResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			rsp, err := server.LoadExchangeRates(tc.buildContext(t), tc.req)
+			tc.checkResponse(t, rsp, err)
+		})
+	}
+}
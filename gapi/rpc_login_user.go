@@ -6,6 +6,7 @@ import (
 
 	"github.com/October-9th/simple-bank/database/sqlc"
 	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/token"
 	"github.com/October-9th/simple-bank/util"
 	"github.com/October-9th/simple-bank/validate"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -33,16 +34,18 @@ func (server *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (
 		return nil, status.Errorf(codes.Unauthenticated, "password invalid")
 	}
 
-	// If authorized, generate access token
-	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.AccessTokenDuration)
+	// If authorized, generate the refresh token, its id is the id of the session the login starts
+	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, server.config.RefreshTokenDuration,
+		token.WithType(token.TokenTypeRefresh), token.WithScopes(token.DefaultScopes...))
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Error creating access token")
+		return nil, status.Errorf(codes.Internal, "error creating refresh token:")
 	}
 
-	// generate the refresh token
-	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.RefreshTokenDuration)
+	// then the access token of that session
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, server.config.AccessTokenDuration,
+		token.WithScopes(token.DefaultScopes...), token.WithSessionID(refreshPayload.ID))
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error creating refresh token:")
+		return nil, status.Errorf(codes.Internal, "Error creating access token")
 	}
 
 	// Handle metadata from the context
@@ -57,6 +60,8 @@ func (server *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (
 		ClientIp:     metadata.ClientIP,
 		IsBlocked:    false,
 		ExpiresAt:    refreshPayload.ExpiredAt,
+		// A login starts a new family, renewals add to it
+		FamilyID: refreshPayload.ID,
 	})
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "error creating session")
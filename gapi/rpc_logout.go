@@ -0,0 +1,59 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/token"
+	"github.com/October-9th/simple-bank/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Logout blocks the session family of the given refresh token so it can't be renewed anymore
+func (server *Server) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateLogoutRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	refreshPayload, err := server.tokenMaker.VerifyToken(req.GetRefreshToken(), token.TokenTypeRefresh)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if refreshPayload.Username != authPayload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "session doesn't belong to the authenticated user")
+	}
+
+	session, err := server.store.GetSession(ctx, refreshPayload.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "session doesn't exist")
+		}
+		return nil, status.Errorf(codes.Internal, "couldn't find session")
+	}
+
+	if session.RefreshToken != req.GetRefreshToken() {
+		return nil, status.Errorf(codes.Unauthenticated, "mismatched session token")
+	}
+
+	if err = server.store.BlockSessionFamily(ctx, session.FamilyID); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to block session: %s", err)
+	}
+	return &pb.LogoutResponse{}, nil
+}
+
+func validateLogoutRequest(req *pb.LogoutRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validate.ValidateString(req.GetRefreshToken(), 1, 1000); err != nil {
+		violations = append(violations, fieldViolation("refresh_token", err))
+	}
+	return violations
+}
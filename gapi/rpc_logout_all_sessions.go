@@ -0,0 +1,22 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/October-9th/simple-bank/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// LogoutAllSessions blocks every session of the authenticated user
+func (server *Server) LogoutAllSessions(ctx context.Context, req *pb.LogoutAllSessionsRequest) (*pb.LogoutAllSessionsResponse, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if err = server.store.BlockUserSessions(ctx, authPayload.Username); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to block sessions: %s", err)
+	}
+	return &pb.LogoutAllSessionsResponse{}, nil
+}
@@ -0,0 +1,181 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	mockdb "github.com/October-9th/simple-bank/database/mock"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/token"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLogoutAPI(t *testing.T) {
+	username := util.RandomOwner()
+	familyID := uuid.New()
+
+	testCases := []struct {
+		name          string
+		createToken   func(t *testing.T, tokenMaker token.Maker) (string, *token.Payload)
+		buildContext  func(t *testing.T) context.Context
+		buildStubs    func(store *mockdb.MockStore, refreshToken string, payload *token.Payload)
+		checkResponse func(t *testing.T, rsp *pb.LogoutResponse, err error)
+	}{
+		{
+			name: "OK",
+			createToken: func(t *testing.T, tokenMaker token.Maker) (string, *token.Payload) {
+				return newRefreshToken(t, tokenMaker, username)
+			},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, username, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				session := sessionOf(refreshToken, payload, familyID)
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(payload.ID)).Times(1).Return(session, nil)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Eq(familyID)).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.LogoutResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, rsp)
+			},
+		},
+		{
+			name: "TokenOfAnotherUser",
+			createToken: func(t *testing.T, tokenMaker token.Maker) (string, *token.Payload) {
+				return newRefreshToken(t, tokenMaker, util.RandomOwner())
+			},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, username, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.LogoutResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "SessionNotFound",
+			createToken: func(t *testing.T, tokenMaker token.Maker) (string, *token.Payload) {
+				return newRefreshToken(t, tokenMaker, username)
+			},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, username, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(payload.ID)).Times(1).Return(sqlc.Session{}, sql.ErrNoRows)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.LogoutResponse, err error) {
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name: "MismatchedToken",
+			createToken: func(t *testing.T, tokenMaker token.Maker) (string, *token.Payload) {
+				return newRefreshToken(t, tokenMaker, username)
+			},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, username, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				session := sessionOf("another token", payload, familyID)
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(payload.ID)).Times(1).Return(session, nil)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.LogoutResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name: "InternalError",
+			createToken: func(t *testing.T, tokenMaker token.Maker) (string, *token.Payload) {
+				return newRefreshToken(t, tokenMaker, username)
+			},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, username, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				session := sessionOf(refreshToken, payload, familyID)
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(payload.ID)).Times(1).Return(session, nil)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Eq(familyID)).Times(1).Return(sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.LogoutResponse, err error) {
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+		{
+			name: "InvalidToken",
+			createToken: func(t *testing.T, tokenMaker token.Maker) (string, *token.Payload) {
+				return "invalid", nil
+			},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, username, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.LogoutResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name: "MissingToken",
+			createToken: func(t *testing.T, tokenMaker token.Maker) (string, *token.Payload) {
+				return "", nil
+			},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, username, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.LogoutResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "NoAuthorization",
+			createToken: func(t *testing.T, tokenMaker token.Maker) (string, *token.Payload) {
+				return newRefreshToken(t, tokenMaker, username)
+			},
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.LogoutResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store)
+
+			refreshToken, payload := tc.createToken(t, server.tokenMaker)
+			tc.buildStubs(store, refreshToken, payload)
+
+			rsp, err := server.Logout(tc.buildContext(t), &pb.LogoutRequest{RefreshToken: refreshToken})
+			tc.checkResponse(t, rsp, err)
+		})
+	}
+}
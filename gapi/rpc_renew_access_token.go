@@ -0,0 +1,111 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/token"
+	"github.com/October-9th/simple-bank/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (server *Server) RenewAccessToken(ctx context.Context, req *pb.RenewAccessTokenRequest) (*pb.RenewAccessTokenResponse, error) {
+	violations := validateRenewAccessTokenRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	refreshPayload, err := server.tokenMaker.VerifyToken(req.GetRefreshToken(), token.TokenTypeRefresh)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	session, err := server.store.GetSession(ctx, refreshPayload.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "session doesn't exist")
+		}
+		return nil, status.Errorf(codes.Internal, "couldn't find session")
+	}
+
+	if session.IsBlocked {
+		return nil, status.Errorf(codes.Unauthenticated, "blocked session")
+	}
+
+	if session.Username != refreshPayload.Username {
+		return nil, status.Errorf(codes.Unauthenticated, "incorrect session user")
+	}
+
+	if session.RefreshToken != req.GetRefreshToken() {
+		return nil, status.Errorf(codes.Unauthenticated, "mismatched session token")
+	}
+
+	if time.Now().After(session.ExpiresAt) {
+		return nil, status.Errorf(codes.Unauthenticated, "expired session")
+	}
+
+	// Read the role again, it may have changed since the session was created
+	user, err := server.store.GetUser(ctx, session.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "couldn't find user")
+	}
+
+	// With the scopes granted when the session started
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, server.config.AccessTokenDuration,
+		token.WithScopes(refreshPayload.Scopes...), token.WithSessionID(session.FamilyID))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error creating access token")
+	}
+
+	// The refresh token being used is replaced by a new one of the same session family
+	newRefreshToken, newRefreshPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, server.config.RefreshTokenDuration,
+		token.WithType(token.TokenTypeRefresh), token.WithScopes(refreshPayload.Scopes...))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error creating refresh token")
+	}
+
+	metadata := server.extractMetadata(ctx)
+	result, err := server.store.RotateSessionTx(ctx, sqlc.RotateSessionTxParams{
+		SessionID: session.ID,
+		FamilyID:  session.FamilyID,
+		NewSession: sqlc.CreateSessionParams{
+			ID:           newRefreshPayload.ID,
+			Username:     refreshPayload.Username,
+			RefreshToken: newRefreshToken,
+			UserAgent:    metadata.UserAgent,
+			ClientIp:     metadata.ClientIP,
+			IsBlocked:    false,
+			ExpiresAt:    newRefreshPayload.ExpiredAt,
+		},
+	})
+	if err != nil {
+		if errors.Is(err, sqlc.ErrRefreshTokenReused) || errors.Is(err, sqlc.ErrSessionFamilyExpired) {
+			return nil, status.Errorf(codes.Unauthenticated, "%s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "error rotating session")
+	}
+
+	// The refresh token works until its session expires, no later than the login of the family
+	rsp := &pb.RenewAccessTokenResponse{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  timestamppb.New(accessPayload.ExpiredAt),
+		RefreshToken:          newRefreshToken,
+		RefreshTokenExpiresAt: timestamppb.New(result.Session.ExpiresAt),
+		SessionId:             session.FamilyID.String(),
+	}
+	return rsp, nil
+}
+
+func validateRenewAccessTokenRequest(req *pb.RenewAccessTokenRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validate.ValidateString(req.GetRefreshToken(), 1, 1000); err != nil {
+		violations = append(violations, fieldViolation("refresh_token", err))
+	}
+	return violations
+}
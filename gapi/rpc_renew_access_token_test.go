@@ -0,0 +1,220 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/October-9th/simple-bank/database/mock"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/token"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newRefreshToken creates a refresh token of username signed by tokenMaker
+func newRefreshToken(t *testing.T, tokenMaker token.Maker, username string) (string, *token.Payload) {
+	refreshToken, payload, err := tokenMaker.CreateToken(username, util.DepositorRole, time.Hour,
+		token.WithType(token.TokenTypeRefresh), token.WithScopes(token.DefaultScopes...))
+	require.NoError(t, err)
+	return refreshToken, payload
+}
+
+// sessionOf returns the stored session of a refresh token
+func sessionOf(refreshToken string, payload *token.Payload, familyID uuid.UUID) sqlc.Session {
+	return sqlc.Session{
+		ID:           payload.ID,
+		FamilyID:     familyID,
+		Username:     payload.Username,
+		RefreshToken: refreshToken,
+		ExpiresAt:    payload.ExpiredAt,
+	}
+}
+
+func TestRenewAccessTokenAPI(t *testing.T) {
+	username := util.RandomOwner()
+	familyID := uuid.New()
+	user := sqlc.User{Username: username, Role: util.BankerRole}
+
+	testCases := []struct {
+		name          string
+		createToken   func(t *testing.T, tokenMaker token.Maker) (string, *token.Payload)
+		buildStubs    func(store *mockdb.MockStore, refreshToken string, payload *token.Payload)
+		checkResponse func(t *testing.T, tokenMaker token.Maker, rsp *pb.RenewAccessTokenResponse, err error)
+	}{
+		{
+			name: "OK",
+			createToken: func(t *testing.T, tokenMaker token.Maker) (string, *token.Payload) {
+				return newRefreshToken(t, tokenMaker, username)
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				session := sessionOf(refreshToken, payload, familyID)
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(payload.ID)).Times(1).Return(session, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(username)).Times(1).Return(user, nil)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, arg sqlc.RotateSessionTxParams) (sqlc.RotateSessionTxResult, error) {
+						require.Equal(t, session.ID, arg.SessionID)
+						require.Equal(t, familyID, arg.FamilyID)
+						require.Equal(t, username, arg.NewSession.Username)
+						require.NotEqual(t, refreshToken, arg.NewSession.RefreshToken)
+						return sqlc.RotateSessionTxResult{Session: sqlc.Session{
+							ID:        arg.NewSession.ID,
+							FamilyID:  arg.FamilyID,
+							ExpiresAt: arg.NewSession.ExpiresAt,
+						}}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, tokenMaker token.Maker, rsp *pb.RenewAccessTokenResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, familyID.String(), rsp.GetSessionId())
+
+				// The role is read again, and the access token belongs to the session family
+				accessPayload, err := tokenMaker.VerifyToken(rsp.GetAccessToken(), token.TokenTypeAccess)
+				require.NoError(t, err)
+				require.Equal(t, util.BankerRole, accessPayload.Role)
+				require.Equal(t, familyID, accessPayload.SessionID)
+
+				_, err = tokenMaker.VerifyToken(rsp.GetRefreshToken(), token.TokenTypeRefresh)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "SessionNotFound",
+			createToken: func(t *testing.T, tokenMaker token.Maker) (string, *token.Payload) {
+				return newRefreshToken(t, tokenMaker, username)
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(payload.ID)).Times(1).Return(sqlc.Session{}, sql.ErrNoRows)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, tokenMaker token.Maker, rsp *pb.RenewAccessTokenResponse, err error) {
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name: "BlockedSession",
+			createToken: func(t *testing.T, tokenMaker token.Maker) (string, *token.Payload) {
+				return newRefreshToken(t, tokenMaker, username)
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				session := sessionOf(refreshToken, payload, familyID)
+				session.IsBlocked = true
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(payload.ID)).Times(1).Return(session, nil)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, tokenMaker token.Maker, rsp *pb.RenewAccessTokenResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name: "MismatchedToken",
+			createToken: func(t *testing.T, tokenMaker token.Maker) (string, *token.Payload) {
+				return newRefreshToken(t, tokenMaker, username)
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				session := sessionOf("another token", payload, familyID)
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(payload.ID)).Times(1).Return(session, nil)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, tokenMaker token.Maker, rsp *pb.RenewAccessTokenResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name: "ExpiredSession",
+			createToken: func(t *testing.T, tokenMaker token.Maker) (string, *token.Payload) {
+				return newRefreshToken(t, tokenMaker, username)
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				session := sessionOf(refreshToken, payload, familyID)
+				session.ExpiresAt = time.Now().Add(-time.Minute)
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(payload.ID)).Times(1).Return(session, nil)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, tokenMaker token.Maker, rsp *pb.RenewAccessTokenResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			// Renewing with a refresh token that was already rotated out revokes the family
+			name: "RefreshTokenReused",
+			createToken: func(t *testing.T, tokenMaker token.Maker) (string, *token.Payload) {
+				return newRefreshToken(t, tokenMaker, username)
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				session := sessionOf(refreshToken, payload, familyID)
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(payload.ID)).Times(1).Return(session, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(username)).Times(1).Return(user, nil)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(1).
+					Return(sqlc.RotateSessionTxResult{}, sqlc.ErrRefreshTokenReused)
+			},
+			checkResponse: func(t *testing.T, tokenMaker token.Maker, rsp *pb.RenewAccessTokenResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name: "InternalError",
+			createToken: func(t *testing.T, tokenMaker token.Maker) (string, *token.Payload) {
+				return newRefreshToken(t, tokenMaker, username)
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(1).Return(sqlc.Session{}, sql.ErrConnDone)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, tokenMaker token.Maker, rsp *pb.RenewAccessTokenResponse, err error) {
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+		{
+			name: "AccessTokenInsteadOfRefreshToken",
+			createToken: func(t *testing.T, tokenMaker token.Maker) (string, *token.Payload) {
+				accessToken, payload, err := tokenMaker.CreateToken(username, util.DepositorRole, time.Hour,
+					token.WithScopes(token.DefaultScopes...))
+				require.NoError(t, err)
+				return accessToken, payload
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, tokenMaker token.Maker, rsp *pb.RenewAccessTokenResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name: "MissingToken",
+			createToken: func(t *testing.T, tokenMaker token.Maker) (string, *token.Payload) {
+				return "", nil
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, tokenMaker token.Maker, rsp *pb.RenewAccessTokenResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store)
+
+			refreshToken, payload := tc.createToken(t, server.tokenMaker)
+			tc.buildStubs(store, refreshToken, payload)
+
+			rsp, err := server.RenewAccessToken(context.Background(), &pb.RenewAccessTokenRequest{RefreshToken: refreshToken})
+			tc.checkResponse(t, server.tokenMaker, rsp, err)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/October-9th/simple-bank/authz"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/money"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ReverseTransfer(ctx context.Context, req *pb.ReverseTransferRequest) (*pb.ReverseTransferResponse, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateReverseTransferRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	transfer, err := server.store.GetTransfer(ctx, req.GetTransferId())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "transfer doesn't exist")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get transfer: %s", err)
+	}
+
+	// Only the recipient of the transfer, or an admin, may give the money back
+	recipient, err := server.store.GetAccount(ctx, transfer.ToAccountID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+	if err := authz.Authorize(authPayload, authz.ReverseTransfer, recipient.Owner); err != nil {
+		return nil, permissionDeniedError(err)
+	}
+
+	result, err := server.store.ReverseTransferTx(ctx, sqlc.ReverseTransferTxParams{
+		TransferID: transfer.ID,
+		Amount:     req.GetAmount(),
+	})
+	if err != nil {
+		if errors.Is(err, sqlc.ErrReversalExceedsTransfer) || errors.Is(err, sqlc.ErrTransferReversed) ||
+			errors.Is(err, sqlc.ErrReverseReversal) || errors.Is(err, sqlc.ErrInsufficientFunds) ||
+			errors.Is(err, sqlc.ErrInvalidAmount) || errors.Is(err, sqlc.ErrAccountClosed) ||
+			errors.Is(err, sqlc.ErrAccountFrozen) || errors.Is(err, money.ErrOverflow) || errors.Is(err, sqlc.ErrSameAccount) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to reverse transfer: %s", err)
+	}
+
+	rsp := &pb.ReverseTransferResponse{
+		Reversal:    convertTransfer(result.Transfer),
+		Original:    convertTransfer(result.Original),
+		FromAccount: convertAccount(result.FromAccount),
+		ToAccount:   convertAccount(result.ToAccount),
+		FromEntry:   convertEntry(result.FromEntry),
+		ToEntry:     convertEntry(result.ToEntry),
+		Remaining:   result.Remaining,
+	}
+	return rsp, nil
+}
+
+func validateReverseTransferRequest(req *pb.ReverseTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validate.ValidateID(req.GetTransferId()); err != nil {
+		violations = append(violations, fieldViolation("transfer_id", err))
+	}
+	// Zero reverses whatever is left of the transfer
+	if req.GetAmount() != 0 {
+		if err := validate.ValidateAmount(req.GetAmount()); err != nil {
+			violations = append(violations, fieldViolation("amount", err))
+		}
+	}
+	return violations
+}
@@ -0,0 +1,212 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	mockdb "github.com/October-9th/simple-bank/database/mock"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestReverseTransferAPI(t *testing.T) {
+	sender := randomAccount(util.RandomOwner())
+	recipient := randomAccount(util.RandomOwner())
+	recipient.Currency = sender.Currency
+
+	transfer := sqlc.Transfer{
+		ID:            util.RandomInt(1, 1000),
+		FromAccountID: sender.ID,
+		ToAccountID:   recipient.ID,
+		Amount:        100,
+		ToAmount:      100,
+	}
+	result := sqlc.ReverseTransferTxResult{
+		TransferTxResult: sqlc.TransferTxResult{
+			Transfer: sqlc.Transfer{
+				ID:            transfer.ID + 1,
+				FromAccountID: recipient.ID,
+				ToAccountID:   sender.ID,
+				Amount:        40,
+				ToAmount:      40,
+			},
+			FromAccount: recipient,
+			ToAccount:   sender,
+		},
+		Original:  transfer,
+		Remaining: 60,
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.ReverseTransferRequest
+		buildContext  func(t *testing.T) context.Context
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, rsp *pb.ReverseTransferResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.ReverseTransferRequest{TransferId: transfer.ID, Amount: 40},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, recipient.Owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := sqlc.ReverseTransferTxParams{TransferID: transfer.ID, Amount: 40}
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(recipient.ID)).Times(1).Return(recipient, nil)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ReverseTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int64(40), rsp.GetReversal().GetAmount())
+				require.Equal(t, transfer.ID, rsp.GetOriginal().GetId())
+				require.Equal(t, int64(60), rsp.GetRemaining())
+			},
+		},
+		{
+			// Zero reverses whatever is left
+			name: "Remainder",
+			req:  &pb.ReverseTransferRequest{TransferId: transfer.ID},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "admin", util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := sqlc.ReverseTransferTxParams{TransferID: transfer.ID}
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(recipient.ID)).Times(1).Return(recipient, nil)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ReverseTransferResponse, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			// The sender can't pull money back on their own
+			name: "SenderIsNotRecipient",
+			req:  &pb.ReverseTransferRequest{TransferId: transfer.ID},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, sender.Owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(recipient.ID)).Times(1).Return(recipient, nil)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ReverseTransferResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "TransferNotFound",
+			req:  &pb.ReverseTransferRequest{TransferId: transfer.ID},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, recipient.Owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(sqlc.Transfer{}, sql.ErrNoRows)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ReverseTransferResponse, err error) {
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name: "ExceedsTransfer",
+			req:  &pb.ReverseTransferRequest{TransferId: transfer.ID, Amount: 101},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, recipient.Owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(recipient.ID)).Times(1).Return(recipient, nil)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(1).
+					Return(sqlc.ReverseTransferTxResult{}, sqlc.ErrReversalExceedsTransfer)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ReverseTransferResponse, err error) {
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		},
+		{
+			name: "AlreadyReversed",
+			req:  &pb.ReverseTransferRequest{TransferId: transfer.ID},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, recipient.Owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(recipient.ID)).Times(1).Return(recipient, nil)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(1).
+					Return(sqlc.ReverseTransferTxResult{}, sqlc.ErrTransferReversed)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ReverseTransferResponse, err error) {
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		},
+		{
+			name: "InternalError",
+			req:  &pb.ReverseTransferRequest{TransferId: transfer.ID},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, recipient.Owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(recipient.ID)).Times(1).Return(recipient, nil)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(1).
+					Return(sqlc.ReverseTransferTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ReverseTransferResponse, err error) {
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+		{
+			name: "NegativeAmount",
+			req:  &pb.ReverseTransferRequest{TransferId: transfer.ID, Amount: -1},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, recipient.Owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ReverseTransferResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "NoAuthorization",
+			req:  &pb.ReverseTransferRequest{TransferId: transfer.ID},
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.ReverseTransferResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			rsp, err := server.ReverseTransfer(tc.buildContext(t), tc.req)
+			tc.checkResponse(t, rsp, err)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RevokeOtherSessions blocks every session of the authenticated user but the one the call was made with
+func (server *Server) RevokeOtherSessions(ctx context.Context, req *pb.RevokeOtherSessionsRequest) (*pb.RevokeOtherSessionsResponse, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+	// A token from before sessions were stamped on tokens would revoke its own session too
+	if authPayload.SessionID == uuid.Nil {
+		return nil, status.Errorf(codes.Unauthenticated, "access token isn't tied to a session, log in again")
+	}
+
+	revoked, err := server.store.BlockOtherUserSessions(ctx, sqlc.BlockOtherUserSessionsParams{
+		Username:     authPayload.Username,
+		KeptFamilyID: authPayload.SessionID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke sessions: %s", err)
+	}
+	return &pb.RevokeOtherSessionsResponse{RevokedSessions: revoked}, nil
+}
@@ -0,0 +1,46 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RevokeSession blocks a session of the authenticated user, it can't be renewed anymore and its access tokens are refused.
+// Revoking the current session is the same as logging out
+func (server *Server) RevokeSession(ctx context.Context, req *pb.RevokeSessionRequest) (*pb.RevokeSessionResponse, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateRevokeSessionRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	// Only sessions of the user are blocked, those of someone else are as not found as those that don't exist
+	blocked, err := server.store.BlockUserSessionFamily(ctx, sqlc.BlockUserSessionFamilyParams{
+		FamilyID: uuid.MustParse(req.GetSessionId()),
+		Username: authPayload.Username,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke session: %s", err)
+	}
+	if blocked == 0 {
+		return nil, status.Errorf(codes.NotFound, "session not found")
+	}
+	return &pb.RevokeSessionResponse{}, nil
+}
+
+func validateRevokeSessionRequest(req *pb.RevokeSessionRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if _, err := uuid.Parse(req.GetSessionId()); err != nil {
+		violations = append(violations, fieldViolation("session_id", err))
+	}
+	return violations
+}
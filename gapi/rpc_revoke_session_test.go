@@ -0,0 +1,163 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/October-9th/simple-bank/database/mock"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/token"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRevokeSessionAPI(t *testing.T) {
+	username := util.RandomOwner()
+	familyID := uuid.New()
+
+	testCases := []struct {
+		name          string
+		req           *pb.RevokeSessionRequest
+		buildContext  func(t *testing.T) context.Context
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, rsp *pb.RevokeSessionResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.RevokeSessionRequest{SessionId: familyID.String()},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, username, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := sqlc.BlockUserSessionFamilyParams{FamilyID: familyID, Username: username}
+				store.EXPECT().BlockUserSessionFamily(gomock.Any(), gomock.Eq(arg)).Times(1).Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.RevokeSessionResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, rsp)
+			},
+		},
+		{
+			// A session of someone else blocks nothing
+			name: "NotFound",
+			req:  &pb.RevokeSessionRequest{SessionId: familyID.String()},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, util.RandomOwner(), util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().BlockUserSessionFamily(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.RevokeSessionResponse, err error) {
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name: "InternalError",
+			req:  &pb.RevokeSessionRequest{SessionId: familyID.String()},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, username, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().BlockUserSessionFamily(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.RevokeSessionResponse, err error) {
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+		{
+			name: "InvalidSessionID",
+			req:  &pb.RevokeSessionRequest{SessionId: "invalid"},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, username, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().BlockUserSessionFamily(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.RevokeSessionResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "NoAuthorization",
+			req:  &pb.RevokeSessionRequest{SessionId: familyID.String()},
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().BlockUserSessionFamily(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.RevokeSessionResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			rsp, err := server.RevokeSession(tc.buildContext(t), tc.req)
+			tc.checkResponse(t, rsp, err)
+		})
+	}
+}
+
+func TestRenewThenRevokeSession(t *testing.T) {
+	username := util.RandomOwner()
+	familyID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+
+	// A refresh token that was already renewed once, its session isn't the first of the family
+	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(username, util.DepositorRole, time.Hour,
+		token.WithType(token.TokenTypeRefresh), token.WithScopes(token.DefaultScopes...))
+	require.NoError(t, err)
+	session := sqlc.Session{
+		ID:           refreshPayload.ID,
+		FamilyID:     familyID,
+		Username:     username,
+		RefreshToken: refreshToken,
+		ExpiresAt:    refreshPayload.ExpiredAt,
+	}
+	store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(username)).Times(1).
+		Return(sqlc.User{Username: username, Role: util.DepositorRole}, nil)
+	store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ context.Context, arg sqlc.RotateSessionTxParams) (sqlc.RotateSessionTxResult, error) {
+			return sqlc.RotateSessionTxResult{Session: sqlc.Session{
+				ID:        arg.NewSession.ID,
+				FamilyID:  arg.FamilyID,
+				ExpiresAt: arg.NewSession.ExpiresAt,
+			}}, nil
+		})
+
+	renewed, err := server.RenewAccessToken(context.Background(), &pb.RenewAccessTokenRequest{RefreshToken: refreshToken})
+	require.NoError(t, err)
+	require.Equal(t, familyID.String(), renewed.GetSessionId())
+
+	// The session id the renewal returned is the one the session is revoked with
+	arg := sqlc.BlockUserSessionFamilyParams{FamilyID: familyID, Username: username}
+	store.EXPECT().BlockUserSessionFamily(gomock.Any(), gomock.Eq(arg)).Times(1).Return(int64(2), nil)
+
+	_, err = server.RevokeSession(newContextWithPayload(t, username, util.DepositorRole),
+		&pb.RevokeSessionRequest{SessionId: renewed.GetSessionId()})
+	require.NoError(t, err)
+}
@@ -0,0 +1,32 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/October-9th/simple-bank/authz"
+	"github.com/October-9th/simple-bank/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) RunReconciliation(ctx context.Context, req *pb.RunReconciliationRequest) (*pb.RunReconciliationResponse, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if err := authz.Authorize(authPayload, authz.Reconcile, ""); err != nil {
+		return nil, permissionDeniedError(err)
+	}
+
+	result, err := server.store.ReconcileTx(ctx, authPayload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to reconcile: %s", err)
+	}
+
+	rsp := &pb.RunReconciliationResponse{
+		Run:           convertReconciliationRun(result.Run),
+		Discrepancies: convertDiscrepancies(result.Discrepancies),
+	}
+	return rsp, nil
+}
@@ -0,0 +1,120 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/October-9th/simple-bank/database/mock"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRunReconciliationAPI(t *testing.T) {
+	account := randomAccount(util.RandomOwner())
+	result := sqlc.ReconcileTxResult{
+		Run: sqlc.ReconciliationRun{
+			ID:               1,
+			StartedBy:        "admin",
+			AccountsChecked:  10,
+			TransfersChecked: 20,
+			EntriesChecked:   40,
+			DiscrepancyCount: 1,
+			StartedAt:        time.Now().Add(-time.Second),
+			FinishedAt:       time.Now(),
+		},
+		Discrepancies: []sqlc.ReconciliationDiscrepancy{
+			{
+				ID:        1,
+				RunID:     1,
+				Kind:      sqlc.DiscrepancyBalance,
+				AccountID: sql.NullInt64{Int64: account.ID, Valid: true},
+				Expected:  account.Balance,
+				Actual:    account.Balance + 1,
+			},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		buildContext  func(t *testing.T) context.Context
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, rsp *pb.RunReconciliationResponse, err error)
+	}{
+		{
+			name: "OK",
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "admin", util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ReconcileTx(gomock.Any(), gomock.Eq("admin")).Times(1).Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.RunReconciliationResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "admin", rsp.GetRun().GetStartedBy())
+				require.Equal(t, int64(1), rsp.GetRun().GetDiscrepancyCount())
+				require.Len(t, rsp.GetDiscrepancies(), 1)
+				require.Equal(t, sqlc.DiscrepancyBalance, rsp.GetDiscrepancies()[0].GetKind())
+				require.Equal(t, account.ID, rsp.GetDiscrepancies()[0].GetAccountId())
+			},
+		},
+		{
+			name: "NotAdmin",
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "banker", util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ReconcileTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.RunReconciliationResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "InternalError",
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "admin", util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ReconcileTx(gomock.Any(), gomock.Any()).Times(1).Return(sqlc.ReconcileTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.RunReconciliationResponse, err error) {
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+		{
+			name: "NoAuthorization",
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ReconcileTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.RunReconciliationResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			rsp, err := server.RunReconciliation(tc.buildContext(t), &pb.RunReconciliationRequest{})
+			tc.checkResponse(t, rsp, err)
+		})
+	}
+}
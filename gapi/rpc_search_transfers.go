@@ -0,0 +1,126 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/October-9th/simple-bank/cursor"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SearchTransfers only searches the accounts of the authenticated user whatever the role,
+// an account of someone else simply matches nothing
+func (server *Server) SearchTransfers(ctx context.Context, req *pb.SearchTransfersRequest) (*pb.SearchTransfersResponse, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateSearchTransfersRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	arg := sqlc.SearchTransfersParams{
+		Owner: authPayload.Username,
+		// One more row than the page tells whether there is a next page
+		LimitCount: req.GetPageSize() + 1,
+	}
+	if req.GetDirection() != "" {
+		arg.Direction = sql.NullString{String: req.GetDirection(), Valid: true}
+	}
+	if req.GetAccountId() != 0 {
+		arg.AccountID = sql.NullInt64{Int64: req.GetAccountId(), Valid: true}
+	}
+	if req.GetCounterpartyAccountId() != 0 {
+		arg.CounterpartyAccountID = sql.NullInt64{Int64: req.GetCounterpartyAccountId(), Valid: true}
+	}
+	if req.GetMinAmount() != 0 {
+		arg.MinAmount = sql.NullInt64{Int64: req.GetMinAmount(), Valid: true}
+	}
+	if req.GetMaxAmount() != 0 {
+		arg.MaxAmount = sql.NullInt64{Int64: req.GetMaxAmount(), Valid: true}
+	}
+	if req.GetFrom() != nil {
+		arg.FromTime = sql.NullTime{Time: req.GetFrom().AsTime(), Valid: true}
+	}
+	if req.GetTo() != nil {
+		arg.ToTime = sql.NullTime{Time: req.GetTo().AsTime(), Valid: true}
+	}
+	if req.GetCurrency() != "" {
+		arg.Currency = sql.NullString{String: req.GetCurrency(), Valid: true}
+	}
+	if req.GetMemo() != "" {
+		arg.Memo = sql.NullString{String: sqlc.ContainsPattern(req.GetMemo()), Valid: true}
+	}
+	if req.GetCursor() != "" {
+		// Already checked by validateSearchTransfersRequest
+		after, _ := cursor.Decode(req.GetCursor())
+		arg.CursorCreatedAt = sql.NullTime{Time: after.CreatedAt, Valid: true}
+		arg.CursorID = sql.NullInt64{Int64: after.ID, Valid: true}
+	}
+
+	transfers, err := server.store.SearchTransfers(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to search transfers: %s", err)
+	}
+
+	rsp := &pb.SearchTransfersResponse{
+		Transfers: make([]*pb.Transfer, 0, len(transfers)),
+	}
+	if len(transfers) > int(req.GetPageSize()) {
+		transfers = transfers[:req.GetPageSize()]
+		last := transfers[len(transfers)-1]
+		rsp.NextCursor = cursor.Encode(cursor.Cursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	for _, transfer := range transfers {
+		rsp.Transfers = append(rsp.Transfers, convertTransfer(transfer))
+	}
+	return rsp, nil
+}
+
+func validateSearchTransfersRequest(req *pb.SearchTransfersRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.GetAccountId() != 0 {
+		if err := validate.ValidateID(req.GetAccountId()); err != nil {
+			violations = append(violations, fieldViolation("account_id", err))
+		}
+	}
+	if err := validate.ValidateDirection(req.GetDirection()); err != nil {
+		violations = append(violations, fieldViolation("direction", err))
+	}
+	if req.GetCounterpartyAccountId() != 0 {
+		if err := validate.ValidateID(req.GetCounterpartyAccountId()); err != nil {
+			violations = append(violations, fieldViolation("counterparty_account_id", err))
+		}
+	}
+	if err := validate.ValidateAmountRange(req.GetMinAmount(), req.GetMaxAmount()); err != nil {
+		violations = append(violations, fieldViolation("max_amount", err))
+	}
+	if req.GetFrom() != nil && req.GetTo() != nil {
+		if err := validate.ValidateTimeRange(req.GetFrom().AsTime(), req.GetTo().AsTime()); err != nil {
+			violations = append(violations, fieldViolation("to", err))
+		}
+	}
+	if req.GetCurrency() != "" {
+		if err := validate.ValidateCurrency(req.GetCurrency()); err != nil {
+			violations = append(violations, fieldViolation("currency", err))
+		}
+	}
+	if err := validate.ValidateMemo(req.GetMemo()); err != nil {
+		violations = append(violations, fieldViolation("memo", err))
+	}
+	if err := validate.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+	if req.GetCursor() != "" {
+		if _, err := cursor.Decode(req.GetCursor()); err != nil {
+			violations = append(violations, fieldViolation("cursor", err))
+		}
+	}
+	return violations
+}
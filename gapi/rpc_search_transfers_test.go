@@ -0,0 +1,194 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/October-9th/simple-bank/cursor"
+	mockdb "github.com/October-9th/simple-bank/database/mock"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/October-9th/simple-bank/validate"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSearchTransfersAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	account := randomAccount(owner)
+	pageSize := int32(5)
+
+	// One row more than the page, so there is a next page
+	createdAt := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	transfers := make([]sqlc.Transfer, pageSize+1)
+	for i := range transfers {
+		transfers[i] = sqlc.Transfer{
+			ID:            int64(100 - i),
+			FromAccountID: account.ID,
+			ToAccountID:   util.RandomInt(1, 1000),
+			Amount:        util.RandomMoney(),
+			Memo:          "rent 100%",
+			CreatedAt:     createdAt.Add(-time.Duration(i) * time.Minute),
+		}
+	}
+	last := transfers[pageSize-1]
+	nextCursor := cursor.Encode(cursor.Cursor{CreatedAt: last.CreatedAt, ID: last.ID})
+
+	testCases := []struct {
+		name          string
+		req           *pb.SearchTransfersRequest
+		buildContext  func(t *testing.T) context.Context
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, rsp *pb.SearchTransfersResponse, err error)
+	}{
+		{
+			name: "OK",
+			req: &pb.SearchTransfersRequest{
+				AccountId: account.ID,
+				Direction: validate.DirectionSent,
+				Currency:  account.Currency,
+				Memo:      "100%",
+				PageSize:  pageSize,
+			},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := sqlc.SearchTransfersParams{
+					Owner:      owner,
+					Direction:  sql.NullString{String: validate.DirectionSent, Valid: true},
+					AccountID:  sql.NullInt64{Int64: account.ID, Valid: true},
+					Currency:   sql.NullString{String: account.Currency, Valid: true},
+					Memo:       sql.NullString{String: `%100\%%`, Valid: true},
+					LimitCount: pageSize + 1,
+				}
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Eq(arg)).Times(1).Return(transfers, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.SearchTransfersResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, rsp.GetTransfers(), int(pageSize))
+				require.Equal(t, nextCursor, rsp.GetNextCursor())
+			},
+		},
+		{
+			name: "NextPage",
+			req:  &pb.SearchTransfersRequest{PageSize: pageSize, Cursor: nextCursor},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := sqlc.SearchTransfersParams{
+					Owner:           owner,
+					CursorCreatedAt: sql.NullTime{Time: last.CreatedAt, Valid: true},
+					CursorID:        sql.NullInt64{Int64: last.ID, Valid: true},
+					LimitCount:      pageSize + 1,
+				}
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Eq(arg)).Times(1).Return(transfers[pageSize:], nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.SearchTransfersResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, rsp.GetTransfers(), 1)
+				require.Empty(t, rsp.GetNextCursor())
+			},
+		},
+		{
+			name: "InternalError",
+			req:  &pb.SearchTransfersRequest{PageSize: pageSize},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.SearchTransfersResponse, err error) {
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+		{
+			name: "InvalidCursor",
+			req:  &pb.SearchTransfersRequest{PageSize: pageSize, Cursor: "invalid"},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.SearchTransfersResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "InvalidAmountRange",
+			req:  &pb.SearchTransfersRequest{PageSize: pageSize, MinAmount: 100, MaxAmount: 10},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.SearchTransfersResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "InvalidDirection",
+			req:  &pb.SearchTransfersRequest{PageSize: pageSize, Direction: "sideways"},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.SearchTransfersResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "InvalidPageSize",
+			req:  &pb.SearchTransfersRequest{PageSize: 20},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.SearchTransfersResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "NoAuthorization",
+			req:  &pb.SearchTransfersRequest{PageSize: pageSize},
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.SearchTransfersResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			rsp, err := server.SearchTransfers(tc.buildContext(t), tc.req)
+			tc.checkResponse(t, rsp, err)
+		})
+	}
+}
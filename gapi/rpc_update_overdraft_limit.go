@@ -0,0 +1,64 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/October-9th/simple-bank/authz"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UpdateOverdraftLimit lets bankers and admins set how far below zero an account may go.
+// Every change is recorded together with the previous limit, who made it and why
+func (server *Server) UpdateOverdraftLimit(ctx context.Context, req *pb.UpdateOverdraftLimitRequest) (*pb.UpdateOverdraftLimitResponse, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateUpdateOverdraftLimitRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	if _, err := server.getAuthorizedAccount(ctx, req.GetAccountId(), authPayload, authz.SetOverdraftLimit); err != nil {
+		return nil, err
+	}
+
+	result, err := server.store.UpdateOverdraftLimitTx(ctx, sqlc.UpdateOverdraftLimitTxParams{
+		AccountID:      req.GetAccountId(),
+		OverdraftLimit: req.GetOverdraftLimit(),
+		Reason:         req.GetReason(),
+		ChangedBy:      authPayload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, sqlc.ErrAccountClosed) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to update overdraft limit: %s", err)
+	}
+
+	rsp := &pb.UpdateOverdraftLimitResponse{
+		Change:  convertOverdraftLimitChange(result.Change),
+		Account: convertAccount(result.Account),
+	}
+	return rsp, nil
+}
+
+func validateUpdateOverdraftLimitRequest(req *pb.UpdateOverdraftLimitRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validate.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+	if err := validate.ValidateOverdraftLimit(req.GetOverdraftLimit()); err != nil {
+		violations = append(violations, fieldViolation("overdraft_limit", err))
+	}
+	if err := validate.ValidateString(req.GetReason(), 1, 200); err != nil {
+		violations = append(violations, fieldViolation("reason", err))
+	}
+	return violations
+}
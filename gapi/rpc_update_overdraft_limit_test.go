@@ -0,0 +1,157 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+
+	mockdb "github.com/October-9th/simple-bank/database/mock"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUpdateOverdraftLimitAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	account := randomAccount(owner)
+	limit := int64(500)
+	reason := "approved credit line"
+
+	updated := account
+	updated.OverdraftLimit = limit
+	result := sqlc.UpdateOverdraftLimitTxResult{
+		Account: updated,
+		Change: sqlc.OverdraftLimitChange{
+			ID:            1,
+			AccountID:     account.ID,
+			PreviousLimit: account.OverdraftLimit,
+			NewLimit:      limit,
+			Reason:        reason,
+			ChangedBy:     "banker",
+		},
+	}
+	newRequest := func() *pb.UpdateOverdraftLimitRequest {
+		return &pb.UpdateOverdraftLimitRequest{AccountId: account.ID, OverdraftLimit: limit, Reason: reason}
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.UpdateOverdraftLimitRequest
+		buildContext  func(t *testing.T) context.Context
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, rsp *pb.UpdateOverdraftLimitResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  newRequest(),
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "banker", util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := sqlc.UpdateOverdraftLimitTxParams{
+					AccountID:      account.ID,
+					OverdraftLimit: limit,
+					Reason:         reason,
+					ChangedBy:      "banker",
+				}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().UpdateOverdraftLimitTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.UpdateOverdraftLimitResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, limit, rsp.GetAccount().GetOverdraftLimit())
+				require.Equal(t, account.OverdraftLimit, rsp.GetChange().GetPreviousLimit())
+				require.Equal(t, "banker", rsp.GetChange().GetChangedBy())
+			},
+		},
+		{
+			name: "OwnerIsNotStaff",
+			req:  newRequest(),
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, owner, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().UpdateOverdraftLimitTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.UpdateOverdraftLimitResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "NegativeLimit",
+			req:  &pb.UpdateOverdraftLimitRequest{AccountId: account.ID, OverdraftLimit: -1, Reason: reason},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "admin", util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UpdateOverdraftLimitTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.UpdateOverdraftLimitResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "MissingReason",
+			req:  &pb.UpdateOverdraftLimitRequest{AccountId: account.ID, OverdraftLimit: limit},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "admin", util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UpdateOverdraftLimitTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.UpdateOverdraftLimitResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "AccountClosed",
+			req:  newRequest(),
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, "admin", util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().UpdateOverdraftLimitTx(gomock.Any(), gomock.Any()).Times(1).
+					Return(sqlc.UpdateOverdraftLimitTxResult{}, sqlc.ErrAccountClosed)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.UpdateOverdraftLimitResponse, err error) {
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		},
+		{
+			name: "NoAuthorization",
+			req:  newRequest(),
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateOverdraftLimitTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.UpdateOverdraftLimitResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			rsp, err := server.UpdateOverdraftLimit(tc.buildContext(t), tc.req)
+			tc.checkResponse(t, rsp, err)
+		})
+	}
+}
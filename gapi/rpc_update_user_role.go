@@ -0,0 +1,58 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/October-9th/simple-bank/authz"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UpdateUserRole lets an admin give a user another role. Tokens already issued keep the role they were created with,
+// the new one applies from the next login or renewal
+func (server *Server) UpdateUserRole(ctx context.Context, req *pb.UpdateUserRoleRequest) (*pb.UpdateUserRoleResponse, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateUpdateUserRoleRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	if err := authz.Authorize(authPayload, authz.AssignRole, req.GetUsername()); err != nil {
+		return nil, permissionDeniedError(err)
+	}
+
+	user, err := server.store.UpdateUserRole(ctx, sqlc.UpdateUserRoleParams{
+		Username: req.GetUsername(),
+		Role:     req.GetRole(),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "user doesn't exist")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to update user role: %s", err)
+	}
+
+	rsp := &pb.UpdateUserRoleResponse{
+		User: convertUser(user),
+	}
+	return rsp, nil
+}
+
+func validateUpdateUserRoleRequest(req *pb.UpdateUserRoleRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validate.ValidateUsername(req.GetUsername()); err != nil {
+		violations = append(violations, fieldViolation("username", err))
+	}
+	if err := validate.ValidateRole(req.GetRole()); err != nil {
+		violations = append(violations, fieldViolation("role", err))
+	}
+	return violations
+}
@@ -0,0 +1,128 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	mockdb "github.com/October-9th/simple-bank/database/mock"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUpdateUserRoleAPI(t *testing.T) {
+	username := util.RandomOwner()
+	banker := sqlc.User{Username: username, Role: util.BankerRole}
+
+	testCases := []struct {
+		name          string
+		req           *pb.UpdateUserRoleRequest
+		buildContext  func(t *testing.T) context.Context
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, rsp *pb.UpdateUserRoleResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.UpdateUserRoleRequest{Username: username, Role: util.BankerRole},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, util.RandomOwner(), util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := sqlc.UpdateUserRoleParams{Username: username, Role: util.BankerRole}
+				store.EXPECT().UpdateUserRole(gomock.Any(), gomock.Eq(arg)).Times(1).Return(banker, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.UpdateUserRoleResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, username, rsp.GetUser().GetUsername())
+				require.Equal(t, util.BankerRole, rsp.GetUser().GetRole())
+			},
+		},
+		{
+			// Staff can't promote anyone, themselves included
+			name: "Banker",
+			req:  &pb.UpdateUserRoleRequest{Username: username, Role: util.AdminRole},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, username, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserRole(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.UpdateUserRoleResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "UnsupportedRole",
+			req:  &pb.UpdateUserRoleRequest{Username: username, Role: "superuser"},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, util.RandomOwner(), util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserRole(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.UpdateUserRoleResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "NotFound",
+			req:  &pb.UpdateUserRoleRequest{Username: username, Role: util.BankerRole},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, util.RandomOwner(), util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserRole(gomock.Any(), gomock.Any()).Times(1).Return(sqlc.User{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.UpdateUserRoleResponse, err error) {
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name: "InternalError",
+			req:  &pb.UpdateUserRoleRequest{Username: username, Role: util.BankerRole},
+			buildContext: func(t *testing.T) context.Context {
+				return newContextWithPayload(t, util.RandomOwner(), util.AdminRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserRole(gomock.Any(), gomock.Any()).Times(1).Return(sqlc.User{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.UpdateUserRoleResponse, err error) {
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+		{
+			name: "NoAuthorization",
+			req:  &pb.UpdateUserRoleRequest{Username: username, Role: util.BankerRole},
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserRole(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.UpdateUserRoleResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			rsp, err := server.UpdateUserRole(tc.buildContext(t), tc.req)
+			tc.checkResponse(t, rsp, err)
+		})
+	}
+}
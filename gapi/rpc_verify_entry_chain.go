@@ -0,0 +1,55 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/October-9th/simple-bank/authz"
+	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) VerifyEntryChain(ctx context.Context, req *pb.VerifyEntryChainRequest) (*pb.VerifyEntryChainResponse, error) {
+	authPayload, err := payloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if err := authz.Authorize(authPayload, authz.Reconcile, ""); err != nil {
+		return nil, permissionDeniedError(err)
+	}
+
+	violations := validateVerifyEntryChainRequest(req)
+	if violations != nil {
+		return nil, InvalidArgumentError(violations)
+	}
+
+	result, err := server.store.VerifyEntryChain(ctx, req.GetAccountId())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "account doesn't exist")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to verify entry chain: %s", err)
+	}
+
+	rsp := &pb.VerifyEntryChainResponse{
+		AccountId:       result.AccountID,
+		EntriesChecked:  result.EntriesChecked,
+		UnsealedEntries: result.UnsealedEntries,
+		Intact:          result.Intact,
+		BrokenEntryId:   result.BrokenEntryID,
+		Reason:          result.Reason,
+		Detail:          result.Detail,
+	}
+	return rsp, nil
+}
+
+func validateVerifyEntryChainRequest(req *pb.VerifyEntryChainRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validate.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+	return violations
+}
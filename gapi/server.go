@@ -21,7 +21,7 @@ type Server struct {
 // NewServer create a new GRPC server to server gRPC request and setup routing.
 func NewServer(config util.Config, store sqlc.Store) (*Server, error) {
 
-	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmectricKey)
+	tokenMaker, err := token.NewMaker(config)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't create token maker: %v", err)
 	}
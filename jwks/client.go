@@ -0,0 +1,205 @@
+package jwks
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/October-9th/simple-bank/token"
+)
+
+const (
+	// maxCacheAge bounds the max-age announced by the server
+	maxCacheAge = 24 * time.Hour
+	// minRefreshInterval limits how often an unknown key id triggers a fetch, so tokens with made up
+	// key ids can't make the client hammer the server
+	minRefreshInterval = 30 * time.Second
+	// maxSetSize bounds the response read from the server
+	maxSetSize = 1 << 20
+)
+
+// Client fetches the key set published by the server and caches it as long as the server allows.
+// A key id missing from the cache triggers a fetch, so keys added by a rotation are picked up right away,
+// but the server is never asked more than once per minRefreshInterval.
+// It implements token.KeySource and is safe for concurrent use: the cache isn't locked during a fetch,
+// and calls needing the set while it is fetched wait for that fetch rather than starting their own
+type Client struct {
+	url        string
+	httpClient *http.Client
+	now        func() time.Time
+
+	mu          sync.Mutex
+	keys        map[string]ed25519.PublicKey
+	etag        string
+	expiresAt   time.Time
+	lastFetchAt time.Time
+	// fetching is closed when the fetch in progress is done, it is nil when there is none
+	fetching chan struct{}
+	// fetchErr is the error of the last fetch
+	fetchErr error
+}
+
+// fetchResult is a key set fetched from the server
+type fetchResult struct {
+	// keys are nil when the server answered the cached set wasn't modified
+	keys   map[string]ed25519.PublicKey
+	etag   string
+	maxAge time.Duration
+}
+
+// NewClient returns a client of the key set at url, e.g. https://bank.example.com/.well-known/jwks.json.
+// httpClient may be nil to use a client with a 10 seconds timeout
+func NewClient(url string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Client{
+		url:        url,
+		httpClient: httpClient,
+		now:        time.Now,
+	}
+}
+
+// NewVerifier returns a token maker verifying tokens with the keys fetched by client. It can't create tokens.
+// options should name the issuer of the server and the audience of the service, the ring signs tokens for every service
+func NewVerifier(client *Client, options token.PasetoOptions) token.Maker {
+	return token.NewPasetoPublicVerifier(client, options)
+}
+
+// NewJWTVerifier is NewVerifier for a server issuing JWTs
+func NewJWTVerifier(client *Client, options token.JWTOptions) token.Maker {
+	return token.NewEdDSAJWTVerifier(client, options)
+}
+
+// PublicKey returns the key of keyID, fetching the set when the cache has expired or doesn't have the key.
+// When the server can't be reached the cached keys are used until the server answers again
+func (client *Client) PublicKey(keyID string) (ed25519.PublicKey, error) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	now := client.now()
+	publicKey, found := client.keys[keyID]
+	if found && now.Before(client.expiresAt) {
+		return publicKey, nil
+	}
+	// Stale or missing keys are fetched at most once per minRefreshInterval
+	if client.fetching != nil || client.lastFetchAt.IsZero() || now.Sub(client.lastFetchAt) >= minRefreshInterval {
+		if err := client.refresh(now); err != nil && client.keys == nil {
+			return nil, err
+		}
+	}
+	if client.keys == nil {
+		return nil, fmt.Errorf("key set isn't available yet")
+	}
+
+	publicKey, found = client.keys[keyID]
+	if !found {
+		return nil, token.ErrUnknownKey
+	}
+	return publicKey, nil
+}
+
+// refresh fetches the set and updates the cache, or waits for the fetch already in progress.
+// It is called with mu held, which is released while the server is asked
+func (client *Client) refresh(now time.Time) error {
+	if done := client.fetching; done != nil {
+		client.mu.Unlock()
+		<-done
+		client.mu.Lock()
+		return client.fetchErr
+	}
+
+	done := make(chan struct{})
+	client.fetching = done
+	client.lastFetchAt = now
+	etag := ""
+	if client.keys != nil {
+		etag = client.etag
+	}
+
+	client.mu.Unlock()
+	result, err := client.fetch(etag)
+	client.mu.Lock()
+
+	client.fetching = nil
+	client.fetchErr = err
+	close(done)
+	if err != nil {
+		return err
+	}
+	if result.keys != nil {
+		client.keys = result.keys
+		client.etag = result.etag
+	}
+	client.expiresAt = now.Add(result.maxAge)
+	return nil
+}
+
+// fetch asks the server for the set, revalidating the cached one when etag isn't empty
+func (client *Client) fetch(etag string) (*fetchResult, error) {
+	request, err := http.NewRequest(http.MethodGet, client.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if etag != "" {
+		request.Header.Set("If-None-Match", etag)
+	}
+
+	response, err := client.httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't fetch key set: %w", err)
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusNotModified:
+		return &fetchResult{maxAge: cacheMaxAge(response.Header)}, nil
+	case http.StatusOK:
+	default:
+		return nil, fmt.Errorf("couldn't fetch key set: %s", response.Status)
+	}
+
+	var set Set
+	if err := json.NewDecoder(io.LimitReader(response.Body, maxSetSize)).Decode(&set); err != nil {
+		return nil, fmt.Errorf("couldn't decode key set: %w", err)
+	}
+	keys, err := set.PublicKeys()
+	if err != nil {
+		return nil, err
+	}
+	return &fetchResult{
+		keys:   keys,
+		etag:   response.Header.Get("ETag"),
+		maxAge: cacheMaxAge(response.Header),
+	}, nil
+}
+
+// cacheMaxAge returns the max-age of the Cache-Control header, DefaultMaxAge when there is none
+func cacheMaxAge(header http.Header) time.Duration {
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.TrimSpace(directive)
+		if directive == "no-cache" || directive == "no-store" {
+			return 0
+		}
+		value, ok := strings.CutPrefix(directive, "max-age=")
+		if !ok {
+			continue
+		}
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds < 0 {
+			return DefaultMaxAge
+		}
+		maxAge := time.Duration(seconds) * time.Second
+		if maxAge > maxCacheAge {
+			return maxCacheAge
+		}
+		return maxAge
+	}
+	return DefaultMaxAge
+}
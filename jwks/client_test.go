@@ -0,0 +1,183 @@
+package jwks
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/October-9th/simple-bank/token"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+// testServer serves the keys of ring and counts the requests it answers
+type testServer struct {
+	*httptest.Server
+	requests atomic.Int32
+	down     atomic.Bool
+}
+
+func newTestServer(t *testing.T, ring *token.KeyRing, maxAge time.Duration) *testServer {
+	server := &testServer{}
+	handler := Handler(ring, maxAge)
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.requests.Add(1)
+		if server.down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClientVerifiesTokens(t *testing.T) {
+	ring := newTestKeyRing(t, "key-1")
+	maker, err := token.NewPasetoPublicMaker(ring, token.PasetoOptions{})
+	require.NoError(t, err)
+	server := newTestServer(t, ring, time.Minute)
+
+	client := NewClient(server.URL+Path, nil)
+	verifier := NewVerifier(client, token.PasetoOptions{})
+
+	username := util.RandomOwner()
+	accessToken, _, err := maker.CreateToken(username, util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := verifier.VerifyToken(accessToken, token.TokenTypeAccess)
+	require.NoError(t, err)
+	require.Equal(t, username, payload.Username)
+
+	// Cached until max-age
+	_, err = verifier.VerifyToken(accessToken, token.TokenTypeAccess)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, server.requests.Load())
+
+	_, _, err = verifier.CreateToken(username, util.DepositorRole, time.Minute)
+	require.ErrorIs(t, err, token.ErrNoSigningKey)
+}
+
+func TestClientCache(t *testing.T) {
+	ring := newTestKeyRing(t, "key-1")
+	server := newTestServer(t, ring, time.Minute)
+
+	now := time.Now()
+	client := NewClient(server.URL+Path, nil)
+	client.now = func() time.Time { return now }
+
+	_, err := client.PublicKey("key-1")
+	require.NoError(t, err)
+	require.EqualValues(t, 1, server.requests.Load())
+
+	// An unknown key id fetches the set again, but not more than once per minRefreshInterval
+	_, err = client.PublicKey("key-9")
+	require.ErrorIs(t, err, token.ErrUnknownKey)
+	require.EqualValues(t, 1, server.requests.Load())
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	require.NoError(t, ring.SetSigningKey("key-2", privateKey))
+
+	now = now.Add(minRefreshInterval)
+	publicKey, err := client.PublicKey("key-2")
+	require.NoError(t, err)
+	require.Equal(t, privateKey.Public(), publicKey)
+	require.EqualValues(t, 2, server.requests.Load())
+
+	// The stale set is revalidated, and still used while the server is down
+	now = now.Add(time.Minute)
+	_, err = client.PublicKey("key-1")
+	require.NoError(t, err)
+	require.EqualValues(t, 3, server.requests.Load())
+
+	server.down.Store(true)
+	now = now.Add(time.Minute)
+	_, err = client.PublicKey("key-1")
+	require.NoError(t, err)
+	require.EqualValues(t, 4, server.requests.Load())
+}
+
+func TestClientConcurrentFetch(t *testing.T) {
+	ring := newTestKeyRing(t, "key-1")
+	handler := Handler(ring, time.Minute)
+
+	// Every request waits to be released, like a slow server
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	now := time.Now()
+	client := NewClient(server.URL+Path, nil)
+	client.now = func() time.Time { return now }
+
+	// Calls needing the set while it is fetched share the fetch
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.PublicKey("key-1")
+			errs <- err
+		}()
+	}
+	require.Eventually(t, func() bool { return requests.Load() == 1 }, time.Second, time.Millisecond)
+	release <- struct{}{}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	require.EqualValues(t, 1, requests.Load())
+
+	// Cached keys are still served while an unknown key id is fetched
+	now = now.Add(minRefreshInterval)
+	fetched := make(chan error)
+	go func() {
+		_, err := client.PublicKey("key-9")
+		fetched <- err
+	}()
+	require.Eventually(t, func() bool { return requests.Load() == 2 }, time.Second, time.Millisecond)
+
+	_, err := client.PublicKey("key-1")
+	require.NoError(t, err)
+
+	release <- struct{}{}
+	require.ErrorIs(t, <-fetched, token.ErrUnknownKey)
+}
+
+func TestClientServerDown(t *testing.T) {
+	ring := newTestKeyRing(t, "key-1")
+	server := newTestServer(t, ring, time.Minute)
+	server.down.Store(true)
+
+	client := NewClient(server.URL+Path, nil)
+	_, err := client.PublicKey("key-1")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, token.ErrUnknownKey)
+}
+
+func TestCacheMaxAge(t *testing.T) {
+	for value, maxAge := range map[string]time.Duration{
+		"":                        DefaultMaxAge,
+		"public, max-age=60":      time.Minute,
+		"max-age=abc":             DefaultMaxAge,
+		"no-store":                0,
+		"public, max-age=9999999": maxCacheAge,
+	} {
+		header := http.Header{}
+		header.Set("Cache-Control", value)
+		require.Equal(t, maxAge, cacheMaxAge(header), value)
+	}
+}
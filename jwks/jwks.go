@@ -0,0 +1,114 @@
+// Package jwks publishes the public keys tokens are signed with as a JSON Web Key Set (RFC 7517),
+// and fetches them back so other services verify tokens without holding any secret
+package jwks
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/October-9th/simple-bank/token"
+)
+
+// Path is where the HTTP gateway serves the key set
+const Path = "/.well-known/jwks.json"
+
+// DefaultMaxAge is how long clients may cache the key set. A key is published before it signs anything,
+// so the rotation is seen by every client within this delay
+const DefaultMaxAge = 5 * time.Minute
+
+// Ed25519 keys are published as octet key pairs (RFC 8037)
+const (
+	keyTypeOKP   = "OKP"
+	curveEd25519 = "Ed25519"
+	useSignature = "sig"
+)
+
+// ErrInvalidKey is returned for a key of the set that isn't an Ed25519 signing key
+var ErrInvalidKey = errors.New("invalid key")
+
+// Key is a public key of the set
+type Key struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"` // the public key, base64url encoded
+	KeyID     string `json:"kid"`
+	Use       string `json:"use,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+}
+
+// Set is a JSON Web Key Set
+type Set struct {
+	Keys []Key `json:"keys"`
+}
+
+// NewSet returns the set of keys, sorted by key id so the same keys always encode the same way.
+// The keys have no alg: they sign PASETO v4.public tokens, which no JOSE algorithm name stands for
+func NewSet(keys map[string]ed25519.PublicKey) Set {
+	set := Set{Keys: make([]Key, 0, len(keys))}
+	for keyID, publicKey := range keys {
+		set.Keys = append(set.Keys, Key{
+			KeyType: keyTypeOKP,
+			Curve:   curveEd25519,
+			X:       base64.RawURLEncoding.EncodeToString(publicKey),
+			KeyID:   keyID,
+			Use:     useSignature,
+		})
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].KeyID < set.Keys[j].KeyID })
+	return set
+}
+
+// PublicKeys returns the Ed25519 public keys of the set by key id. Keys of other types are skipped,
+// a malformed Ed25519 key fails the whole set
+func (set Set) PublicKeys() (map[string]ed25519.PublicKey, error) {
+	keys := make(map[string]ed25519.PublicKey, len(set.Keys))
+	for _, key := range set.Keys {
+		if key.KeyType != keyTypeOKP || key.Curve != curveEd25519 || (key.Use != "" && key.Use != useSignature) {
+			continue
+		}
+		publicKey, err := base64.RawURLEncoding.DecodeString(key.X)
+		if err != nil || len(publicKey) != ed25519.PublicKeySize || key.KeyID == "" {
+			return nil, fmt.Errorf("%w %q", ErrInvalidKey, key.KeyID)
+		}
+		keys[key.KeyID] = publicKey
+	}
+	return keys, nil
+}
+
+// Handler serves the public keys of ring, the active one and those still accepted during a rotation.
+// Responses may be cached for maxAge and carry an ETag, so clients revalidate without downloading the set again
+func Handler(ring *token.KeyRing, maxAge time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		// The ring may rotate at any time, so the set is encoded on every request
+		body, err := json.Marshal(NewSet(ring.PublicKeys()))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		sum := sha256.Sum256(body)
+		etag := fmt.Sprintf(`"%s"`, base64.RawURLEncoding.EncodeToString(sum[:16]))
+
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	})
+}
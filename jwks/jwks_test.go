@@ -0,0 +1,92 @@
+package jwks
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/October-9th/simple-bank/token"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKeyRing(t *testing.T, keyID string) *token.KeyRing {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ring := token.NewKeyRing()
+	require.NoError(t, ring.SetSigningKey(keyID, privateKey))
+	return ring
+}
+
+func TestSet(t *testing.T) {
+	ring := newTestKeyRing(t, "key-2")
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	require.NoError(t, ring.AddVerificationKey("key-1", publicKey))
+
+	set := NewSet(ring.PublicKeys())
+	require.Len(t, set.Keys, 2)
+	require.Equal(t, "key-1", set.Keys[0].KeyID)
+	require.Equal(t, "OKP", set.Keys[0].KeyType)
+	require.Equal(t, "Ed25519", set.Keys[0].Curve)
+	// The keys sign PASETO tokens, they aren't advertised for any JOSE algorithm
+	require.Empty(t, set.Keys[0].Algorithm)
+
+	keys, err := set.PublicKeys()
+	require.NoError(t, err)
+	require.Equal(t, ring.PublicKeys(), keys)
+
+	// Keys of other types are skipped, broken Ed25519 keys fail the set
+	set.Keys = append(set.Keys, Key{KeyType: "RSA", KeyID: "rsa-1"})
+	keys, err = set.PublicKeys()
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+
+	set.Keys = append(set.Keys, Key{KeyType: "OKP", Curve: "Ed25519", KeyID: "key-3", X: "AAAA"})
+	_, err = set.PublicKeys()
+	require.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestHandler(t *testing.T) {
+	ring := newTestKeyRing(t, "key-1")
+	handler := Handler(ring, 5*time.Minute)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, Path, nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "public, max-age=300", recorder.Header().Get("Cache-Control"))
+	require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+	var set Set
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &set))
+	require.Len(t, set.Keys, 1)
+	require.Equal(t, "key-1", set.Keys[0].KeyID)
+
+	etag := recorder.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	// The same set is only revalidated
+	request := httptest.NewRequest(http.MethodGet, Path, nil)
+	request.Header.Set("If-None-Match", etag)
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusNotModified, recorder.Code)
+	require.Empty(t, recorder.Body.Bytes())
+
+	// A rotation changes the set
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	require.NoError(t, ring.SetSigningKey("key-2", privateKey))
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.NotEqual(t, etag, recorder.Header().Get("ETag"))
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, Path, nil))
+	require.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
@@ -3,21 +3,31 @@ package main
 import (
 	"context"
 	"database/sql"
+	"encoding/hex"
+	"flag"
 	"log"
 	"net"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/October-9th/simple-bank/api"
 	"github.com/October-9th/simple-bank/database/sqlc"
 	_ "github.com/October-9th/simple-bank/doc/statik"
+	"github.com/October-9th/simple-bank/fx"
 	"github.com/October-9th/simple-bank/gapi"
+	"github.com/October-9th/simple-bank/jwks"
 	"github.com/October-9th/simple-bank/pb"
+	"github.com/October-9th/simple-bank/schedule"
+	"github.com/October-9th/simple-bank/snapshot"
+	"github.com/October-9th/simple-bank/token"
 	"github.com/October-9th/simple-bank/util"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	_ "github.com/lib/pq"
 	"github.com/rakyll/statik/fs"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/reflection"
 )
 
@@ -26,25 +36,140 @@ func main() {
 	if err != nil {
 		log.Fatal("Couldn't load config file: ", err)
 	}
+	if err := config.Validate(); err != nil {
+		log.Fatal("Invalid config: ", err)
+	}
 	conn, err := sql.Open(config.DBDriver, config.DBSource)
 	if err != nil {
 		log.Fatal("Couldn't connect to database: ", err)
 	}
 
-	store := sqlc.NewStore(conn)
+	ledgerHashKey, err := hex.DecodeString(config.LedgerHashKey)
+	if err != nil {
+		log.Fatal("Couldn't decode ledger hash key: ", err)
+	}
+	store := sqlc.NewStore(conn, sqlc.WithEntryHashKey(ledgerHashKey))
+
+	if err := sqlc.LoadCurrencies(context.Background(), store); err != nil {
+		log.Fatal("Couldn't load currencies: ", err)
+	}
+
+	// go-bank snapshot --date YYYY-MM-DD runs the balance snapshot job once and exits,
+	// go-bank reconcile checks the ledger once and exits with status 1 when it doesn't balance
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "snapshot":
+			runSnapshotCommand(os.Args[2:], store)
+			return
+		case "reconcile":
+			runReconcileCommand(store)
+			return
+		}
+	}
+
+	if config.ExchangeRatesFile != "" {
+		loadExchangeRates(config.ExchangeRatesFile, store)
+	}
+
+	if config.SnapshotDelay > 0 {
+		go snapshot.Schedule(context.Background(), store, config.SnapshotDelay)
+	}
+	if config.ReconciliationDelay > 0 {
+		go scheduleReconciliation(context.Background(), store, config.ReconciliationDelay)
+	}
+
 	// Run http gateway in another goroutine
-	go runGatewayServer(config, store)
+	go runGatewayServer(config)
 
 	runGrpcServer(config, store)
 
 }
+
+// runSnapshotCommand snapshots the balances of one day, yesterday unless --date is given
+func runSnapshotCommand(args []string, store sqlc.Store) {
+	flags := flag.NewFlagSet("snapshot", flag.ExitOnError)
+	yesterday := time.Now().UTC().AddDate(0, 0, -1).Format(time.DateOnly)
+	dateFlag := flags.String("date", yesterday, "UTC day to snapshot, as YYYY-MM-DD")
+	flags.Parse(args)
+
+	date, err := time.Parse(time.DateOnly, *dateFlag)
+	if err != nil {
+		log.Fatal("Invalid snapshot date: ", err)
+	}
+
+	written, err := snapshot.Run(context.Background(), store, date)
+	if err != nil {
+		log.Fatal("Couldn't snapshot balances: ", err)
+	}
+	log.Printf("Snapshotted %d balances of %s", written, date.Format(time.DateOnly))
+}
+
+// nightlyReconciliation is the started_by of the runs made by the server itself
+const nightlyReconciliation = "nightly reconciliation"
+
+// reconcile checks the ledger and logs every discrepancy found
+func reconcile(ctx context.Context, store sqlc.Store, startedBy string) (sqlc.ReconcileTxResult, error) {
+	result, err := store.ReconcileTx(ctx, startedBy)
+	if err != nil {
+		return result, err
+	}
+	for _, discrepancy := range result.Discrepancies {
+		log.Printf("Discrepancy %s: %s", discrepancy.Kind, discrepancy.Detail)
+	}
+	log.Printf("Reconciliation %d checked %d accounts, %d transfers and %d entries, found %d discrepancies",
+		result.Run.ID, result.Run.AccountsChecked, result.Run.TransfersChecked, result.Run.EntriesChecked, result.Run.DiscrepancyCount)
+	return result, nil
+}
+
+// runReconcileCommand checks the ledger once, the exit status tells whether it balances
+func runReconcileCommand(store sqlc.Store) {
+	result, err := reconcile(context.Background(), store, "cli")
+	if err != nil {
+		log.Fatal("Couldn't reconcile ledger: ", err)
+	}
+	if result.Run.DiscrepancyCount > 0 {
+		os.Exit(1)
+	}
+}
+
+// scheduleReconciliation reconciles the ledger every night. A restart doesn't trigger an extra run
+// when the ledger was already reconciled since the last scheduled time
+func scheduleReconciliation(ctx context.Context, store sqlc.Store, delay time.Duration) {
+	schedule.Daily(ctx, "reconciliation", delay, func(ctx context.Context, now time.Time) error {
+		lastScheduled := schedule.NextRun(now, delay).AddDate(0, 0, -1)
+		runs, err := store.ListReconciliationRuns(ctx, sqlc.ListReconciliationRunsParams{Limit: 1})
+		if err != nil {
+			return err
+		}
+		if len(runs) > 0 && !runs[0].StartedAt.Before(lastScheduled) {
+			return nil
+		}
+		_, err = reconcile(ctx, store, nightlyReconciliation)
+		return err
+	})
+}
+
+// loadExchangeRates stores the rates of a local CSV file, so cross-currency transfers work without any rate provider
+func loadExchangeRates(path string, store sqlc.Store) {
+	rates, err := fx.ReadCSVFile(path)
+	if err != nil {
+		log.Fatal("Couldn't read exchange rates: ", err)
+	}
+	if _, err := store.LoadExchangeRatesTx(context.Background(), rates); err != nil {
+		log.Fatal("Couldn't load exchange rates: ", err)
+	}
+	log.Printf("Loaded %d exchange rates from %s", len(rates), path)
+}
 func runGrpcServer(config util.Config, store sqlc.Store) {
 	server, err := gapi.NewServer(config, store)
 	if err != nil {
 		log.Fatal("Couldn't create server", err)
 	}
 
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(server.UnaryAuthInterceptor()),
+		grpc.StreamInterceptor(server.StreamAuthInterceptor()),
+	)
 	pb.RegisterGoBankServer(grpcServer, server)
 	// Register the reflection for the gprc server for gRPC client to explore what RPCs are available on the server
 	// and how to call them
@@ -60,18 +185,17 @@ func runGrpcServer(config util.Config, store sqlc.Store) {
 		log.Fatal("Couldn't start server: ", err)
 	}
 }
-func runGatewayServer(config util.Config, store sqlc.Store) {
-	server, err := gapi.NewServer(config, store)
-	if err != nil {
-		log.Fatal("Couldn't create server: ", err)
-	}
-
+func runGatewayServer(config util.Config) {
 	grpcMux := runtime.NewServeMux()
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	err = pb.RegisterGoBankHandlerServer(ctx, grpcMux, server)
+	// Forward requests to the gRPC server instead of calling the handlers in process,
+	// so they go through the same interceptors as native gRPC calls
+	// The marker tells the server that the client address in x-forwarded-for was written by the gateway
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials()), gapi.GatewayDialOption()}
+	err := pb.RegisterGoBankHandlerFromEndpoint(ctx, grpcMux, config.GRPCServerAddress, opts)
 	if err != nil {
 		log.Fatal("Couldn't register handler server: ", err)
 	}
@@ -81,6 +205,15 @@ func runGatewayServer(config util.Config, store sqlc.Store) {
 	// Convert those requests into gRPC format, reroute to grpc mux
 	mux.Handle("/", grpcMux)
 
+	// Publish the public keys of the tokens, so other services verify them on their own
+	if config.TokenPrivateKey != "" {
+		ring, err := token.ParseKeyRing(config.TokenKeyID, config.TokenPrivateKey, config.TokenVerificationKeys)
+		if err != nil {
+			log.Fatal("Couldn't load token keys: ", err)
+		}
+		mux.Handle(jwks.Path, jwks.Handler(ring, jwks.DefaultMaxAge))
+	}
+
 	// Define new file server
 	statikFs, err := fs.New()
 	if err != nil {
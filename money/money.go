@@ -0,0 +1,175 @@
+// Package money represents exact amounts of a currency in minor units,
+// with overflow-checked arithmetic and decimal parsing and formatting that follow the currency's exponent
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/October-9th/simple-bank/util"
+)
+
+var (
+	// ErrOverflow is returned when the result of an operation doesn't fit in an int64 of minor units
+	ErrOverflow = errors.New("amount overflows")
+	// ErrCurrencyMismatch is returned when combining amounts of different currencies
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	// ErrUnknownCurrency is returned for a currency missing from the registry
+	ErrUnknownCurrency = errors.New("unknown currency")
+	// ErrInvalidAmount is returned when a decimal string can't be parsed as an amount of the currency
+	ErrInvalidAmount = errors.New("invalid amount")
+)
+
+var isDecimal = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`).MatchString
+
+// Amount is an amount of Currency counted in its minor units, 1234 USD minor units are 12.34 USD
+type Amount struct {
+	Minor    int64
+	Currency string
+}
+
+// New returns an amount of minor units of currency, which has to be known by the registry.
+// The smallest int64 is rejected so that every amount can be negated
+func New(minor int64, currency string) (Amount, error) {
+	if _, ok := util.LookupCurrency(currency); !ok {
+		return Amount{}, fmt.Errorf("%w %q", ErrUnknownCurrency, currency)
+	}
+	if minor == math.MinInt64 {
+		return Amount{}, ErrOverflow
+	}
+	return Amount{Minor: minor, Currency: currency}, nil
+}
+
+// Parse reads a decimal string such as "12.34" as an amount of currency.
+// It fails rather than rounds when value has more decimal places than the currency
+func Parse(value string, currency string) (Amount, error) {
+	registered, ok := util.LookupCurrency(currency)
+	if !ok {
+		return Amount{}, fmt.Errorf("%w %q", ErrUnknownCurrency, currency)
+	}
+	if !isDecimal(value) {
+		return Amount{}, fmt.Errorf("%w: %q is not a decimal number", ErrInvalidAmount, value)
+	}
+
+	whole, fraction, _ := strings.Cut(value, ".")
+	if len(fraction) > int(registered.MinorUnit) {
+		return Amount{}, fmt.Errorf("%w: %s has at most %d decimal places", ErrInvalidAmount, currency, registered.MinorUnit)
+	}
+	fraction += strings.Repeat("0", int(registered.MinorUnit)-len(fraction))
+
+	minor, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return Amount{}, fmt.Errorf("%w: %q", ErrOverflow, value)
+	}
+	return Amount{Minor: minor, Currency: currency}, nil
+}
+
+// Add returns a + b, both amounts must have the same currency
+func (a Amount) Add(b Amount) (Amount, error) {
+	if a.Currency != b.Currency {
+		return Amount{}, fmt.Errorf("%w: %s vs %s", ErrCurrencyMismatch, a.Currency, b.Currency)
+	}
+	minor, err := AddMinor(a.Minor, b.Minor)
+	if err != nil {
+		return Amount{}, err
+	}
+	return Amount{Minor: minor, Currency: a.Currency}, nil
+}
+
+// Sub returns a - b, both amounts must have the same currency
+func (a Amount) Sub(b Amount) (Amount, error) {
+	neg, err := b.Neg()
+	if err != nil {
+		return Amount{}, err
+	}
+	return a.Add(neg)
+}
+
+// Neg returns -a
+func (a Amount) Neg() (Amount, error) {
+	if a.Minor == math.MinInt64 {
+		return Amount{}, ErrOverflow
+	}
+	return Amount{Minor: -a.Minor, Currency: a.Currency}, nil
+}
+
+// IsZero reports whether a is zero
+func (a Amount) IsZero() bool {
+	return a.Minor == 0
+}
+
+// IsPositive reports whether a is greater than zero
+func (a Amount) IsPositive() bool {
+	return a.Minor > 0
+}
+
+// IsNegative reports whether a is less than zero
+func (a Amount) IsNegative() bool {
+	return a.Minor < 0
+}
+
+// AddMinor returns a + b, failing instead of wrapping around when the sum doesn't fit in an int64
+func AddMinor(a, b int64) (int64, error) {
+	if (b > 0 && a > math.MaxInt64-b) || (b < 0 && a < math.MinInt64-b) {
+		return 0, ErrOverflow
+	}
+	return a + b, nil
+}
+
+// Decimal formats a with as many decimal places as its currency has, for example "12.34" for USD,
+// "1234" for JPY or "1.234" for KWD. Amounts of a currency missing from the registry are printed in minor units
+func (a Amount) Decimal() string {
+	sign := ""
+	digits := strconv.FormatInt(a.Minor, 10)
+	if a.Minor < 0 {
+		sign, digits = "-", digits[1:]
+	}
+
+	registered, ok := util.LookupCurrency(a.Currency)
+	if !ok || registered.MinorUnit == 0 {
+		return sign + digits
+	}
+
+	width := int(registered.MinorUnit) + 1
+	if len(digits) < width {
+		digits = strings.Repeat("0", width-len(digits)) + digits
+	}
+	split := len(digits) - int(registered.MinorUnit)
+	return sign + digits[:split] + "." + digits[split:]
+}
+
+// String formats a as a decimal followed by its currency, for example "12.34 USD"
+func (a Amount) String() string {
+	return a.Decimal() + " " + a.Currency
+}
+
+// jsonAmount is the JSON form of an Amount. The amount is a decimal string,
+// so it survives JSON decoders that read numbers as floats
+type jsonAmount struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// MarshalJSON encodes a as {"amount": "12.34", "currency": "USD"}
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonAmount{Amount: a.Decimal(), Currency: a.Currency})
+}
+
+// UnmarshalJSON decodes the form written by MarshalJSON
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	var decoded jsonAmount
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	amount, err := Parse(decoded.Amount, decoded.Currency)
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
@@ -0,0 +1,148 @@
+package money
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/October-9th/simple-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func mustNew(t *testing.T, minor int64, currency string) Amount {
+	amount, err := New(minor, currency)
+	require.NoError(t, err)
+	return amount
+}
+
+func TestNew(t *testing.T) {
+	amount, err := New(1234, util.USD)
+	require.NoError(t, err)
+	require.Equal(t, Amount{Minor: 1234, Currency: util.USD}, amount)
+
+	_, err = New(1234, "XXX")
+	require.ErrorIs(t, err, ErrUnknownCurrency)
+
+	_, err = New(math.MinInt64, util.USD)
+	require.ErrorIs(t, err, ErrOverflow)
+}
+
+func TestAddSub(t *testing.T) {
+	a := mustNew(t, 1000, util.USD)
+	b := mustNew(t, 250, util.USD)
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	require.Equal(t, int64(1250), sum.Minor)
+
+	diff, err := b.Sub(a)
+	require.NoError(t, err)
+	require.Equal(t, int64(-750), diff.Minor)
+	require.True(t, diff.IsNegative())
+
+	_, err = a.Add(mustNew(t, 1, util.EUR))
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = mustNew(t, math.MaxInt64, util.USD).Add(mustNew(t, 1, util.USD))
+	require.ErrorIs(t, err, ErrOverflow)
+
+	_, err = Amount{Minor: math.MinInt64, Currency: util.USD}.Sub(mustNew(t, 1, util.USD))
+	require.ErrorIs(t, err, ErrOverflow)
+
+	_, err = mustNew(t, 0, util.USD).Sub(Amount{Minor: math.MinInt64, Currency: util.USD})
+	require.ErrorIs(t, err, ErrOverflow)
+}
+
+func TestAddMinor(t *testing.T) {
+	sum, err := AddMinor(math.MaxInt64-1, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(math.MaxInt64), sum)
+
+	_, err = AddMinor(math.MaxInt64, 1)
+	require.ErrorIs(t, err, ErrOverflow)
+
+	_, err = AddMinor(math.MinInt64, -1)
+	require.ErrorIs(t, err, ErrOverflow)
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		value    string
+		currency string
+		minor    int64
+	}{
+		{"12.34", util.USD, 1234},
+		{"12.3", util.USD, 1230},
+		{"12", util.USD, 1200},
+		{"-0.05", util.EUR, -5},
+		{"1234", util.JPY, 1234},
+		{"1.234", util.KWD, 1234},
+		{"0.007", util.KWD, 7},
+		{"92233720368547758.07", util.USD, math.MaxInt64},
+	}
+
+	for _, tc := range testCases {
+		amount, err := Parse(tc.value, tc.currency)
+		require.NoError(t, err, tc.value)
+		require.Equal(t, Amount{Minor: tc.minor, Currency: tc.currency}, amount)
+	}
+
+	_, err := Parse("12.345", util.USD)
+	require.ErrorIs(t, err, ErrInvalidAmount)
+
+	_, err = Parse("12.5", util.JPY)
+	require.ErrorIs(t, err, ErrInvalidAmount)
+
+	_, err = Parse("1e3", util.USD)
+	require.ErrorIs(t, err, ErrInvalidAmount)
+
+	_, err = Parse("", util.USD)
+	require.ErrorIs(t, err, ErrInvalidAmount)
+
+	_, err = Parse("92233720368547758.08", util.USD)
+	require.ErrorIs(t, err, ErrOverflow)
+
+	_, err = Parse("1", "XXX")
+	require.ErrorIs(t, err, ErrUnknownCurrency)
+}
+
+func TestFormat(t *testing.T) {
+	testCases := []struct {
+		amount   Amount
+		expected string
+	}{
+		{Amount{1234, util.USD}, "12.34 USD"},
+		{Amount{5, util.USD}, "0.05 USD"},
+		{Amount{-5, util.EUR}, "-0.05 EUR"},
+		{Amount{1234, util.JPY}, "1234 JPY"},
+		{Amount{-1234, util.JPY}, "-1234 JPY"},
+		{Amount{1234, util.KWD}, "1.234 KWD"},
+		{Amount{7, util.KWD}, "0.007 KWD"},
+		{Amount{0, util.KWD}, "0.000 KWD"},
+		{Amount{math.MinInt64, util.USD}, "-92233720368547758.08 USD"},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, tc.amount.String())
+
+		// Formatting and parsing round trip
+		parsed, err := Parse(tc.amount.Decimal(), tc.amount.Currency)
+		require.NoError(t, err)
+		require.Equal(t, tc.amount, parsed)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	amount := mustNew(t, 1234, util.KWD)
+
+	data, err := json.Marshal(amount)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"amount":"1.234","currency":"KWD"}`, string(data))
+
+	var decoded Amount
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, amount, decoded)
+
+	err = json.Unmarshal([]byte(`{"amount":"1.2345","currency":"KWD"}`), &decoded)
+	require.ErrorIs(t, err, ErrInvalidAmount)
+}
@@ -0,0 +1,264 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        v4.24.0--rc1
+// source: rpc_adjust_account_balance.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AdjustAccountBalanceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AccountId int64 `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	// Added to the balance, a negative amount takes money out
+	Amount int64  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Reason string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (x *AdjustAccountBalanceRequest) Reset() {
+	*x = AdjustAccountBalanceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_adjust_account_balance_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdjustAccountBalanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdjustAccountBalanceRequest) ProtoMessage() {}
+
+func (x *AdjustAccountBalanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_adjust_account_balance_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdjustAccountBalanceRequest.ProtoReflect.Descriptor instead.
+func (*AdjustAccountBalanceRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_adjust_account_balance_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AdjustAccountBalanceRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *AdjustAccountBalanceRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *AdjustAccountBalanceRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type AdjustAccountBalanceResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Adjustment *BalanceAdjustment `protobuf:"bytes,1,opt,name=adjustment,proto3" json:"adjustment,omitempty"`
+	Entry      *Entry             `protobuf:"bytes,2,opt,name=entry,proto3" json:"entry,omitempty"`
+	Account    *Account           `protobuf:"bytes,3,opt,name=account,proto3" json:"account,omitempty"`
+}
+
+func (x *AdjustAccountBalanceResponse) Reset() {
+	*x = AdjustAccountBalanceResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_adjust_account_balance_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdjustAccountBalanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdjustAccountBalanceResponse) ProtoMessage() {}
+
+func (x *AdjustAccountBalanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_adjust_account_balance_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdjustAccountBalanceResponse.ProtoReflect.Descriptor instead.
+func (*AdjustAccountBalanceResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_adjust_account_balance_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AdjustAccountBalanceResponse) GetAdjustment() *BalanceAdjustment {
+	if x != nil {
+		return x.Adjustment
+	}
+	return nil
+}
+
+func (x *AdjustAccountBalanceResponse) GetEntry() *Entry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+func (x *AdjustAccountBalanceResponse) GetAccount() *Account {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+var File_rpc_adjust_account_balance_proto protoreflect.FileDescriptor
+
+var file_rpc_adjust_account_balance_proto_rawDesc = []byte{
+	0x0a, 0x20, 0x72, 0x70, 0x63, 0x5f, 0x61, 0x64, 0x6a, 0x75, 0x73, 0x74, 0x5f, 0x61, 0x63, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x02, 0x70, 0x62, 0x1a, 0x0d, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x18, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x61,
+	0x64, 0x6a, 0x75, 0x73, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
+	0x0b, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x6c, 0x0a, 0x1b,
+	0x41, 0x64, 0x6a, 0x75, 0x73, 0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x42, 0x61, 0x6c,
+	0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x61,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x09, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d,
+	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x9d, 0x01, 0x0a, 0x1c, 0x41,
+	0x64, 0x6a, 0x75, 0x73, 0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x42, 0x61, 0x6c, 0x61,
+	0x6e, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x0a, 0x61,
+	0x64, 0x6a, 0x75, 0x73, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x15, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x41, 0x64, 0x6a, 0x75,
+	0x73, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x0a, 0x61, 0x64, 0x6a, 0x75, 0x73, 0x74, 0x6d, 0x65,
+	0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x09, 0x2e, 0x70, 0x62, 0x2e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x65, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x25, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x42, 0x27, 0x5a, 0x25, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x4f, 0x63, 0x74, 0x6f, 0x62, 0x65, 0x72,
+	0x2d, 0x39, 0x74, 0x68, 0x2f, 0x73, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x2d, 0x62, 0x61, 0x6e, 0x6b,
+	0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_rpc_adjust_account_balance_proto_rawDescOnce sync.Once
+	file_rpc_adjust_account_balance_proto_rawDescData = file_rpc_adjust_account_balance_proto_rawDesc
+)
+
+func file_rpc_adjust_account_balance_proto_rawDescGZIP() []byte {
+	file_rpc_adjust_account_balance_proto_rawDescOnce.Do(func() {
+		file_rpc_adjust_account_balance_proto_rawDescData = protoimpl.X.CompressGZIP(file_rpc_adjust_account_balance_proto_rawDescData)
+	})
+	return file_rpc_adjust_account_balance_proto_rawDescData
+}
+
+var file_rpc_adjust_account_balance_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_adjust_account_balance_proto_goTypes = []interface{}{
+	(*AdjustAccountBalanceRequest)(nil),  // 0: pb.AdjustAccountBalanceRequest
+	(*AdjustAccountBalanceResponse)(nil), // 1: pb.AdjustAccountBalanceResponse
+	(*BalanceAdjustment)(nil),            // 2: pb.BalanceAdjustment
+	(*Entry)(nil),                        // 3: pb.Entry
+	(*Account)(nil),                      // 4: pb.Account
+}
+var file_rpc_adjust_account_balance_proto_depIdxs = []int32{
+	2, // 0: pb.AdjustAccountBalanceResponse.adjustment:type_name -> pb.BalanceAdjustment
+	3, // 1: pb.AdjustAccountBalanceResponse.entry:type_name -> pb.Entry
+	4, // 2: pb.AdjustAccountBalanceResponse.account:type_name -> pb.Account
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_rpc_adjust_account_balance_proto_init() }
+func file_rpc_adjust_account_balance_proto_init() {
+	if File_rpc_adjust_account_balance_proto != nil {
+		return
+	}
+	file_account_proto_init()
+	file_balance_adjustment_proto_init()
+	file_entry_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_rpc_adjust_account_balance_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdjustAccountBalanceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_adjust_account_balance_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdjustAccountBalanceResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rpc_adjust_account_balance_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_adjust_account_balance_proto_goTypes,
+		DependencyIndexes: file_rpc_adjust_account_balance_proto_depIdxs,
+		MessageInfos:      file_rpc_adjust_account_balance_proto_msgTypes,
+	}.Build()
+	File_rpc_adjust_account_balance_proto = out.File
+	file_rpc_adjust_account_balance_proto_rawDesc = nil
+	file_rpc_adjust_account_balance_proto_goTypes = nil
+	file_rpc_adjust_account_balance_proto_depIdxs = nil
+}
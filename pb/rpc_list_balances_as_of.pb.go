@@ -0,0 +1,232 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        v4.24.0--rc1
+// source: rpc_list_balances_as_of.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ListBalancesAsOfRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Defaults to the logged in user, another user's balances require a staff role
+	Owner string                 `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	AsOf  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+}
+
+func (x *ListBalancesAsOfRequest) Reset() {
+	*x = ListBalancesAsOfRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_list_balances_as_of_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListBalancesAsOfRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBalancesAsOfRequest) ProtoMessage() {}
+
+func (x *ListBalancesAsOfRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_list_balances_as_of_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBalancesAsOfRequest.ProtoReflect.Descriptor instead.
+func (*ListBalancesAsOfRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_list_balances_as_of_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ListBalancesAsOfRequest) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *ListBalancesAsOfRequest) GetAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AsOf
+	}
+	return nil
+}
+
+type ListBalancesAsOfResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// One balance for every account the owner had at as_of
+	Balances []*AccountBalance `protobuf:"bytes,1,rep,name=balances,proto3" json:"balances,omitempty"`
+}
+
+func (x *ListBalancesAsOfResponse) Reset() {
+	*x = ListBalancesAsOfResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_list_balances_as_of_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListBalancesAsOfResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBalancesAsOfResponse) ProtoMessage() {}
+
+func (x *ListBalancesAsOfResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_list_balances_as_of_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBalancesAsOfResponse.ProtoReflect.Descriptor instead.
+func (*ListBalancesAsOfResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_list_balances_as_of_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListBalancesAsOfResponse) GetBalances() []*AccountBalance {
+	if x != nil {
+		return x.Balances
+	}
+	return nil
+}
+
+var File_rpc_list_balances_as_of_proto protoreflect.FileDescriptor
+
+var file_rpc_list_balances_as_of_proto_rawDesc = []byte{
+	0x0a, 0x1d, 0x72, 0x70, 0x63, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e,
+	0x63, 0x65, 0x73, 0x5f, 0x61, 0x73, 0x5f, 0x6f, 0x66, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x02, 0x70, 0x62, 0x1a, 0x15, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x62, 0x61, 0x6c,
+	0x61, 0x6e, 0x63, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x60, 0x0a, 0x17, 0x4c,
+	0x69, 0x73, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x41, 0x73, 0x4f, 0x66, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x2f, 0x0a, 0x05,
+	0x61, 0x73, 0x5f, 0x6f, 0x66, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x61, 0x73, 0x4f, 0x66, 0x22, 0x4a, 0x0a,
+	0x18, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x41, 0x73, 0x4f,
+	0x66, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x08, 0x62, 0x61, 0x6c,
+	0x61, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x62,
+	0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x52,
+	0x08, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x42, 0x27, 0x5a, 0x25, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x4f, 0x63, 0x74, 0x6f, 0x62, 0x65, 0x72, 0x2d,
+	0x39, 0x74, 0x68, 0x2f, 0x73, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x2d, 0x62, 0x61, 0x6e, 0x6b, 0x2f,
+	0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_rpc_list_balances_as_of_proto_rawDescOnce sync.Once
+	file_rpc_list_balances_as_of_proto_rawDescData = file_rpc_list_balances_as_of_proto_rawDesc
+)
+
+func file_rpc_list_balances_as_of_proto_rawDescGZIP() []byte {
+	file_rpc_list_balances_as_of_proto_rawDescOnce.Do(func() {
+		file_rpc_list_balances_as_of_proto_rawDescData = protoimpl.X.CompressGZIP(file_rpc_list_balances_as_of_proto_rawDescData)
+	})
+	return file_rpc_list_balances_as_of_proto_rawDescData
+}
+
+var file_rpc_list_balances_as_of_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_list_balances_as_of_proto_goTypes = []interface{}{
+	(*ListBalancesAsOfRequest)(nil),  // 0: pb.ListBalancesAsOfRequest
+	(*ListBalancesAsOfResponse)(nil), // 1: pb.ListBalancesAsOfResponse
+	(*timestamppb.Timestamp)(nil),    // 2: google.protobuf.Timestamp
+	(*AccountBalance)(nil),           // 3: pb.AccountBalance
+}
+var file_rpc_list_balances_as_of_proto_depIdxs = []int32{
+	2, // 0: pb.ListBalancesAsOfRequest.as_of:type_name -> google.protobuf.Timestamp
+	3, // 1: pb.ListBalancesAsOfResponse.balances:type_name -> pb.AccountBalance
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_rpc_list_balances_as_of_proto_init() }
+func file_rpc_list_balances_as_of_proto_init() {
+	if File_rpc_list_balances_as_of_proto != nil {
+		return
+	}
+	file_account_balance_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_rpc_list_balances_as_of_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListBalancesAsOfRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_list_balances_as_of_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListBalancesAsOfResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rpc_list_balances_as_of_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_list_balances_as_of_proto_goTypes,
+		DependencyIndexes: file_rpc_list_balances_as_of_proto_depIdxs,
+		MessageInfos:      file_rpc_list_balances_as_of_proto_msgTypes,
+	}.Build()
+	File_rpc_list_balances_as_of_proto = out.File
+	file_rpc_list_balances_as_of_proto_rawDesc = nil
+	file_rpc_list_balances_as_of_proto_goTypes = nil
+	file_rpc_list_balances_as_of_proto_depIdxs = nil
+}
@@ -0,0 +1,217 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        v4.24.0--rc1
+// source: rpc_load_exchange_rates.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type LoadExchangeRatesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Only base_currency, quote_currency, rate and effective_at are read
+	Rates []*ExchangeRate `protobuf:"bytes,1,rep,name=rates,proto3" json:"rates,omitempty"`
+}
+
+func (x *LoadExchangeRatesRequest) Reset() {
+	*x = LoadExchangeRatesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_load_exchange_rates_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoadExchangeRatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadExchangeRatesRequest) ProtoMessage() {}
+
+func (x *LoadExchangeRatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_load_exchange_rates_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadExchangeRatesRequest.ProtoReflect.Descriptor instead.
+func (*LoadExchangeRatesRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_load_exchange_rates_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LoadExchangeRatesRequest) GetRates() []*ExchangeRate {
+	if x != nil {
+		return x.Rates
+	}
+	return nil
+}
+
+type LoadExchangeRatesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rates []*ExchangeRate `protobuf:"bytes,1,rep,name=rates,proto3" json:"rates,omitempty"`
+}
+
+func (x *LoadExchangeRatesResponse) Reset() {
+	*x = LoadExchangeRatesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_load_exchange_rates_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoadExchangeRatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadExchangeRatesResponse) ProtoMessage() {}
+
+func (x *LoadExchangeRatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_load_exchange_rates_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadExchangeRatesResponse.ProtoReflect.Descriptor instead.
+func (*LoadExchangeRatesResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_load_exchange_rates_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LoadExchangeRatesResponse) GetRates() []*ExchangeRate {
+	if x != nil {
+		return x.Rates
+	}
+	return nil
+}
+
+var File_rpc_load_exchange_rates_proto protoreflect.FileDescriptor
+
+var file_rpc_load_exchange_rates_proto_rawDesc = []byte{
+	0x0a, 0x1d, 0x72, 0x70, 0x63, 0x5f, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x65, 0x78, 0x63, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x02, 0x70, 0x62, 0x1a, 0x13, 0x65, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x72, 0x61,
+	0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x42, 0x0a, 0x18, 0x4c, 0x6f, 0x61, 0x64,
+	0x45, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x05, 0x72, 0x61, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x62, 0x2e, 0x45, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x52, 0x61, 0x74, 0x65, 0x52, 0x05, 0x72, 0x61, 0x74, 0x65, 0x73, 0x22, 0x43, 0x0a, 0x19,
+	0x4c, 0x6f, 0x61, 0x64, 0x45, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x61, 0x74, 0x65,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x05, 0x72, 0x61, 0x74,
+	0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x62, 0x2e, 0x45, 0x78,
+	0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x61, 0x74, 0x65, 0x52, 0x05, 0x72, 0x61, 0x74, 0x65,
+	0x73, 0x42, 0x27, 0x5a, 0x25, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x4f, 0x63, 0x74, 0x6f, 0x62, 0x65, 0x72, 0x2d, 0x39, 0x74, 0x68, 0x2f, 0x73, 0x69, 0x6d, 0x70,
+	0x6c, 0x65, 0x2d, 0x62, 0x61, 0x6e, 0x6b, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_rpc_load_exchange_rates_proto_rawDescOnce sync.Once
+	file_rpc_load_exchange_rates_proto_rawDescData = file_rpc_load_exchange_rates_proto_rawDesc
+)
+
+func file_rpc_load_exchange_rates_proto_rawDescGZIP() []byte {
+	file_rpc_load_exchange_rates_proto_rawDescOnce.Do(func() {
+		file_rpc_load_exchange_rates_proto_rawDescData = protoimpl.X.CompressGZIP(file_rpc_load_exchange_rates_proto_rawDescData)
+	})
+	return file_rpc_load_exchange_rates_proto_rawDescData
+}
+
+var file_rpc_load_exchange_rates_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_load_exchange_rates_proto_goTypes = []interface{}{
+	(*LoadExchangeRatesRequest)(nil),  // 0: pb.LoadExchangeRatesRequest
+	(*LoadExchangeRatesResponse)(nil), // 1: pb.LoadExchangeRatesResponse
+	(*ExchangeRate)(nil),              // 2: pb.ExchangeRate
+}
+var file_rpc_load_exchange_rates_proto_depIdxs = []int32{
+	2, // 0: pb.LoadExchangeRatesRequest.rates:type_name -> pb.ExchangeRate
+	2, // 1: pb.LoadExchangeRatesResponse.rates:type_name -> pb.ExchangeRate
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_rpc_load_exchange_rates_proto_init() }
+func file_rpc_load_exchange_rates_proto_init() {
+	if File_rpc_load_exchange_rates_proto != nil {
+		return
+	}
+	file_exchange_rate_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_rpc_load_exchange_rates_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadExchangeRatesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_load_exchange_rates_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadExchangeRatesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rpc_load_exchange_rates_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_load_exchange_rates_proto_goTypes,
+		DependencyIndexes: file_rpc_load_exchange_rates_proto_depIdxs,
+		MessageInfos:      file_rpc_load_exchange_rates_proto_msgTypes,
+	}.Build()
+	File_rpc_load_exchange_rates_proto = out.File
+	file_rpc_load_exchange_rates_proto_rawDesc = nil
+	file_rpc_load_exchange_rates_proto_goTypes = nil
+	file_rpc_load_exchange_rates_proto_depIdxs = nil
+}
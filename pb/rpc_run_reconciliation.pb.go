@@ -0,0 +1,217 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        v4.24.0--rc1
+// source: rpc_run_reconciliation.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type RunReconciliationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *RunReconciliationRequest) Reset() {
+	*x = RunReconciliationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_run_reconciliation_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RunReconciliationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunReconciliationRequest) ProtoMessage() {}
+
+func (x *RunReconciliationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_run_reconciliation_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunReconciliationRequest.ProtoReflect.Descriptor instead.
+func (*RunReconciliationRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_run_reconciliation_proto_rawDescGZIP(), []int{0}
+}
+
+type RunReconciliationResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Run           *ReconciliationRun `protobuf:"bytes,1,opt,name=run,proto3" json:"run,omitempty"`
+	Discrepancies []*Discrepancy     `protobuf:"bytes,2,rep,name=discrepancies,proto3" json:"discrepancies,omitempty"`
+}
+
+func (x *RunReconciliationResponse) Reset() {
+	*x = RunReconciliationResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_run_reconciliation_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RunReconciliationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunReconciliationResponse) ProtoMessage() {}
+
+func (x *RunReconciliationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_run_reconciliation_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunReconciliationResponse.ProtoReflect.Descriptor instead.
+func (*RunReconciliationResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_run_reconciliation_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RunReconciliationResponse) GetRun() *ReconciliationRun {
+	if x != nil {
+		return x.Run
+	}
+	return nil
+}
+
+func (x *RunReconciliationResponse) GetDiscrepancies() []*Discrepancy {
+	if x != nil {
+		return x.Discrepancies
+	}
+	return nil
+}
+
+var File_rpc_run_reconciliation_proto protoreflect.FileDescriptor
+
+var file_rpc_run_reconciliation_proto_rawDesc = []byte{
+	0x0a, 0x1c, 0x72, 0x70, 0x63, 0x5f, 0x72, 0x75, 0x6e, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x6e, 0x63,
+	0x69, 0x6c, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02,
+	0x70, 0x62, 0x1a, 0x14, 0x72, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x69, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x1a, 0x0a, 0x18, 0x52, 0x75, 0x6e, 0x52,
+	0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x22, 0x7b, 0x0a, 0x19, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x63, 0x6f, 0x6e,
+	0x63, 0x69, 0x6c, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x27, 0x0a, 0x03, 0x72, 0x75, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15,
+	0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x69, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x75, 0x6e, 0x52, 0x03, 0x72, 0x75, 0x6e, 0x12, 0x35, 0x0a, 0x0d, 0x64, 0x69,
+	0x73, 0x63, 0x72, 0x65, 0x70, 0x61, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x62, 0x2e, 0x44, 0x69, 0x73, 0x63, 0x72, 0x65, 0x70, 0x61, 0x6e,
+	0x63, 0x79, 0x52, 0x0d, 0x64, 0x69, 0x73, 0x63, 0x72, 0x65, 0x70, 0x61, 0x6e, 0x63, 0x69, 0x65,
+	0x73, 0x42, 0x27, 0x5a, 0x25, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x4f, 0x63, 0x74, 0x6f, 0x62, 0x65, 0x72, 0x2d, 0x39, 0x74, 0x68, 0x2f, 0x73, 0x69, 0x6d, 0x70,
+	0x6c, 0x65, 0x2d, 0x62, 0x61, 0x6e, 0x6b, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_rpc_run_reconciliation_proto_rawDescOnce sync.Once
+	file_rpc_run_reconciliation_proto_rawDescData = file_rpc_run_reconciliation_proto_rawDesc
+)
+
+func file_rpc_run_reconciliation_proto_rawDescGZIP() []byte {
+	file_rpc_run_reconciliation_proto_rawDescOnce.Do(func() {
+		file_rpc_run_reconciliation_proto_rawDescData = protoimpl.X.CompressGZIP(file_rpc_run_reconciliation_proto_rawDescData)
+	})
+	return file_rpc_run_reconciliation_proto_rawDescData
+}
+
+var file_rpc_run_reconciliation_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_run_reconciliation_proto_goTypes = []interface{}{
+	(*RunReconciliationRequest)(nil),  // 0: pb.RunReconciliationRequest
+	(*RunReconciliationResponse)(nil), // 1: pb.RunReconciliationResponse
+	(*ReconciliationRun)(nil),         // 2: pb.ReconciliationRun
+	(*Discrepancy)(nil),               // 3: pb.Discrepancy
+}
+var file_rpc_run_reconciliation_proto_depIdxs = []int32{
+	2, // 0: pb.RunReconciliationResponse.run:type_name -> pb.ReconciliationRun
+	3, // 1: pb.RunReconciliationResponse.discrepancies:type_name -> pb.Discrepancy
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_rpc_run_reconciliation_proto_init() }
+func file_rpc_run_reconciliation_proto_init() {
+	if File_rpc_run_reconciliation_proto != nil {
+		return
+	}
+	file_reconciliation_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_rpc_run_reconciliation_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunReconciliationRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_run_reconciliation_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunReconciliationResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rpc_run_reconciliation_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_run_reconciliation_proto_goTypes,
+		DependencyIndexes: file_rpc_run_reconciliation_proto_depIdxs,
+		MessageInfos:      file_rpc_run_reconciliation_proto_msgTypes,
+	}.Build()
+	File_rpc_run_reconciliation_proto = out.File
+	file_rpc_run_reconciliation_proto_rawDesc = nil
+	file_rpc_run_reconciliation_proto_goTypes = nil
+	file_rpc_run_reconciliation_proto_depIdxs = nil
+}
@@ -0,0 +1,251 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        v4.24.0--rc1
+// source: rpc_update_overdraft_limit.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type UpdateOverdraftLimitRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AccountId int64 `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	// How far below zero the balance may go, 0 removes the overdraft
+	OverdraftLimit int64  `protobuf:"varint,2,opt,name=overdraft_limit,json=overdraftLimit,proto3" json:"overdraft_limit,omitempty"`
+	Reason         string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (x *UpdateOverdraftLimitRequest) Reset() {
+	*x = UpdateOverdraftLimitRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_update_overdraft_limit_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateOverdraftLimitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateOverdraftLimitRequest) ProtoMessage() {}
+
+func (x *UpdateOverdraftLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_update_overdraft_limit_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateOverdraftLimitRequest.ProtoReflect.Descriptor instead.
+func (*UpdateOverdraftLimitRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_update_overdraft_limit_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *UpdateOverdraftLimitRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *UpdateOverdraftLimitRequest) GetOverdraftLimit() int64 {
+	if x != nil {
+		return x.OverdraftLimit
+	}
+	return 0
+}
+
+func (x *UpdateOverdraftLimitRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type UpdateOverdraftLimitResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Change  *OverdraftLimitChange `protobuf:"bytes,1,opt,name=change,proto3" json:"change,omitempty"`
+	Account *Account              `protobuf:"bytes,2,opt,name=account,proto3" json:"account,omitempty"`
+}
+
+func (x *UpdateOverdraftLimitResponse) Reset() {
+	*x = UpdateOverdraftLimitResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_update_overdraft_limit_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateOverdraftLimitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateOverdraftLimitResponse) ProtoMessage() {}
+
+func (x *UpdateOverdraftLimitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_update_overdraft_limit_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateOverdraftLimitResponse.ProtoReflect.Descriptor instead.
+func (*UpdateOverdraftLimitResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_update_overdraft_limit_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UpdateOverdraftLimitResponse) GetChange() *OverdraftLimitChange {
+	if x != nil {
+		return x.Change
+	}
+	return nil
+}
+
+func (x *UpdateOverdraftLimitResponse) GetAccount() *Account {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+var File_rpc_update_overdraft_limit_proto protoreflect.FileDescriptor
+
+var file_rpc_update_overdraft_limit_proto_rawDesc = []byte{
+	0x0a, 0x20, 0x72, 0x70, 0x63, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x6f, 0x76, 0x65,
+	0x72, 0x64, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x02, 0x70, 0x62, 0x1a, 0x0d, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1c, 0x6f, 0x76, 0x65, 0x72, 0x64, 0x72, 0x61, 0x66, 0x74,
+	0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x22, 0x7d, 0x0a, 0x1b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4f, 0x76, 0x65,
+	0x72, 0x64, 0x72, 0x61, 0x66, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49,
+	0x64, 0x12, 0x27, 0x0a, 0x0f, 0x6f, 0x76, 0x65, 0x72, 0x64, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x6f, 0x76, 0x65, 0x72,
+	0x64, 0x72, 0x61, 0x66, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x22, 0x77, 0x0a, 0x1c, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4f, 0x76, 0x65, 0x72,
+	0x64, 0x72, 0x61, 0x66, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x30, 0x0a, 0x06, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x4f, 0x76, 0x65, 0x72, 0x64, 0x72, 0x61, 0x66,
+	0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x06, 0x63, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x12, 0x25, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x42, 0x27, 0x5a, 0x25, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x4f, 0x63, 0x74, 0x6f, 0x62, 0x65,
+	0x72, 0x2d, 0x39, 0x74, 0x68, 0x2f, 0x73, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x2d, 0x62, 0x61, 0x6e,
+	0x6b, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_rpc_update_overdraft_limit_proto_rawDescOnce sync.Once
+	file_rpc_update_overdraft_limit_proto_rawDescData = file_rpc_update_overdraft_limit_proto_rawDesc
+)
+
+func file_rpc_update_overdraft_limit_proto_rawDescGZIP() []byte {
+	file_rpc_update_overdraft_limit_proto_rawDescOnce.Do(func() {
+		file_rpc_update_overdraft_limit_proto_rawDescData = protoimpl.X.CompressGZIP(file_rpc_update_overdraft_limit_proto_rawDescData)
+	})
+	return file_rpc_update_overdraft_limit_proto_rawDescData
+}
+
+var file_rpc_update_overdraft_limit_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_update_overdraft_limit_proto_goTypes = []interface{}{
+	(*UpdateOverdraftLimitRequest)(nil),  // 0: pb.UpdateOverdraftLimitRequest
+	(*UpdateOverdraftLimitResponse)(nil), // 1: pb.UpdateOverdraftLimitResponse
+	(*OverdraftLimitChange)(nil),         // 2: pb.OverdraftLimitChange
+	(*Account)(nil),                      // 3: pb.Account
+}
+var file_rpc_update_overdraft_limit_proto_depIdxs = []int32{
+	2, // 0: pb.UpdateOverdraftLimitResponse.change:type_name -> pb.OverdraftLimitChange
+	3, // 1: pb.UpdateOverdraftLimitResponse.account:type_name -> pb.Account
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_rpc_update_overdraft_limit_proto_init() }
+func file_rpc_update_overdraft_limit_proto_init() {
+	if File_rpc_update_overdraft_limit_proto != nil {
+		return
+	}
+	file_account_proto_init()
+	file_overdraft_limit_change_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_rpc_update_overdraft_limit_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateOverdraftLimitRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_update_overdraft_limit_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateOverdraftLimitResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rpc_update_overdraft_limit_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_update_overdraft_limit_proto_goTypes,
+		DependencyIndexes: file_rpc_update_overdraft_limit_proto_depIdxs,
+		MessageInfos:      file_rpc_update_overdraft_limit_proto_msgTypes,
+	}.Build()
+	File_rpc_update_overdraft_limit_proto = out.File
+	file_rpc_update_overdraft_limit_proto_rawDesc = nil
+	file_rpc_update_overdraft_limit_proto_goTypes = nil
+	file_rpc_update_overdraft_limit_proto_depIdxs = nil
+}
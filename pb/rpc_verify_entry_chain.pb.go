@@ -0,0 +1,269 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        v4.24.0--rc1
+// source: rpc_verify_entry_chain.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type VerifyEntryChainRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AccountId int64 `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+}
+
+func (x *VerifyEntryChainRequest) Reset() {
+	*x = VerifyEntryChainRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_verify_entry_chain_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyEntryChainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyEntryChainRequest) ProtoMessage() {}
+
+func (x *VerifyEntryChainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_verify_entry_chain_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyEntryChainRequest.ProtoReflect.Descriptor instead.
+func (*VerifyEntryChainRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_verify_entry_chain_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *VerifyEntryChainRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+type VerifyEntryChainResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AccountId       int64  `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	EntriesChecked  int64  `protobuf:"varint,2,opt,name=entries_checked,json=entriesChecked,proto3" json:"entries_checked,omitempty"`
+	UnsealedEntries int64  `protobuf:"varint,3,opt,name=unsealed_entries,json=unsealedEntries,proto3" json:"unsealed_entries,omitempty"`
+	Intact          bool   `protobuf:"varint,4,opt,name=intact,proto3" json:"intact,omitempty"`
+	BrokenEntryId   int64  `protobuf:"varint,5,opt,name=broken_entry_id,json=brokenEntryId,proto3" json:"broken_entry_id,omitempty"`
+	Reason          string `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	Detail          string `protobuf:"bytes,7,opt,name=detail,proto3" json:"detail,omitempty"`
+}
+
+func (x *VerifyEntryChainResponse) Reset() {
+	*x = VerifyEntryChainResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_verify_entry_chain_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyEntryChainResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyEntryChainResponse) ProtoMessage() {}
+
+func (x *VerifyEntryChainResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_verify_entry_chain_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyEntryChainResponse.ProtoReflect.Descriptor instead.
+func (*VerifyEntryChainResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_verify_entry_chain_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *VerifyEntryChainResponse) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *VerifyEntryChainResponse) GetEntriesChecked() int64 {
+	if x != nil {
+		return x.EntriesChecked
+	}
+	return 0
+}
+
+func (x *VerifyEntryChainResponse) GetUnsealedEntries() int64 {
+	if x != nil {
+		return x.UnsealedEntries
+	}
+	return 0
+}
+
+func (x *VerifyEntryChainResponse) GetIntact() bool {
+	if x != nil {
+		return x.Intact
+	}
+	return false
+}
+
+func (x *VerifyEntryChainResponse) GetBrokenEntryId() int64 {
+	if x != nil {
+		return x.BrokenEntryId
+	}
+	return 0
+}
+
+func (x *VerifyEntryChainResponse) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *VerifyEntryChainResponse) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+var File_rpc_verify_entry_chain_proto protoreflect.FileDescriptor
+
+var file_rpc_verify_entry_chain_proto_rawDesc = []byte{
+	0x0a, 0x1c, 0x72, 0x70, 0x63, 0x5f, 0x76, 0x65, 0x72, 0x69, 0x66, 0x79, 0x5f, 0x65, 0x6e, 0x74,
+	0x72, 0x79, 0x5f, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02,
+	0x70, 0x62, 0x22, 0x38, 0x0a, 0x17, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a,
+	0x0a, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x09, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49, 0x64, 0x22, 0xfd, 0x01, 0x0a,
+	0x18, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x43, 0x68, 0x61, 0x69,
+	0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x63, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x61,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x65, 0x6e, 0x74, 0x72,
+	0x69, 0x65, 0x73, 0x5f, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0e, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x65,
+	0x64, 0x12, 0x29, 0x0a, 0x10, 0x75, 0x6e, 0x73, 0x65, 0x61, 0x6c, 0x65, 0x64, 0x5f, 0x65, 0x6e,
+	0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x75, 0x6e, 0x73,
+	0x65, 0x61, 0x6c, 0x65, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06,
+	0x69, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x69, 0x6e,
+	0x74, 0x61, 0x63, 0x74, 0x12, 0x26, 0x0a, 0x0f, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x6e, 0x5f, 0x65,
+	0x6e, 0x74, 0x72, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x62,
+	0x72, 0x6f, 0x6b, 0x65, 0x6e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06,
+	0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x42, 0x27, 0x5a, 0x25,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x4f, 0x63, 0x74, 0x6f, 0x62,
+	0x65, 0x72, 0x2d, 0x39, 0x74, 0x68, 0x2f, 0x73, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x2d, 0x62, 0x61,
+	0x6e, 0x6b, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_rpc_verify_entry_chain_proto_rawDescOnce sync.Once
+	file_rpc_verify_entry_chain_proto_rawDescData = file_rpc_verify_entry_chain_proto_rawDesc
+)
+
+func file_rpc_verify_entry_chain_proto_rawDescGZIP() []byte {
+	file_rpc_verify_entry_chain_proto_rawDescOnce.Do(func() {
+		file_rpc_verify_entry_chain_proto_rawDescData = protoimpl.X.CompressGZIP(file_rpc_verify_entry_chain_proto_rawDescData)
+	})
+	return file_rpc_verify_entry_chain_proto_rawDescData
+}
+
+var file_rpc_verify_entry_chain_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_verify_entry_chain_proto_goTypes = []interface{}{
+	(*VerifyEntryChainRequest)(nil),  // 0: pb.VerifyEntryChainRequest
+	(*VerifyEntryChainResponse)(nil), // 1: pb.VerifyEntryChainResponse
+}
+var file_rpc_verify_entry_chain_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_rpc_verify_entry_chain_proto_init() }
+func file_rpc_verify_entry_chain_proto_init() {
+	if File_rpc_verify_entry_chain_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_rpc_verify_entry_chain_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifyEntryChainRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_verify_entry_chain_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifyEntryChainResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rpc_verify_entry_chain_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_verify_entry_chain_proto_goTypes,
+		DependencyIndexes: file_rpc_verify_entry_chain_proto_depIdxs,
+		MessageInfos:      file_rpc_verify_entry_chain_proto_msgTypes,
+	}.Build()
+	File_rpc_verify_entry_chain_proto = out.File
+	file_rpc_verify_entry_chain_proto_rawDesc = nil
+	file_rpc_verify_entry_chain_proto_goTypes = nil
+	file_rpc_verify_entry_chain_proto_depIdxs = nil
+}
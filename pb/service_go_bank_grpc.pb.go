@@ -8,6 +8,7 @@ package pb
 
 import (
 	context "context"
+	httpbody "google.golang.org/genproto/googleapis/api/httpbody"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -19,8 +20,36 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	GoBank_CreateUser_FullMethodName = "/pb.GoBank/CreateUser"
-	GoBank_LoginUser_FullMethodName  = "/pb.GoBank/LoginUser"
+	GoBank_CreateUser_FullMethodName           = "/pb.GoBank/CreateUser"
+	GoBank_LoginUser_FullMethodName            = "/pb.GoBank/LoginUser"
+	GoBank_RenewAccessToken_FullMethodName     = "/pb.GoBank/RenewAccessToken"
+	GoBank_UpdateUserRole_FullMethodName       = "/pb.GoBank/UpdateUserRole"
+	GoBank_Logout_FullMethodName               = "/pb.GoBank/Logout"
+	GoBank_LogoutAllSessions_FullMethodName    = "/pb.GoBank/LogoutAllSessions"
+	GoBank_ListSessions_FullMethodName         = "/pb.GoBank/ListSessions"
+	GoBank_RevokeSession_FullMethodName        = "/pb.GoBank/RevokeSession"
+	GoBank_RevokeOtherSessions_FullMethodName  = "/pb.GoBank/RevokeOtherSessions"
+	GoBank_CreateAccount_FullMethodName        = "/pb.GoBank/CreateAccount"
+	GoBank_GetAccount_FullMethodName           = "/pb.GoBank/GetAccount"
+	GoBank_ListAccounts_FullMethodName         = "/pb.GoBank/ListAccounts"
+	GoBank_CloseAccount_FullMethodName         = "/pb.GoBank/CloseAccount"
+	GoBank_FreezeAccount_FullMethodName        = "/pb.GoBank/FreezeAccount"
+	GoBank_UnfreezeAccount_FullMethodName      = "/pb.GoBank/UnfreezeAccount"
+	GoBank_AdjustAccountBalance_FullMethodName = "/pb.GoBank/AdjustAccountBalance"
+	GoBank_UpdateOverdraftLimit_FullMethodName = "/pb.GoBank/UpdateOverdraftLimit"
+	GoBank_CreateTransfer_FullMethodName       = "/pb.GoBank/CreateTransfer"
+	GoBank_ReverseTransfer_FullMethodName      = "/pb.GoBank/ReverseTransfer"
+	GoBank_ListTransfers_FullMethodName        = "/pb.GoBank/ListTransfers"
+	GoBank_SearchTransfers_FullMethodName      = "/pb.GoBank/SearchTransfers"
+	GoBank_ListEntries_FullMethodName          = "/pb.GoBank/ListEntries"
+	GoBank_GetAccountStatement_FullMethodName  = "/pb.GoBank/GetAccountStatement"
+	GoBank_GetBalanceAsOf_FullMethodName       = "/pb.GoBank/GetBalanceAsOf"
+	GoBank_ListBalancesAsOf_FullMethodName     = "/pb.GoBank/ListBalancesAsOf"
+	GoBank_LoadExchangeRates_FullMethodName    = "/pb.GoBank/LoadExchangeRates"
+	GoBank_RunReconciliation_FullMethodName    = "/pb.GoBank/RunReconciliation"
+	GoBank_GetReconciliation_FullMethodName    = "/pb.GoBank/GetReconciliation"
+	GoBank_ListReconciliations_FullMethodName  = "/pb.GoBank/ListReconciliations"
+	GoBank_VerifyEntryChain_FullMethodName     = "/pb.GoBank/VerifyEntryChain"
 )
 
 // GoBankClient is the client API for GoBank service.
@@ -29,6 +58,34 @@ const (
 type GoBankClient interface {
 	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*CreateUserResponse, error)
 	LoginUser(ctx context.Context, in *LoginUserRequest, opts ...grpc.CallOption) (*LoginUserResponse, error)
+	RenewAccessToken(ctx context.Context, in *RenewAccessTokenRequest, opts ...grpc.CallOption) (*RenewAccessTokenResponse, error)
+	UpdateUserRole(ctx context.Context, in *UpdateUserRoleRequest, opts ...grpc.CallOption) (*UpdateUserRoleResponse, error)
+	Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutResponse, error)
+	LogoutAllSessions(ctx context.Context, in *LogoutAllSessionsRequest, opts ...grpc.CallOption) (*LogoutAllSessionsResponse, error)
+	ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*RevokeSessionResponse, error)
+	RevokeOtherSessions(ctx context.Context, in *RevokeOtherSessionsRequest, opts ...grpc.CallOption) (*RevokeOtherSessionsResponse, error)
+	CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*CreateAccountResponse, error)
+	GetAccount(ctx context.Context, in *GetAccountRequest, opts ...grpc.CallOption) (*GetAccountResponse, error)
+	ListAccounts(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error)
+	CloseAccount(ctx context.Context, in *CloseAccountRequest, opts ...grpc.CallOption) (*CloseAccountResponse, error)
+	FreezeAccount(ctx context.Context, in *FreezeAccountRequest, opts ...grpc.CallOption) (*FreezeAccountResponse, error)
+	UnfreezeAccount(ctx context.Context, in *UnfreezeAccountRequest, opts ...grpc.CallOption) (*UnfreezeAccountResponse, error)
+	AdjustAccountBalance(ctx context.Context, in *AdjustAccountBalanceRequest, opts ...grpc.CallOption) (*AdjustAccountBalanceResponse, error)
+	UpdateOverdraftLimit(ctx context.Context, in *UpdateOverdraftLimitRequest, opts ...grpc.CallOption) (*UpdateOverdraftLimitResponse, error)
+	CreateTransfer(ctx context.Context, in *CreateTransferRequest, opts ...grpc.CallOption) (*CreateTransferResponse, error)
+	ReverseTransfer(ctx context.Context, in *ReverseTransferRequest, opts ...grpc.CallOption) (*ReverseTransferResponse, error)
+	ListTransfers(ctx context.Context, in *ListTransfersRequest, opts ...grpc.CallOption) (*ListTransfersResponse, error)
+	SearchTransfers(ctx context.Context, in *SearchTransfersRequest, opts ...grpc.CallOption) (*SearchTransfersResponse, error)
+	ListEntries(ctx context.Context, in *ListEntriesRequest, opts ...grpc.CallOption) (*ListEntriesResponse, error)
+	GetAccountStatement(ctx context.Context, in *GetAccountStatementRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+	GetBalanceAsOf(ctx context.Context, in *GetBalanceAsOfRequest, opts ...grpc.CallOption) (*GetBalanceAsOfResponse, error)
+	ListBalancesAsOf(ctx context.Context, in *ListBalancesAsOfRequest, opts ...grpc.CallOption) (*ListBalancesAsOfResponse, error)
+	LoadExchangeRates(ctx context.Context, in *LoadExchangeRatesRequest, opts ...grpc.CallOption) (*LoadExchangeRatesResponse, error)
+	RunReconciliation(ctx context.Context, in *RunReconciliationRequest, opts ...grpc.CallOption) (*RunReconciliationResponse, error)
+	GetReconciliation(ctx context.Context, in *GetReconciliationRequest, opts ...grpc.CallOption) (*GetReconciliationResponse, error)
+	ListReconciliations(ctx context.Context, in *ListReconciliationsRequest, opts ...grpc.CallOption) (*ListReconciliationsResponse, error)
+	VerifyEntryChain(ctx context.Context, in *VerifyEntryChainRequest, opts ...grpc.CallOption) (*VerifyEntryChainResponse, error)
 }
 
 type goBankClient struct {
@@ -57,12 +114,292 @@ func (c *goBankClient) LoginUser(ctx context.Context, in *LoginUserRequest, opts
 	return out, nil
 }
 
+func (c *goBankClient) RenewAccessToken(ctx context.Context, in *RenewAccessTokenRequest, opts ...grpc.CallOption) (*RenewAccessTokenResponse, error) {
+	out := new(RenewAccessTokenResponse)
+	err := c.cc.Invoke(ctx, GoBank_RenewAccessToken_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) UpdateUserRole(ctx context.Context, in *UpdateUserRoleRequest, opts ...grpc.CallOption) (*UpdateUserRoleResponse, error) {
+	out := new(UpdateUserRoleResponse)
+	err := c.cc.Invoke(ctx, GoBank_UpdateUserRole_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutResponse, error) {
+	out := new(LogoutResponse)
+	err := c.cc.Invoke(ctx, GoBank_Logout_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) LogoutAllSessions(ctx context.Context, in *LogoutAllSessionsRequest, opts ...grpc.CallOption) (*LogoutAllSessionsResponse, error) {
+	out := new(LogoutAllSessionsResponse)
+	err := c.cc.Invoke(ctx, GoBank_LogoutAllSessions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	out := new(ListSessionsResponse)
+	err := c.cc.Invoke(ctx, GoBank_ListSessions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*RevokeSessionResponse, error) {
+	out := new(RevokeSessionResponse)
+	err := c.cc.Invoke(ctx, GoBank_RevokeSession_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) RevokeOtherSessions(ctx context.Context, in *RevokeOtherSessionsRequest, opts ...grpc.CallOption) (*RevokeOtherSessionsResponse, error) {
+	out := new(RevokeOtherSessionsResponse)
+	err := c.cc.Invoke(ctx, GoBank_RevokeOtherSessions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*CreateAccountResponse, error) {
+	out := new(CreateAccountResponse)
+	err := c.cc.Invoke(ctx, GoBank_CreateAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) GetAccount(ctx context.Context, in *GetAccountRequest, opts ...grpc.CallOption) (*GetAccountResponse, error) {
+	out := new(GetAccountResponse)
+	err := c.cc.Invoke(ctx, GoBank_GetAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) ListAccounts(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error) {
+	out := new(ListAccountsResponse)
+	err := c.cc.Invoke(ctx, GoBank_ListAccounts_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) CloseAccount(ctx context.Context, in *CloseAccountRequest, opts ...grpc.CallOption) (*CloseAccountResponse, error) {
+	out := new(CloseAccountResponse)
+	err := c.cc.Invoke(ctx, GoBank_CloseAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) FreezeAccount(ctx context.Context, in *FreezeAccountRequest, opts ...grpc.CallOption) (*FreezeAccountResponse, error) {
+	out := new(FreezeAccountResponse)
+	err := c.cc.Invoke(ctx, GoBank_FreezeAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) UnfreezeAccount(ctx context.Context, in *UnfreezeAccountRequest, opts ...grpc.CallOption) (*UnfreezeAccountResponse, error) {
+	out := new(UnfreezeAccountResponse)
+	err := c.cc.Invoke(ctx, GoBank_UnfreezeAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) AdjustAccountBalance(ctx context.Context, in *AdjustAccountBalanceRequest, opts ...grpc.CallOption) (*AdjustAccountBalanceResponse, error) {
+	out := new(AdjustAccountBalanceResponse)
+	err := c.cc.Invoke(ctx, GoBank_AdjustAccountBalance_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) UpdateOverdraftLimit(ctx context.Context, in *UpdateOverdraftLimitRequest, opts ...grpc.CallOption) (*UpdateOverdraftLimitResponse, error) {
+	out := new(UpdateOverdraftLimitResponse)
+	err := c.cc.Invoke(ctx, GoBank_UpdateOverdraftLimit_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) CreateTransfer(ctx context.Context, in *CreateTransferRequest, opts ...grpc.CallOption) (*CreateTransferResponse, error) {
+	out := new(CreateTransferResponse)
+	err := c.cc.Invoke(ctx, GoBank_CreateTransfer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) ReverseTransfer(ctx context.Context, in *ReverseTransferRequest, opts ...grpc.CallOption) (*ReverseTransferResponse, error) {
+	out := new(ReverseTransferResponse)
+	err := c.cc.Invoke(ctx, GoBank_ReverseTransfer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) ListTransfers(ctx context.Context, in *ListTransfersRequest, opts ...grpc.CallOption) (*ListTransfersResponse, error) {
+	out := new(ListTransfersResponse)
+	err := c.cc.Invoke(ctx, GoBank_ListTransfers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) SearchTransfers(ctx context.Context, in *SearchTransfersRequest, opts ...grpc.CallOption) (*SearchTransfersResponse, error) {
+	out := new(SearchTransfersResponse)
+	err := c.cc.Invoke(ctx, GoBank_SearchTransfers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) ListEntries(ctx context.Context, in *ListEntriesRequest, opts ...grpc.CallOption) (*ListEntriesResponse, error) {
+	out := new(ListEntriesResponse)
+	err := c.cc.Invoke(ctx, GoBank_ListEntries_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) GetAccountStatement(ctx context.Context, in *GetAccountStatementRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error) {
+	out := new(httpbody.HttpBody)
+	err := c.cc.Invoke(ctx, GoBank_GetAccountStatement_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) GetBalanceAsOf(ctx context.Context, in *GetBalanceAsOfRequest, opts ...grpc.CallOption) (*GetBalanceAsOfResponse, error) {
+	out := new(GetBalanceAsOfResponse)
+	err := c.cc.Invoke(ctx, GoBank_GetBalanceAsOf_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) ListBalancesAsOf(ctx context.Context, in *ListBalancesAsOfRequest, opts ...grpc.CallOption) (*ListBalancesAsOfResponse, error) {
+	out := new(ListBalancesAsOfResponse)
+	err := c.cc.Invoke(ctx, GoBank_ListBalancesAsOf_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) LoadExchangeRates(ctx context.Context, in *LoadExchangeRatesRequest, opts ...grpc.CallOption) (*LoadExchangeRatesResponse, error) {
+	out := new(LoadExchangeRatesResponse)
+	err := c.cc.Invoke(ctx, GoBank_LoadExchangeRates_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) RunReconciliation(ctx context.Context, in *RunReconciliationRequest, opts ...grpc.CallOption) (*RunReconciliationResponse, error) {
+	out := new(RunReconciliationResponse)
+	err := c.cc.Invoke(ctx, GoBank_RunReconciliation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) GetReconciliation(ctx context.Context, in *GetReconciliationRequest, opts ...grpc.CallOption) (*GetReconciliationResponse, error) {
+	out := new(GetReconciliationResponse)
+	err := c.cc.Invoke(ctx, GoBank_GetReconciliation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) ListReconciliations(ctx context.Context, in *ListReconciliationsRequest, opts ...grpc.CallOption) (*ListReconciliationsResponse, error) {
+	out := new(ListReconciliationsResponse)
+	err := c.cc.Invoke(ctx, GoBank_ListReconciliations_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBankClient) VerifyEntryChain(ctx context.Context, in *VerifyEntryChainRequest, opts ...grpc.CallOption) (*VerifyEntryChainResponse, error) {
+	out := new(VerifyEntryChainResponse)
+	err := c.cc.Invoke(ctx, GoBank_VerifyEntryChain_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // GoBankServer is the server API for GoBank service.
 // All implementations must embed UnimplementedGoBankServer
 // for forward compatibility
 type GoBankServer interface {
 	CreateUser(context.Context, *CreateUserRequest) (*CreateUserResponse, error)
 	LoginUser(context.Context, *LoginUserRequest) (*LoginUserResponse, error)
+	RenewAccessToken(context.Context, *RenewAccessTokenRequest) (*RenewAccessTokenResponse, error)
+	UpdateUserRole(context.Context, *UpdateUserRoleRequest) (*UpdateUserRoleResponse, error)
+	Logout(context.Context, *LogoutRequest) (*LogoutResponse, error)
+	LogoutAllSessions(context.Context, *LogoutAllSessionsRequest) (*LogoutAllSessionsResponse, error)
+	ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error)
+	RevokeSession(context.Context, *RevokeSessionRequest) (*RevokeSessionResponse, error)
+	RevokeOtherSessions(context.Context, *RevokeOtherSessionsRequest) (*RevokeOtherSessionsResponse, error)
+	CreateAccount(context.Context, *CreateAccountRequest) (*CreateAccountResponse, error)
+	GetAccount(context.Context, *GetAccountRequest) (*GetAccountResponse, error)
+	ListAccounts(context.Context, *ListAccountsRequest) (*ListAccountsResponse, error)
+	CloseAccount(context.Context, *CloseAccountRequest) (*CloseAccountResponse, error)
+	FreezeAccount(context.Context, *FreezeAccountRequest) (*FreezeAccountResponse, error)
+	UnfreezeAccount(context.Context, *UnfreezeAccountRequest) (*UnfreezeAccountResponse, error)
+	AdjustAccountBalance(context.Context, *AdjustAccountBalanceRequest) (*AdjustAccountBalanceResponse, error)
+	UpdateOverdraftLimit(context.Context, *UpdateOverdraftLimitRequest) (*UpdateOverdraftLimitResponse, error)
+	CreateTransfer(context.Context, *CreateTransferRequest) (*CreateTransferResponse, error)
+	ReverseTransfer(context.Context, *ReverseTransferRequest) (*ReverseTransferResponse, error)
+	ListTransfers(context.Context, *ListTransfersRequest) (*ListTransfersResponse, error)
+	SearchTransfers(context.Context, *SearchTransfersRequest) (*SearchTransfersResponse, error)
+	ListEntries(context.Context, *ListEntriesRequest) (*ListEntriesResponse, error)
+	GetAccountStatement(context.Context, *GetAccountStatementRequest) (*httpbody.HttpBody, error)
+	GetBalanceAsOf(context.Context, *GetBalanceAsOfRequest) (*GetBalanceAsOfResponse, error)
+	ListBalancesAsOf(context.Context, *ListBalancesAsOfRequest) (*ListBalancesAsOfResponse, error)
+	LoadExchangeRates(context.Context, *LoadExchangeRatesRequest) (*LoadExchangeRatesResponse, error)
+	RunReconciliation(context.Context, *RunReconciliationRequest) (*RunReconciliationResponse, error)
+	GetReconciliation(context.Context, *GetReconciliationRequest) (*GetReconciliationResponse, error)
+	ListReconciliations(context.Context, *ListReconciliationsRequest) (*ListReconciliationsResponse, error)
+	VerifyEntryChain(context.Context, *VerifyEntryChainRequest) (*VerifyEntryChainResponse, error)
 	mustEmbedUnimplementedGoBankServer()
 }
 
@@ -76,6 +413,90 @@ func (UnimplementedGoBankServer) CreateUser(context.Context, *CreateUserRequest)
 func (UnimplementedGoBankServer) LoginUser(context.Context, *LoginUserRequest) (*LoginUserResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method LoginUser not implemented")
 }
+func (UnimplementedGoBankServer) RenewAccessToken(context.Context, *RenewAccessTokenRequest) (*RenewAccessTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenewAccessToken not implemented")
+}
+func (UnimplementedGoBankServer) UpdateUserRole(context.Context, *UpdateUserRoleRequest) (*UpdateUserRoleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateUserRole not implemented")
+}
+func (UnimplementedGoBankServer) Logout(context.Context, *LogoutRequest) (*LogoutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Logout not implemented")
+}
+func (UnimplementedGoBankServer) LogoutAllSessions(context.Context, *LogoutAllSessionsRequest) (*LogoutAllSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LogoutAllSessions not implemented")
+}
+func (UnimplementedGoBankServer) ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedGoBankServer) RevokeSession(context.Context, *RevokeSessionRequest) (*RevokeSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeSession not implemented")
+}
+func (UnimplementedGoBankServer) RevokeOtherSessions(context.Context, *RevokeOtherSessionsRequest) (*RevokeOtherSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeOtherSessions not implemented")
+}
+func (UnimplementedGoBankServer) CreateAccount(context.Context, *CreateAccountRequest) (*CreateAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAccount not implemented")
+}
+func (UnimplementedGoBankServer) GetAccount(context.Context, *GetAccountRequest) (*GetAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccount not implemented")
+}
+func (UnimplementedGoBankServer) ListAccounts(context.Context, *ListAccountsRequest) (*ListAccountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAccounts not implemented")
+}
+func (UnimplementedGoBankServer) CloseAccount(context.Context, *CloseAccountRequest) (*CloseAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseAccount not implemented")
+}
+func (UnimplementedGoBankServer) FreezeAccount(context.Context, *FreezeAccountRequest) (*FreezeAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FreezeAccount not implemented")
+}
+func (UnimplementedGoBankServer) UnfreezeAccount(context.Context, *UnfreezeAccountRequest) (*UnfreezeAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnfreezeAccount not implemented")
+}
+func (UnimplementedGoBankServer) AdjustAccountBalance(context.Context, *AdjustAccountBalanceRequest) (*AdjustAccountBalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdjustAccountBalance not implemented")
+}
+func (UnimplementedGoBankServer) UpdateOverdraftLimit(context.Context, *UpdateOverdraftLimitRequest) (*UpdateOverdraftLimitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateOverdraftLimit not implemented")
+}
+func (UnimplementedGoBankServer) CreateTransfer(context.Context, *CreateTransferRequest) (*CreateTransferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTransfer not implemented")
+}
+func (UnimplementedGoBankServer) ReverseTransfer(context.Context, *ReverseTransferRequest) (*ReverseTransferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReverseTransfer not implemented")
+}
+func (UnimplementedGoBankServer) ListTransfers(context.Context, *ListTransfersRequest) (*ListTransfersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTransfers not implemented")
+}
+func (UnimplementedGoBankServer) SearchTransfers(context.Context, *SearchTransfersRequest) (*SearchTransfersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchTransfers not implemented")
+}
+func (UnimplementedGoBankServer) ListEntries(context.Context, *ListEntriesRequest) (*ListEntriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEntries not implemented")
+}
+func (UnimplementedGoBankServer) GetAccountStatement(context.Context, *GetAccountStatementRequest) (*httpbody.HttpBody, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountStatement not implemented")
+}
+func (UnimplementedGoBankServer) GetBalanceAsOf(context.Context, *GetBalanceAsOfRequest) (*GetBalanceAsOfResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalanceAsOf not implemented")
+}
+func (UnimplementedGoBankServer) ListBalancesAsOf(context.Context, *ListBalancesAsOfRequest) (*ListBalancesAsOfResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBalancesAsOf not implemented")
+}
+func (UnimplementedGoBankServer) LoadExchangeRates(context.Context, *LoadExchangeRatesRequest) (*LoadExchangeRatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoadExchangeRates not implemented")
+}
+func (UnimplementedGoBankServer) RunReconciliation(context.Context, *RunReconciliationRequest) (*RunReconciliationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunReconciliation not implemented")
+}
+func (UnimplementedGoBankServer) GetReconciliation(context.Context, *GetReconciliationRequest) (*GetReconciliationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReconciliation not implemented")
+}
+func (UnimplementedGoBankServer) ListReconciliations(context.Context, *ListReconciliationsRequest) (*ListReconciliationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListReconciliations not implemented")
+}
+func (UnimplementedGoBankServer) VerifyEntryChain(context.Context, *VerifyEntryChainRequest) (*VerifyEntryChainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyEntryChain not implemented")
+}
 func (UnimplementedGoBankServer) mustEmbedUnimplementedGoBankServer() {}
 
 // UnsafeGoBankServer may be embedded to opt out of forward compatibility for this service.
@@ -125,20 +546,636 @@ func _GoBank_LoginUser_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
-// GoBank_ServiceDesc is the grpc.ServiceDesc for GoBank service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var GoBank_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "pb.GoBank",
-	HandlerType: (*GoBankServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "CreateUser",
-			Handler:    _GoBank_CreateUser_Handler,
-		},
-		{
-			MethodName: "LoginUser",
-			Handler:    _GoBank_LoginUser_Handler,
+func _GoBank_RenewAccessToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenewAccessTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).RenewAccessToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_RenewAccessToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).RenewAccessToken(ctx, req.(*RenewAccessTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_UpdateUserRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).UpdateUserRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_UpdateUserRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).UpdateUserRole(ctx, req.(*UpdateUserRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).Logout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_Logout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).Logout(ctx, req.(*LogoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_LogoutAllSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogoutAllSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).LogoutAllSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_LogoutAllSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).LogoutAllSessions(ctx, req.(*LogoutAllSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_ListSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).ListSessions(ctx, req.(*ListSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_RevokeSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).RevokeSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_RevokeSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).RevokeSession(ctx, req.(*RevokeSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_RevokeOtherSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeOtherSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).RevokeOtherSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_RevokeOtherSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).RevokeOtherSessions(ctx, req.(*RevokeOtherSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_CreateAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).CreateAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_CreateAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).CreateAccount(ctx, req.(*CreateAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_GetAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).GetAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_GetAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).GetAccount(ctx, req.(*GetAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_ListAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).ListAccounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_ListAccounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).ListAccounts(ctx, req.(*ListAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_CloseAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).CloseAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_CloseAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).CloseAccount(ctx, req.(*CloseAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_FreezeAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FreezeAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).FreezeAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_FreezeAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).FreezeAccount(ctx, req.(*FreezeAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_UnfreezeAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnfreezeAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).UnfreezeAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_UnfreezeAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).UnfreezeAccount(ctx, req.(*UnfreezeAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_AdjustAccountBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdjustAccountBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).AdjustAccountBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_AdjustAccountBalance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).AdjustAccountBalance(ctx, req.(*AdjustAccountBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_UpdateOverdraftLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateOverdraftLimitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).UpdateOverdraftLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_UpdateOverdraftLimit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).UpdateOverdraftLimit(ctx, req.(*UpdateOverdraftLimitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_CreateTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).CreateTransfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_CreateTransfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).CreateTransfer(ctx, req.(*CreateTransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_ReverseTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReverseTransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).ReverseTransfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_ReverseTransfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).ReverseTransfer(ctx, req.(*ReverseTransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_ListTransfers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTransfersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).ListTransfers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_ListTransfers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).ListTransfers(ctx, req.(*ListTransfersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_SearchTransfers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchTransfersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).SearchTransfers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_SearchTransfers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).SearchTransfers(ctx, req.(*SearchTransfersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_ListEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEntriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).ListEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_ListEntries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).ListEntries(ctx, req.(*ListEntriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_GetAccountStatement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountStatementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).GetAccountStatement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_GetAccountStatement_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).GetAccountStatement(ctx, req.(*GetAccountStatementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_GetBalanceAsOf_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBalanceAsOfRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).GetBalanceAsOf(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_GetBalanceAsOf_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).GetBalanceAsOf(ctx, req.(*GetBalanceAsOfRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_ListBalancesAsOf_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBalancesAsOfRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).ListBalancesAsOf(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_ListBalancesAsOf_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).ListBalancesAsOf(ctx, req.(*ListBalancesAsOfRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_LoadExchangeRates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoadExchangeRatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).LoadExchangeRates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_LoadExchangeRates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).LoadExchangeRates(ctx, req.(*LoadExchangeRatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_RunReconciliation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RunReconciliationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).RunReconciliation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_RunReconciliation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).RunReconciliation(ctx, req.(*RunReconciliationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_GetReconciliation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReconciliationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).GetReconciliation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_GetReconciliation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).GetReconciliation(ctx, req.(*GetReconciliationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_ListReconciliations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReconciliationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).ListReconciliations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_ListReconciliations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).ListReconciliations(ctx, req.(*ListReconciliationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBank_VerifyEntryChain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyEntryChainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBankServer).VerifyEntryChain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBank_VerifyEntryChain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBankServer).VerifyEntryChain(ctx, req.(*VerifyEntryChainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// GoBank_ServiceDesc is the grpc.ServiceDesc for GoBank service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var GoBank_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.GoBank",
+	HandlerType: (*GoBankServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateUser",
+			Handler:    _GoBank_CreateUser_Handler,
+		},
+		{
+			MethodName: "LoginUser",
+			Handler:    _GoBank_LoginUser_Handler,
+		},
+		{
+			MethodName: "RenewAccessToken",
+			Handler:    _GoBank_RenewAccessToken_Handler,
+		},
+		{
+			MethodName: "UpdateUserRole",
+			Handler:    _GoBank_UpdateUserRole_Handler,
+		},
+		{
+			MethodName: "Logout",
+			Handler:    _GoBank_Logout_Handler,
+		},
+		{
+			MethodName: "LogoutAllSessions",
+			Handler:    _GoBank_LogoutAllSessions_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _GoBank_ListSessions_Handler,
+		},
+		{
+			MethodName: "RevokeSession",
+			Handler:    _GoBank_RevokeSession_Handler,
+		},
+		{
+			MethodName: "RevokeOtherSessions",
+			Handler:    _GoBank_RevokeOtherSessions_Handler,
+		},
+		{
+			MethodName: "CreateAccount",
+			Handler:    _GoBank_CreateAccount_Handler,
+		},
+		{
+			MethodName: "GetAccount",
+			Handler:    _GoBank_GetAccount_Handler,
+		},
+		{
+			MethodName: "ListAccounts",
+			Handler:    _GoBank_ListAccounts_Handler,
+		},
+		{
+			MethodName: "CloseAccount",
+			Handler:    _GoBank_CloseAccount_Handler,
+		},
+		{
+			MethodName: "FreezeAccount",
+			Handler:    _GoBank_FreezeAccount_Handler,
+		},
+		{
+			MethodName: "UnfreezeAccount",
+			Handler:    _GoBank_UnfreezeAccount_Handler,
+		},
+		{
+			MethodName: "AdjustAccountBalance",
+			Handler:    _GoBank_AdjustAccountBalance_Handler,
+		},
+		{
+			MethodName: "UpdateOverdraftLimit",
+			Handler:    _GoBank_UpdateOverdraftLimit_Handler,
+		},
+		{
+			MethodName: "CreateTransfer",
+			Handler:    _GoBank_CreateTransfer_Handler,
+		},
+		{
+			MethodName: "ReverseTransfer",
+			Handler:    _GoBank_ReverseTransfer_Handler,
+		},
+		{
+			MethodName: "ListTransfers",
+			Handler:    _GoBank_ListTransfers_Handler,
+		},
+		{
+			MethodName: "SearchTransfers",
+			Handler:    _GoBank_SearchTransfers_Handler,
+		},
+		{
+			MethodName: "ListEntries",
+			Handler:    _GoBank_ListEntries_Handler,
+		},
+		{
+			MethodName: "GetAccountStatement",
+			Handler:    _GoBank_GetAccountStatement_Handler,
+		},
+		{
+			MethodName: "GetBalanceAsOf",
+			Handler:    _GoBank_GetBalanceAsOf_Handler,
+		},
+		{
+			MethodName: "ListBalancesAsOf",
+			Handler:    _GoBank_ListBalancesAsOf_Handler,
+		},
+		{
+			MethodName: "LoadExchangeRates",
+			Handler:    _GoBank_LoadExchangeRates_Handler,
+		},
+		{
+			MethodName: "RunReconciliation",
+			Handler:    _GoBank_RunReconciliation_Handler,
+		},
+		{
+			MethodName: "GetReconciliation",
+			Handler:    _GoBank_GetReconciliation_Handler,
+		},
+		{
+			MethodName: "ListReconciliations",
+			Handler:    _GoBank_ListReconciliations_Handler,
+		},
+		{
+			MethodName: "VerifyEntryChain",
+			Handler:    _GoBank_VerifyEntryChain_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
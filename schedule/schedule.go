@@ -0,0 +1,47 @@
+// Package schedule runs background jobs once a day at a fixed time after midnight UTC
+package schedule
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// RetryInterval is how long Daily waits before running a failed job again
+const RetryInterval = 5 * time.Minute
+
+// Job is a daily job, now is the time Daily started it
+type Job func(ctx context.Context, now time.Time) error
+
+// Daily runs job right away, catching up on a run missed while the server was down,
+// then every day delay after midnight UTC until ctx is done. A failed run is retried after RetryInterval
+func Daily(ctx context.Context, name string, delay time.Duration, job Job) {
+	next := time.Now()
+	for {
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		now := time.Now().UTC()
+		if err := job(ctx, now); err != nil {
+			log.Printf("Daily %s failed: %v", name, err)
+			next = now.Add(RetryInterval)
+			continue
+		}
+		next = NextRun(now, delay)
+	}
+}
+
+// NextRun returns the first time after now that is delay past a UTC midnight
+func NextRun(now time.Time, delay time.Duration) time.Time {
+	year, month, day := now.UTC().Date()
+	next := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Add(delay)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
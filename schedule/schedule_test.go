@@ -0,0 +1,42 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextRun(t *testing.T) {
+	testCases := []struct {
+		now      time.Time
+		delay    time.Duration
+		expected time.Time
+	}{
+		{
+			now:      time.Date(2026, 1, 31, 0, 5, 0, 0, time.UTC),
+			delay:    10 * time.Minute,
+			expected: time.Date(2026, 1, 31, 0, 10, 0, 0, time.UTC),
+		},
+		{
+			now:      time.Date(2026, 1, 31, 0, 10, 0, 0, time.UTC),
+			delay:    10 * time.Minute,
+			expected: time.Date(2026, 2, 1, 0, 10, 0, 0, time.UTC),
+		},
+		{
+			now:      time.Date(2026, 1, 31, 23, 0, 0, 0, time.UTC),
+			delay:    2 * time.Hour,
+			expected: time.Date(2026, 2, 1, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			// 06:00 at UTC+7 is still January 30 in UTC
+			now:      time.Date(2026, 1, 31, 6, 0, 0, 0, time.FixedZone("UTC+7", 7*60*60)),
+			delay:    time.Hour,
+			expected: time.Date(2026, 1, 31, 1, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, NextRun(tc.now, tc.delay), "%s + %s", tc.now, tc.delay)
+	}
+}
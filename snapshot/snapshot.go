@@ -0,0 +1,41 @@
+// Package snapshot runs the daily balance snapshot job, on demand or on a schedule
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/schedule"
+)
+
+// ErrDayNotOver is returned when asked to snapshot a day that hasn't ended yet
+var ErrDayNotOver = errors.New("day is not over yet")
+
+// Run snapshots the end of day balances of the UTC day date falls on.
+// Entries are stamped with the start of their transaction, so a transfer still committing after midnight
+// belongs to the day before: the store waits for such transactions and gives up with sqlc.ErrDayNotSettled
+func Run(ctx context.Context, store sqlc.Store, date time.Time) (int64, error) {
+	day, cutoff := sqlc.SnapshotDay(date)
+	if !time.Now().After(cutoff) {
+		return 0, fmt.Errorf("%w: %s", ErrDayNotOver, day.Format(time.DateOnly))
+	}
+	return store.SnapshotBalances(ctx, sqlc.SnapshotBalancesParams{Date: day})
+}
+
+// Schedule snapshots the previous day every day at delay after midnight UTC until ctx is done.
+// A day that hasn't settled yet is tried again after schedule.RetryInterval
+func Schedule(ctx context.Context, store sqlc.Store, delay time.Duration) {
+	schedule.Daily(ctx, "balance snapshot", delay, func(ctx context.Context, now time.Time) error {
+		date := now.AddDate(0, 0, -1)
+		written, err := Run(ctx, store, date)
+		if err != nil {
+			return err
+		}
+		log.Printf("Snapshotted %d balances of %s", written, date.Format(time.DateOnly))
+		return nil
+	})
+}
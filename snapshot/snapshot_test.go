@@ -0,0 +1,49 @@
+package snapshot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/October-9th/simple-bank/database/mock"
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	date := time.Date(2026, 1, 31, 15, 4, 5, 0, time.UTC)
+	arg := sqlc.SnapshotBalancesParams{Date: time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)}
+	store.EXPECT().SnapshotBalances(gomock.Any(), gomock.Eq(arg)).Times(1).Return(int64(3), nil)
+
+	written, err := Run(context.Background(), store, date)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), written)
+}
+
+func TestRunDayNotOver(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().SnapshotBalances(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := Run(context.Background(), store, time.Now())
+	require.ErrorIs(t, err, ErrDayNotOver)
+}
+
+func TestRunDayNotSettled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	yesterday := time.Now().UTC().AddDate(0, 0, -1)
+	store.EXPECT().SnapshotBalances(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), sqlc.ErrDayNotSettled)
+
+	_, err := Run(context.Background(), store, yesterday)
+	require.ErrorIs(t, err, sqlc.ErrDayNotSettled)
+}
@@ -0,0 +1,57 @@
+package statement
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
+
+var csvHeader = []string{
+	"date", "entry_id", "type", "transfer_id", "counterparty_account_id", "counterparty_owner",
+	"description", "amount", "balance", "currency", "reverses_transfer_id",
+}
+
+// writeCSV writes one row per entry, framed by an opening and a closing balance row
+func writeCSV(w io.Writer, stmt Statement) error {
+	cw := csv.NewWriter(w)
+
+	rows := [][]string{
+		csvHeader,
+		{stmt.From.UTC().Format(time.RFC3339), "", "opening_balance", "", "", "", "opening balance", "", stmt.OpeningBalance.Decimal(), stmt.Currency, ""},
+	}
+	for _, entry := range stmt.Entries {
+		var transferID, counterpartyID, counterpartyOwner, reversesTransferID string
+		if entry.Counterparty != nil {
+			transferID = strconv.FormatInt(entry.TransferID, 10)
+			counterpartyID = strconv.FormatInt(entry.Counterparty.AccountID, 10)
+			counterpartyOwner = entry.Counterparty.Owner
+		}
+		if entry.ReversesTransferID != 0 {
+			reversesTransferID = strconv.FormatInt(entry.ReversesTransferID, 10)
+		}
+		rows = append(rows, []string{
+			entry.CreatedAt.UTC().Format(time.RFC3339),
+			strconv.FormatInt(entry.ID, 10),
+			entry.Type,
+			transferID,
+			counterpartyID,
+			counterpartyOwner,
+			entry.Description,
+			entry.Amount.Decimal(),
+			entry.Balance.Decimal(),
+			stmt.Currency,
+			reversesTransferID,
+		})
+	}
+	rows = append(rows, []string{stmt.To.UTC().Format(time.RFC3339), "", "closing_balance", "", "", "", "closing balance", "", stmt.ClosingBalance.Decimal(), stmt.Currency, ""})
+
+	return cw.WriteAll(rows)
+}
+
+func writeJSON(w io.Writer, stmt Statement) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(stmt)
+}
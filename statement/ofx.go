@@ -0,0 +1,134 @@
+package statement
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ofxHeader is the processing instruction an OFX 2.x document starts with
+const ofxHeader = xml.Header + `<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n"
+
+// OFX limits NAME to 32 characters
+const ofxMaxNameLength = 32
+
+const ofxBankID = "GOBANK"
+
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+var ofxStatusOK = ofxStatus{Code: 0, Severity: "INFO"}
+
+type ofxDocument struct {
+	XMLName xml.Name `xml:"OFX"`
+	SignOn  struct {
+		Status   ofxStatus `xml:"STATUS"`
+		DTServer string    `xml:"DTSERVER"`
+		Language string    `xml:"LANGUAGE"`
+	} `xml:"SIGNONMSGSRSV1>SONRS"`
+	Statement struct {
+		TrnUID   string    `xml:"TRNUID"`
+		Status   ofxStatus `xml:"STATUS"`
+		Response struct {
+			CurDef  string `xml:"CURDEF"`
+			Account struct {
+				BankID   string `xml:"BANKID"`
+				AcctID   string `xml:"ACCTID"`
+				AcctType string `xml:"ACCTTYPE"`
+			} `xml:"BANKACCTFROM"`
+			TranList struct {
+				DTStart      string           `xml:"DTSTART"`
+				DTEnd        string           `xml:"DTEND"`
+				Transactions []ofxTransaction `xml:"STMTTRN"`
+			} `xml:"BANKTRANLIST"`
+			LedgerBal struct {
+				BalAmt string `xml:"BALAMT"`
+				DTAsOf string `xml:"DTASOF"`
+			} `xml:"LEDGERBAL"`
+		} `xml:"STMTRS"`
+	} `xml:"BANKMSGSRSV1>STMTTRNRS"`
+}
+
+type ofxTransaction struct {
+	TrnType  string `xml:"TRNTYPE"`
+	DTPosted string `xml:"DTPOSTED"`
+	TrnAmt   string `xml:"TRNAMT"`
+	FITID    string `xml:"FITID"`
+	Name     string `xml:"NAME,omitempty"`
+	Memo     string `xml:"MEMO"`
+}
+
+// writeOFX writes a bank statement response. OFX has no opening balance element,
+// the closing balance is reported as the ledger balance at the end of the period
+func writeOFX(w io.Writer, stmt Statement) error {
+	var doc ofxDocument
+	doc.SignOn.Status = ofxStatusOK
+	doc.SignOn.DTServer = ofxTime(stmt.GeneratedAt)
+	doc.SignOn.Language = "ENG"
+
+	doc.Statement.TrnUID = "0"
+	doc.Statement.Status = ofxStatusOK
+
+	rs := &doc.Statement.Response
+	rs.CurDef = stmt.Currency
+	rs.Account.BankID = ofxBankID
+	rs.Account.AcctID = strconv.FormatInt(stmt.AccountID, 10)
+	rs.Account.AcctType = "CHECKING"
+	rs.TranList.DTStart = ofxTime(stmt.From)
+	rs.TranList.DTEnd = ofxTime(stmt.To)
+	rs.LedgerBal.BalAmt = stmt.ClosingBalance.Decimal()
+	rs.LedgerBal.DTAsOf = ofxTime(stmt.To)
+
+	rs.TranList.Transactions = make([]ofxTransaction, 0, len(stmt.Entries))
+	for _, entry := range stmt.Entries {
+		trnType := "CREDIT"
+		if entry.Amount.IsNegative() {
+			trnType = "DEBIT"
+		}
+		if entry.Type == EntryTransfer {
+			trnType = "XFER"
+		}
+
+		var name string
+		if entry.Counterparty != nil {
+			name = truncate(entry.Counterparty.Owner, ofxMaxNameLength)
+		}
+
+		rs.TranList.Transactions = append(rs.TranList.Transactions, ofxTransaction{
+			TrnType:  trnType,
+			DTPosted: ofxTime(entry.CreatedAt),
+			TrnAmt:   entry.Amount.Decimal(),
+			FITID:    strconv.FormatInt(entry.ID, 10),
+			Name:     name,
+			Memo:     entry.Description,
+		})
+	}
+
+	if _, err := io.WriteString(w, ofxHeader); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// ofxTime formats t as an OFX datetime in UTC, e.g. 20260131235959.000[0:GMT]
+func ofxTime(t time.Time) string {
+	return t.UTC().Format("20060102150405.000") + "[0:GMT]"
+}
+
+// truncate cuts s to at most n characters without splitting a multi-byte character
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -0,0 +1,181 @@
+// Package statement builds account statements from the ledger and renders them as CSV, JSON or OFX 2.x
+package statement
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/money"
+)
+
+// Supported statement formats
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+	FormatOFX  = "ofx"
+)
+
+// Entry types
+const (
+	EntryTransfer   = "transfer"
+	EntryAdjustment = "adjustment"
+)
+
+// ErrUnknownFormat is returned when a statement is requested in a format other than csv, json or ofx
+var ErrUnknownFormat = errors.New("unknown statement format")
+
+// IsSupportedFormat reports whether format is one of csv, json or ofx
+func IsSupportedFormat(format string) bool {
+	switch format {
+	case FormatCSV, FormatJSON, FormatOFX:
+		return true
+	}
+	return false
+}
+
+// ContentType returns the media type a statement in format is served with
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatJSON:
+		return "application/json; charset=utf-8"
+	case FormatOFX:
+		return "application/x-ofx"
+	}
+	return "application/octet-stream"
+}
+
+// FileName suggests a file name for stmt rendered in format, e.g. statement-7-20260101-20260201.csv
+func FileName(stmt Statement, format string) string {
+	return fmt.Sprintf("statement-%d-%s-%s.%s", stmt.AccountID, stmt.From.UTC().Format("20060102"), stmt.To.UTC().Format("20060102"), format)
+}
+
+// Counterparty is the other account of a transfer
+type Counterparty struct {
+	AccountID int64  `json:"account_id"`
+	Owner     string `json:"owner"`
+	Currency  string `json:"currency"`
+}
+
+// Entry is a single ledger entry of the statement
+type Entry struct {
+	ID                 int64         `json:"id"`
+	Type               string        `json:"type"`
+	TransferID         int64         `json:"transfer_id,omitempty"`
+	ReversesTransferID int64         `json:"reverses_transfer_id,omitempty"` // only set for reversals, the transfer they give money back for
+	Counterparty       *Counterparty `json:"counterparty,omitempty"`
+	ExchangeRate       string        `json:"exchange_rate,omitempty"` // only set when the counterparty uses another currency
+	Memo               string        `json:"memo,omitempty"`
+	Description        string        `json:"description"`
+	Amount             money.Amount  `json:"amount"`
+	Balance            money.Amount  `json:"balance"` // the balance right after this entry
+	CreatedAt          time.Time     `json:"created_at"`
+}
+
+// Statement is the history of an account between From, included, and To, excluded
+type Statement struct {
+	AccountID      int64        `json:"account_id"`
+	Owner          string       `json:"owner"`
+	Currency       string       `json:"currency"`
+	From           time.Time    `json:"from"`
+	To             time.Time    `json:"to"`
+	OpeningBalance money.Amount `json:"opening_balance"`
+	ClosingBalance money.Amount `json:"closing_balance"`
+	Entries        []Entry      `json:"entries"`
+	GeneratedAt    time.Time    `json:"generated_at"`
+}
+
+// New builds a statement from the result of StatementTx, generatedAt is stamped on the rendered document
+func New(result sqlc.StatementTxResult, from, to, generatedAt time.Time) (Statement, error) {
+	currency := result.Account.Currency
+
+	opening, err := money.New(result.OpeningBalance, currency)
+	if err != nil {
+		return Statement{}, err
+	}
+
+	stmt := Statement{
+		AccountID:      result.Account.ID,
+		Owner:          result.Account.Owner,
+		Currency:       currency,
+		From:           from,
+		To:             to,
+		OpeningBalance: opening,
+		Entries:        make([]Entry, 0, len(result.Entries)),
+		GeneratedAt:    generatedAt,
+	}
+
+	balance := opening
+	for _, row := range result.Entries {
+		amount := money.Amount{Minor: row.Amount, Currency: currency}
+		if balance, err = balance.Add(amount); err != nil {
+			return Statement{}, err
+		}
+		entry := newEntry(row, currency)
+		entry.Amount = amount
+		entry.Balance = balance
+		stmt.Entries = append(stmt.Entries, entry)
+	}
+	stmt.ClosingBalance = balance
+	return stmt, nil
+}
+
+func newEntry(row sqlc.ListStatementEntriesRow, currency string) Entry {
+	entry := Entry{
+		ID:        row.ID,
+		CreatedAt: row.CreatedAt,
+	}
+
+	if !row.TransferID.Valid {
+		entry.Type = EntryAdjustment
+		entry.Description = "balance adjustment"
+		if row.AdjustmentReason.Valid {
+			entry.Description += ": " + row.AdjustmentReason.String
+		}
+		return entry
+	}
+
+	entry.Type = EntryTransfer
+	entry.TransferID = row.TransferID.Int64
+	entry.Counterparty = &Counterparty{
+		AccountID: row.CounterpartyAccountID.Int64,
+		Owner:     row.CounterpartyOwner.String,
+		Currency:  row.CounterpartyCurrency.String,
+	}
+	if entry.Counterparty.Currency != currency {
+		entry.ExchangeRate = row.ExchangeRate.String
+	}
+
+	direction := "from"
+	if row.Amount < 0 {
+		direction = "to"
+	}
+	entry.Description = fmt.Sprintf("transfer %s account %d (%s)", direction, entry.Counterparty.AccountID, entry.Counterparty.Owner)
+	if row.ReversesTransferID.Valid {
+		entry.ReversesTransferID = row.ReversesTransferID.Int64
+		entry.Description = fmt.Sprintf("reversal of transfer %d, %s", entry.ReversesTransferID, entry.Description)
+	}
+	// The memo says why the money moved, the reversal's own memo only repeats the link
+	if row.Memo.Valid && row.Memo.String != "" && !row.ReversesTransferID.Valid {
+		entry.Memo = row.Memo.String
+		entry.Description += ": " + entry.Memo
+	}
+	return entry
+}
+
+// Write renders stmt to w in format
+func Write(w io.Writer, format string, stmt Statement) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, stmt)
+	case FormatJSON:
+		return writeJSON(w, stmt)
+	case FormatOFX:
+		return writeOFX(w, stmt)
+	}
+	return fmt.Errorf("%w %q", ErrUnknownFormat, format)
+}
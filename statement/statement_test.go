@@ -0,0 +1,230 @@
+package statement
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/October-9th/simple-bank/database/sqlc"
+	"github.com/October-9th/simple-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testFrom = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	testTo   = time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func testStatement(t *testing.T) Statement {
+	result := sqlc.StatementTxResult{
+		Account: sqlc.Account{
+			ID:       7,
+			Owner:    "alice",
+			Currency: util.USD,
+		},
+		OpeningBalance: 10000,
+		ClosingBalance: 10000 - 2550 + 1000 + 300,
+		Entries: []sqlc.ListStatementEntriesRow{
+			{
+				ID:                    21,
+				AccountID:             7,
+				Amount:                -2550,
+				CreatedAt:             testFrom.Add(time.Hour),
+				TransferID:            sql.NullInt64{Int64: 11, Valid: true},
+				ExchangeRate:          sql.NullString{String: "1.0000000000", Valid: true},
+				CounterpartyAccountID: sql.NullInt64{Int64: 8, Valid: true},
+				CounterpartyOwner:     sql.NullString{String: "bob", Valid: true},
+				CounterpartyCurrency:  sql.NullString{String: util.USD, Valid: true},
+			},
+			{
+				ID:                    24,
+				AccountID:             7,
+				Amount:                1000,
+				CreatedAt:             testFrom.Add(2 * time.Hour),
+				TransferID:            sql.NullInt64{Int64: 12, Valid: true},
+				ExchangeRate:          sql.NullString{String: "1.0870000000", Valid: true},
+				Memo:                  sql.NullString{String: "dinner", Valid: true},
+				CounterpartyAccountID: sql.NullInt64{Int64: 9, Valid: true},
+				CounterpartyOwner:     sql.NullString{String: "carol", Valid: true},
+				CounterpartyCurrency:  sql.NullString{String: util.EUR, Valid: true},
+			},
+			{
+				ID:               25,
+				AccountID:        7,
+				Amount:           300,
+				CreatedAt:        testFrom.Add(3 * time.Hour),
+				AdjustmentReason: sql.NullString{String: "fee refund", Valid: true},
+			},
+		},
+	}
+
+	stmt, err := New(result, testFrom, testTo, testTo)
+	require.NoError(t, err)
+	return stmt
+}
+
+func TestNew(t *testing.T) {
+	stmt := testStatement(t)
+
+	require.Equal(t, int64(10000), stmt.OpeningBalance.Minor)
+	require.Equal(t, int64(8750), stmt.ClosingBalance.Minor)
+	require.Len(t, stmt.Entries, 3)
+
+	transferOut := stmt.Entries[0]
+	require.Equal(t, EntryTransfer, transferOut.Type)
+	require.Equal(t, int64(11), transferOut.TransferID)
+	require.Equal(t, int64(8), transferOut.Counterparty.AccountID)
+	require.Equal(t, "bob", transferOut.Counterparty.Owner)
+	require.Empty(t, transferOut.ExchangeRate)
+	require.Equal(t, "transfer to account 8 (bob)", transferOut.Description)
+	require.Equal(t, int64(7450), transferOut.Balance.Minor)
+
+	transferIn := stmt.Entries[1]
+	require.Equal(t, "1.0870000000", transferIn.ExchangeRate)
+	require.Equal(t, "dinner", transferIn.Memo)
+	require.Equal(t, "transfer from account 9 (carol): dinner", transferIn.Description)
+	require.Equal(t, int64(8450), transferIn.Balance.Minor)
+
+	adjustment := stmt.Entries[2]
+	require.Equal(t, EntryAdjustment, adjustment.Type)
+	require.Nil(t, adjustment.Counterparty)
+	require.Equal(t, "balance adjustment: fee refund", adjustment.Description)
+	require.Equal(t, int64(8750), adjustment.Balance.Minor)
+}
+
+func TestNewReversal(t *testing.T) {
+	result := sqlc.StatementTxResult{
+		Account:        sqlc.Account{ID: 7, Owner: "alice", Currency: util.USD},
+		OpeningBalance: 500,
+		ClosingBalance: 600,
+		Entries: []sqlc.ListStatementEntriesRow{
+			{
+				ID:                    30,
+				AccountID:             7,
+				Amount:                100,
+				CreatedAt:             testFrom.Add(time.Hour),
+				TransferID:            sql.NullInt64{Int64: 14, Valid: true},
+				ExchangeRate:          sql.NullString{String: "1", Valid: true},
+				ReversesTransferID:    sql.NullInt64{Int64: 11, Valid: true},
+				CounterpartyAccountID: sql.NullInt64{Int64: 8, Valid: true},
+				CounterpartyOwner:     sql.NullString{String: "bob", Valid: true},
+				CounterpartyCurrency:  sql.NullString{String: util.USD, Valid: true},
+			},
+		},
+	}
+
+	stmt, err := New(result, testFrom, testTo, testTo)
+	require.NoError(t, err)
+	require.Len(t, stmt.Entries, 1)
+
+	reversal := stmt.Entries[0]
+	require.Equal(t, EntryTransfer, reversal.Type)
+	require.Equal(t, int64(14), reversal.TransferID)
+	require.Equal(t, int64(11), reversal.ReversesTransferID)
+	require.Equal(t, "reversal of transfer 11, transfer from account 8 (bob)", reversal.Description)
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatCSV, stmt))
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, "11", rows[2][len(csvHeader)-1])
+}
+
+func TestNewEmptyPeriod(t *testing.T) {
+	result := sqlc.StatementTxResult{
+		Account:        sqlc.Account{ID: 7, Owner: "alice", Currency: util.USD},
+		OpeningBalance: 500,
+		ClosingBalance: 500,
+		Entries:        []sqlc.ListStatementEntriesRow{},
+	}
+
+	stmt, err := New(result, testFrom, testTo, testTo)
+	require.NoError(t, err)
+	require.Equal(t, stmt.OpeningBalance, stmt.ClosingBalance)
+	require.Empty(t, stmt.Entries)
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatCSV, testStatement(t)))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 6)
+
+	require.Equal(t, csvHeader, rows[0])
+	require.Equal(t, "opening_balance", rows[1][2])
+	require.Equal(t, "100.00", rows[1][8])
+	require.Equal(t, []string{
+		"2026-01-01T01:00:00Z", "21", "transfer", "11", "8", "bob",
+		"transfer to account 8 (bob)", "-25.50", "74.50", "USD", "",
+	}, rows[2])
+	require.Equal(t, "adjustment", rows[4][2])
+	require.Empty(t, rows[4][3])
+	require.Equal(t, "closing_balance", rows[5][2])
+	require.Equal(t, "87.50", rows[5][8])
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatJSON, testStatement(t)))
+
+	var decoded Statement
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Equal(t, testStatement(t), decoded)
+	require.Contains(t, buf.String(), `"closing_balance": {`)
+}
+
+func TestWriteOFX(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatOFX, testStatement(t)))
+
+	output := buf.String()
+	require.True(t, strings.HasPrefix(output, xml.Header))
+	require.Contains(t, output, `<?OFX OFXHEADER="200" VERSION="220"`)
+
+	// The processing instructions are skipped by the decoder
+	var doc ofxDocument
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+
+	rs := doc.Statement.Response
+	require.Equal(t, util.USD, rs.CurDef)
+	require.Equal(t, "7", rs.Account.AcctID)
+	require.Equal(t, "20260101000000.000[0:GMT]", rs.TranList.DTStart)
+	require.Equal(t, "87.50", rs.LedgerBal.BalAmt)
+
+	require.Len(t, rs.TranList.Transactions, 3)
+	require.Equal(t, ofxTransaction{
+		TrnType:  "XFER",
+		DTPosted: "20260101010000.000[0:GMT]",
+		TrnAmt:   "-25.50",
+		FITID:    "21",
+		Name:     "bob",
+		Memo:     "transfer to account 8 (bob)",
+	}, rs.TranList.Transactions[0])
+	require.Equal(t, "CREDIT", rs.TranList.Transactions[2].TrnType)
+	require.Empty(t, rs.TranList.Transactions[2].Name)
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	var buf bytes.Buffer
+	err := Write(&buf, "pdf", testStatement(t))
+	require.ErrorIs(t, err, ErrUnknownFormat)
+	require.False(t, IsSupportedFormat("pdf"))
+	require.True(t, IsSupportedFormat(FormatOFX))
+}
+
+func TestTruncate(t *testing.T) {
+	require.Equal(t, "abc", truncate("abc", 32))
+	require.Equal(t, "ab", truncate("abc", 2))
+	require.Equal(t, "żó", truncate("żółw", 2))
+}
+
+func TestFileName(t *testing.T) {
+	require.Equal(t, "statement-7-20260101-20260201.ofx", FileName(testStatement(t), FormatOFX))
+}
@@ -1,75 +1,255 @@
 package token
 
 import (
+	"crypto/rsa"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 const minSecretKeySize = 32
 
+// minRSAKeySize is the smallest RSA modulus accepted, in bits
+const minRSAKeySize = 2048
+
+// Signing algorithms of the JWT makers. Each maker accepts only its own, so a token can't pick how it is verified
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// JWTOptions are the checks made on the tokens besides their signature
+type JWTOptions struct {
+	// Issuer is the iss of the tokens created, verified tokens must have the same one. Empty skips the check
+	Issuer string
+	// Audience is the aud of the tokens created, verified tokens must include it. Empty skips the check
+	Audience string
+	// ClockSkew is tolerated when checking exp, nbf and iat against the clock
+	ClockSkew time.Duration
+}
+
+// jwtClaims is the claims set of the tokens, the registered claims and the rest of the payload.
+// Scopes are a space separated list, like the scope claim of RFC 8693, and the session is the sid claim of OpenID Connect
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	Role      string    `json:"role"`
+	TokenType TokenType `json:"token_type"`
+	Scope     string    `json:"scope,omitempty"`
+	SessionID string    `json:"sid,omitempty"`
+}
+
 // JWTMaker is a JSON web token maker
 type JWTMaker struct {
-	secretKey string
+	method  jwt.SigningMethod
+	options JWTOptions
+	// signingKey returns the key id to put in the header and the key to sign with, it is nil for a verifier
+	signingKey func() (keyID string, key any, err error)
+	// verificationKey returns the key checking the signature of a token, the header is checked by the parser first
+	verificationKey jwt.Keyfunc
 }
 
-// NewJWTMaker create a new JWTMaker
-func NewJWTMaker(secretKey string) (Maker, error) {
+// NewHS256JWTMaker creates a JWTMaker signing and verifying with a shared secret
+func NewHS256JWTMaker(secretKey string, options JWTOptions) (Maker, error) {
 	if len(secretKey) < minSecretKeySize {
 		return nil, fmt.Errorf("invalid key size: %d, must be at least %d characters", len(secretKey), minSecretKeySize)
 	}
 
-	return &JWTMaker{secretKey}, nil
+	key := []byte(secretKey)
+	return &JWTMaker{
+		method:  jwt.SigningMethodHS256,
+		options: options,
+		signingKey: func() (string, any, error) {
+			return "", key, nil
+		},
+		verificationKey: func(*jwt.Token) (any, error) {
+			return key, nil
+		},
+	}, nil
 }
 
-func (maker *JWTMaker) CreateToken(username string, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewPayLoad(username, duration)
-	if err != nil {
-		return "", payload, err
+// NewRS256JWTMaker creates a JWTMaker signing with privateKey and verifying with its public key.
+// A nil privateKey makes a maker that only verifies tokens with publicKey
+func NewRS256JWTMaker(privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey, options JWTOptions) (Maker, error) {
+	if privateKey != nil {
+		publicKey = &privateKey.PublicKey
+	}
+	if publicKey == nil {
+		return nil, fmt.Errorf("a public or private key is required")
+	}
+	if publicKey.N.BitLen() < minRSAKeySize {
+		return nil, fmt.Errorf("invalid key size: %d bits, must be at least %d bits", publicKey.N.BitLen(), minRSAKeySize)
 	}
 
-	// Create token
-	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
-	token, err := jwtToken.SignedString([]byte(maker.secretKey))
-	return token, payload, err
+	maker := &JWTMaker{
+		method:  jwt.SigningMethodRS256,
+		options: options,
+		verificationKey: func(*jwt.Token) (any, error) {
+			return publicKey, nil
+		},
+	}
+	if privateKey != nil {
+		maker.signingKey = func() (string, any, error) {
+			return "", privateKey, nil
+		}
+	}
+	return maker, nil
 }
 
-func (maker *JWTMaker) VerifyToken(token string) (*Payload, error) {
+// NewEdDSAJWTMaker creates a JWTMaker signing with the active key of ring, named by the kid header,
+// and verifying with any of its keys. A ring without a signing key makes a maker that only verifies tokens
+func NewEdDSAJWTMaker(ring *KeyRing, options JWTOptions) (Maker, error) {
+	if ring == nil || len(ring.PublicKeys()) == 0 {
+		return nil, fmt.Errorf("key ring must contain at least one key")
+	}
 
-	// KeyFunc recevied parsed but unsigh token and then verify its header
-	// to check if its matches the signing algorithm used to sign the token
-	keyFunc := func(token *jwt.Token) (interface{}, error) {
-		// Get the signing algorithm
-		_, ok := token.Method.(*jwt.SigningMethodHMAC)
+	maker := NewEdDSAJWTVerifier(ring, options).(*JWTMaker)
+	maker.signingKey = func() (string, any, error) {
+		keyID, privateKey, err := ring.signingKeyPair()
+		return keyID, privateKey, err
+	}
+	return maker, nil
+}
 
-		// The signing algorithm doesn't match the the token algorithm
-		if !ok {
-			return nil, ErrInvalidToken
-		}
+// NewEdDSAJWTVerifier creates a JWTMaker that only verifies tokens, with the public keys of keys,
+// e.g. those published by the server and fetched by a jwks.Client
+func NewEdDSAJWTVerifier(keys KeySource, options JWTOptions) Maker {
+	return &JWTMaker{
+		method:  jwt.SigningMethodEdDSA,
+		options: options,
+		verificationKey: func(token *jwt.Token) (any, error) {
+			keyID, _ := token.Header["kid"].(string)
+			publicKey, err := keys.PublicKey(keyID)
+			if err != nil {
+				return nil, err
+			}
+			return publicKey, nil
+		},
+	}
+}
 
-		// Else return the secret key that used to sign the token
-		return []byte(maker.secretKey), nil
+func (maker *JWTMaker) CreateToken(username string, role string, duration time.Duration, options ...Option) (string, *Payload, error) {
+	payload, err := NewPayLoad(username, role, duration, options...)
+	if err != nil {
+		return "", payload, err
+	}
+	if maker.signingKey == nil {
+		return "", payload, ErrNoSigningKey
+	}
+	keyID, key, err := maker.signingKey()
+	if err != nil {
+		return "", payload, err
 	}
 
-	// Parse token
-	jwtToken, err := jwt.ParseWithClaims(token, &Payload{}, keyFunc)
+	// The issuer and audience of the maker unless the token has its own
+	if payload.Issuer == "" {
+		payload.Issuer = maker.options.Issuer
+	}
+	if len(payload.Audience) == 0 && maker.options.Audience != "" {
+		payload.Audience = []string{maker.options.Audience}
+	}
+
+	jwtToken := jwt.NewWithClaims(maker.method, newJWTClaims(payload))
+	if keyID != "" {
+		jwtToken.Header["kid"] = keyID
+	}
+	token, err := jwtToken.SignedString(key)
+	return token, payload, err
+}
+
+func (maker *JWTMaker) VerifyToken(token string, tokenType TokenType, scopes ...string) (*Payload, error) {
+	// Only the algorithm of the maker is accepted: "none", or a public key used as an HMAC secret, never verify
+	parserOptions := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{maker.method.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(maker.options.ClockSkew),
+	}
+	if maker.options.Issuer != "" {
+		parserOptions = append(parserOptions, jwt.WithIssuer(maker.options.Issuer))
+	}
+	if maker.options.Audience != "" {
+		parserOptions = append(parserOptions, jwt.WithAudience(maker.options.Audience))
+	}
+
+	claims := &jwtClaims{}
+	_, err := jwt.NewParser(parserOptions...).ParseWithClaims(token, claims, maker.keyFunc)
 	if err != nil {
-		// Figure the error type converting the error to the jwt.ValidationError
-		verr, ok := err.(*jwt.ValidationError)
-		// if the convertion is ok
-		if ok && errors.Is(verr.Inner, ErrExpiredToken) {
+		if errors.Is(err, jwt.ErrTokenExpired) {
 			return nil, ErrExpiredToken
 		}
 		return nil, ErrInvalidToken
 	}
 
-	// converting jwt.Token.Claims into payload obj
-	payload, ok := jwtToken.Claims.(*Payload)
-	if !ok {
-		return nil, ErrInvalidToken
+	payload, err := claims.payload()
+	if err != nil {
+		return nil, err
+	}
+	if err := payload.Check(tokenType, scopes...); err != nil {
+		return nil, err
 	}
 	return payload, nil
+}
+
+// keyFunc returns the key verifying token. No header extension is supported,
+// so a token with some that must be understood is refused rather than verified without them
+func (maker *JWTMaker) keyFunc(token *jwt.Token) (any, error) {
+	if _, ok := token.Header["crit"]; ok {
+		return nil, fmt.Errorf("unsupported critical headers")
+	}
+	return maker.verificationKey(token)
+}
 
+// newJWTClaims returns the claims of payload: the token id is the jti, the username the sub,
+// and the token is valid from the time it was issued
+func newJWTClaims(payload *Payload) jwtClaims {
+	claims := jwtClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        payload.ID.String(),
+			Subject:   payload.Username,
+			Issuer:    payload.Issuer,
+			Audience:  payload.Audience,
+			IssuedAt:  jwt.NewNumericDate(payload.IssuedAt),
+			NotBefore: jwt.NewNumericDate(payload.IssuedAt),
+			ExpiresAt: jwt.NewNumericDate(payload.ExpiredAt),
+		},
+		Role:      payload.Role,
+		TokenType: payload.Type,
+		Scope:     strings.Join(payload.Scopes, " "),
+	}
+	if payload.SessionID != uuid.Nil {
+		claims.SessionID = payload.SessionID.String()
+	}
+	return claims
+}
+
+// payload returns the payload of verified claims
+func (claims *jwtClaims) payload() (*Payload, error) {
+	tokenID, err := uuid.Parse(claims.ID)
+	if err != nil || claims.Subject == "" || claims.IssuedAt == nil {
+		return nil, ErrInvalidToken
+	}
+	var sessionID uuid.UUID
+	if claims.SessionID != "" {
+		sessionID, err = uuid.Parse(claims.SessionID)
+		if err != nil {
+			return nil, ErrInvalidToken
+		}
+	}
+	return &Payload{
+		ID:        tokenID,
+		Username:  claims.Subject,
+		Role:      claims.Role,
+		Type:      claims.TokenType,
+		Issuer:    claims.Issuer,
+		Audience:  claims.Audience,
+		Scopes:    strings.Fields(claims.Scope),
+		SessionID: sessionID,
+		IssuedAt:  claims.IssuedAt.Time,
+		ExpiredAt: claims.ExpiresAt.Time,
+	}, nil
 }
@@ -1,71 +1,247 @@
 package token
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/October-9th/simple-bank/util"
-	"github.com/dgrijalva/jwt-go"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
 func TestJWTMaker(t *testing.T) {
-	maker, err := NewJWTMaker(util.RandomString(32))
+	maker, err := NewHS256JWTMaker(util.RandomString(32), JWTOptions{})
 	require.NoError(t, err)
 
 	username := util.RandomOwner()
+	role := util.DepositorRole
 	duration := time.Minute
 
 	issuedAt := time.Now()
 	expiredAt := issuedAt.Add(duration)
 
-	token, payload, err := maker.CreateToken(username, duration)
+	token, payload, err := maker.CreateToken(username, role, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
 
-	payload, err = maker.VerifyToken(token)
+	payload, err = maker.VerifyToken(token, TokenTypeAccess)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 
 	require.NotZero(t, payload.ID)
 	require.Equal(t, username, payload.Username)
+	require.Equal(t, role, payload.Role)
 	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
 	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
 
 }
 
 func TestExpiredJWTMaker(t *testing.T) {
-	maker, err := NewJWTMaker(util.RandomString(32))
+	maker, err := NewHS256JWTMaker(util.RandomString(32), JWTOptions{})
 	require.NoError(t, err)
 
 	username := util.RandomOwner()
+	role := util.DepositorRole
 	duration := time.Minute
 
-	token, payload, err := maker.CreateToken(username, -duration)
+	token, payload, err := maker.CreateToken(username, role, -duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
 
-	payload, err = maker.VerifyToken(token)
+	payload, err = maker.VerifyToken(token, TokenTypeAccess)
 	require.Error(t, err)
 	require.EqualError(t, err, ErrExpiredToken.Error())
 	require.Nil(t, payload)
 
 }
 
-func TestInvalidTokenJWTMaker(t *testing.T) {
-	payload, err := NewPayLoad(util.RandomOwner(), time.Minute)
+// signedToken signs claims with method and key whatever the maker would do, header fields are added to the header
+func signedToken(t *testing.T, method jwt.SigningMethod, claims jwtClaims, key any, header map[string]any) string {
+	jwtToken := jwt.NewWithClaims(method, claims)
+	for name, value := range header {
+		jwtToken.Header[name] = value
+	}
+	token, err := jwtToken.SignedString(key)
 	require.NoError(t, err)
+	return token
+}
 
-	jwtToken := jwt.NewWithClaims(jwt.SigningMethodNone, payload)
-	token, err := jwtToken.SignedString(jwt.UnsafeAllowNoneSignatureType)
-	require.NoError(t, err)
-	maker, err := NewJWTMaker(util.RandomString(32))
+func testClaims(issuedAt time.Time, duration time.Duration) jwtClaims {
+	return jwtClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   util.RandomOwner(),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(issuedAt.Add(duration)),
+		},
+		Role:      util.DepositorRole,
+		TokenType: TokenTypeAccess,
+	}
+}
+
+func TestInvalidTokenJWTMaker(t *testing.T) {
+	token := signedToken(t, jwt.SigningMethodNone, testClaims(time.Now(), time.Minute), jwt.UnsafeAllowNoneSignatureType, nil)
+	maker, err := NewHS256JWTMaker(util.RandomString(32), JWTOptions{})
 	require.NoError(t, err)
 
-	payload, err = maker.VerifyToken(token)
+	payload, err := maker.VerifyToken(token, TokenTypeAccess)
 	require.Error(t, err)
 	require.EqualError(t, err, ErrInvalidToken.Error())
 	require.Nil(t, payload)
 }
+
+func TestJWTMakerClaims(t *testing.T) {
+	secret := util.RandomString(32)
+	options := JWTOptions{Issuer: "go-bank", Audience: "partners", ClockSkew: 30 * time.Second}
+	maker, err := NewHS256JWTMaker(secret, options)
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+	_, err = maker.VerifyToken(token, TokenTypeAccess)
+	require.NoError(t, err)
+
+	claims := jwt.MapClaims{}
+	_, _, err = jwt.NewParser().ParseUnverified(token, claims)
+	require.NoError(t, err)
+	require.Equal(t, "go-bank", claims["iss"])
+	require.Equal(t, []any{"partners"}, claims["aud"])
+	for _, name := range []string{"jti", "sub", "iat", "nbf", "exp", "role"} {
+		require.Contains(t, claims, name)
+	}
+
+	now := time.Now()
+	valid := func(claims jwtClaims) jwtClaims {
+		claims.Issuer = options.Issuer
+		claims.Audience = jwt.ClaimStrings{"other", options.Audience}
+		return claims
+	}
+	hs256Token := func(claims jwtClaims, header map[string]any) string {
+		return signedToken(t, jwt.SigningMethodHS256, claims, []byte(secret), header)
+	}
+
+	accepted := map[string]jwtClaims{
+		"AudienceList": valid(testClaims(now, time.Minute)),
+		// Within the clock skew
+		"ExpiredWithinSkew": valid(testClaims(now.Add(-time.Minute-10*time.Second), time.Minute)),
+		"IssuedWithinSkew":  valid(testClaims(now.Add(10*time.Second), time.Minute)),
+	}
+	for name, claims := range accepted {
+		_, err := maker.VerifyToken(hs256Token(claims, nil), TokenTypeAccess)
+		require.NoError(t, err, name)
+	}
+
+	notBefore := valid(testClaims(now, time.Hour))
+	notBefore.NotBefore = jwt.NewNumericDate(now.Add(time.Minute))
+	noExpiry := valid(testClaims(now, time.Minute))
+	noExpiry.ExpiresAt = nil
+	otherIssuer := valid(testClaims(now, time.Minute))
+	otherIssuer.Issuer = "evil"
+	otherAudience := valid(testClaims(now, time.Minute))
+	otherAudience.Audience = jwt.ClaimStrings{"other"}
+	noAudience := valid(testClaims(now, time.Minute))
+	noAudience.Audience = nil
+
+	rejected := map[string]jwtClaims{
+		"NotYetValid":    notBefore,
+		"IssuedInFuture": valid(testClaims(now.Add(time.Minute), time.Minute)),
+		"NoExpiry":       noExpiry,
+		"OtherIssuer":    otherIssuer,
+		"OtherAudience":  otherAudience,
+		"NoAudience":     noAudience,
+	}
+	for name, claims := range rejected {
+		_, err := maker.VerifyToken(hs256Token(claims, nil), TokenTypeAccess)
+		require.ErrorIs(t, err, ErrInvalidToken, name)
+	}
+
+	_, err = maker.VerifyToken(hs256Token(valid(testClaims(now.Add(-time.Hour), time.Minute)), nil), TokenTypeAccess)
+	require.ErrorIs(t, err, ErrExpiredToken)
+
+	critical := map[string]any{"crit": []string{"exp"}}
+	_, err = maker.VerifyToken(hs256Token(valid(testClaims(now, time.Minute)), critical), TokenTypeAccess)
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestRS256JWTMaker(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	maker, err := NewRS256JWTMaker(privateKey, nil, JWTOptions{})
+	require.NoError(t, err)
+
+	username := util.RandomOwner()
+	token, _, err := maker.CreateToken(username, util.BankerRole, time.Minute)
+	require.NoError(t, err)
+
+	verifier, err := NewRS256JWTMaker(nil, &privateKey.PublicKey, JWTOptions{})
+	require.NoError(t, err)
+	payload, err := verifier.VerifyToken(token, TokenTypeAccess)
+	require.NoError(t, err)
+	require.Equal(t, username, payload.Username)
+	require.Equal(t, util.BankerRole, payload.Role)
+
+	_, _, err = verifier.CreateToken(username, util.BankerRole, time.Minute)
+	require.ErrorIs(t, err, ErrNoSigningKey)
+
+	// The public key used as an HMAC secret must not verify, whatever form of it the attacker picks
+	der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+	forged := signedToken(t, jwt.SigningMethodHS256, testClaims(time.Now(), time.Minute), der, nil)
+	_, err = verifier.VerifyToken(forged, TokenTypeAccess)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	none := signedToken(t, jwt.SigningMethodNone, testClaims(time.Now(), time.Minute), jwt.UnsafeAllowNoneSignatureType, nil)
+	_, err = verifier.VerifyToken(none, TokenTypeAccess)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	smallKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	_, err = NewRS256JWTMaker(smallKey, nil, JWTOptions{})
+	require.Error(t, err)
+}
+
+func TestEdDSAJWTMaker(t *testing.T) {
+	ring, privateKey := newTestKeyRing(t, "key-1")
+	maker, err := NewEdDSAJWTMaker(ring, JWTOptions{Issuer: "go-bank"})
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &jwtClaims{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"alg": AlgorithmEdDSA, "typ": "JWT", "kid": "key-1"}, parsed.Header)
+
+	verifierRing := NewKeyRing()
+	require.NoError(t, verifierRing.AddVerificationKey("key-1", privateKey.Public().(ed25519.PublicKey)))
+	verifier := NewEdDSAJWTVerifier(verifierRing, JWTOptions{Issuer: "go-bank"})
+	_, err = verifier.VerifyToken(token, TokenTypeAccess)
+	require.NoError(t, err)
+
+	// Another issuer, or a token of the HS256 maker, is refused
+	_, err = NewEdDSAJWTVerifier(verifierRing, JWTOptions{Issuer: "other"}).VerifyToken(token, TokenTypeAccess)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	hs256Maker, err := NewHS256JWTMaker(util.RandomString(32), JWTOptions{})
+	require.NoError(t, err)
+	hs256Token, _, err := hs256Maker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+	_, err = verifier.VerifyToken(hs256Token, TokenTypeAccess)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	// Tampering with the claims breaks the signature
+	parts := strings.Split(token, ".")
+	claims := testClaims(time.Now(), time.Minute)
+	claims.Role = util.AdminRole
+	tampered := strings.Split(signedToken(t, jwt.SigningMethodEdDSA, claims, privateKey, nil), ".")[1]
+	_, err = verifier.VerifyToken(parts[0]+"."+tampered+"."+parts[2], TokenTypeAccess)
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
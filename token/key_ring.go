@@ -0,0 +1,161 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrUnknownKey is returned for a token signed with a key that isn't in the key ring
+	ErrUnknownKey = errors.New("unknown key id")
+	// ErrNoSigningKey is returned when creating a token with a key ring that can only verify
+	ErrNoSigningKey = errors.New("key ring has no signing key")
+)
+
+// KeySource looks up the Ed25519 public key tokens signed under a key id are verified with
+type KeySource interface {
+	PublicKey(keyID string) (ed25519.PublicKey, error)
+}
+
+// KeyRing holds the Ed25519 public keys that tokens may be signed with, by key id, and optionally the private key
+// new tokens are signed with. Rotating keys means signing with a new key while the previous public keys are
+// still accepted, until every token they signed has expired. It is safe for concurrent use
+type KeyRing struct {
+	mu           sync.RWMutex
+	signingKeyID string
+	signingKey   ed25519.PrivateKey
+	publicKeys   map[string]ed25519.PublicKey
+}
+
+// NewKeyRing returns an empty key ring, which can't sign nor verify anything until keys are added
+func NewKeyRing() *KeyRing {
+	return &KeyRing{publicKeys: make(map[string]ed25519.PublicKey)}
+}
+
+// ParseKeyRing builds a key ring from configuration. signingKey is the hex encoded 32-byte Ed25519 seed of the
+// active key identified by signingKeyID, it may be empty for services that only verify tokens.
+// verificationKeys is a comma separated list of keyID:hex public key pairs also accepted, e.g. during a rotation
+func ParseKeyRing(signingKeyID, signingKey, verificationKeys string) (*KeyRing, error) {
+	ring := NewKeyRing()
+
+	for _, pair := range strings.Split(verificationKeys, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		keyID, value, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid verification key %q, must be keyID:hex public key", pair)
+		}
+		publicKey, err := hex.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid verification key %q: %w", keyID, err)
+		}
+		if err := ring.AddVerificationKey(keyID, publicKey); err != nil {
+			return nil, err
+		}
+	}
+
+	if signingKey != "" {
+		seed, err := hex.DecodeString(signingKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signing key: %w", err)
+		}
+		if len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("invalid signing key size: %d, must be exactly %d bytes", len(seed), ed25519.SeedSize)
+		}
+		if err := ring.SetSigningKey(signingKeyID, ed25519.NewKeyFromSeed(seed)); err != nil {
+			return nil, err
+		}
+	}
+
+	return ring, nil
+}
+
+// AddVerificationKey accepts tokens signed by the private key of publicKey under keyID
+func (ring *KeyRing) AddVerificationKey(keyID string, publicKey ed25519.PublicKey) error {
+	if keyID == "" {
+		return fmt.Errorf("key id must not be empty")
+	}
+	if len(publicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid public key size: %d, must be exactly %d bytes", len(publicKey), ed25519.PublicKeySize)
+	}
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	if existing, ok := ring.publicKeys[keyID]; ok && !existing.Equal(publicKey) {
+		return fmt.Errorf("key id %q is already used by another key", keyID)
+	}
+	ring.publicKeys[keyID] = publicKey
+	return nil
+}
+
+// SetSigningKey signs new tokens with privateKey under keyID. The previous signing key stays a verification key,
+// so the tokens it signed remain valid until RemoveKey is called
+func (ring *KeyRing) SetSigningKey(keyID string, privateKey ed25519.PrivateKey) error {
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return fmt.Errorf("invalid private key size: %d, must be exactly %d bytes", len(privateKey), ed25519.PrivateKeySize)
+	}
+	if err := ring.AddVerificationKey(keyID, privateKey.Public().(ed25519.PublicKey)); err != nil {
+		return err
+	}
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	ring.signingKeyID = keyID
+	ring.signingKey = privateKey
+	return nil
+}
+
+// RemoveKey stops accepting tokens signed under keyID. The active signing key can't be removed
+func (ring *KeyRing) RemoveKey(keyID string) error {
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	if keyID == ring.signingKeyID && ring.signingKey != nil {
+		return fmt.Errorf("key %q is the active signing key", keyID)
+	}
+	delete(ring.publicKeys, keyID)
+	return nil
+}
+
+// PublicKeys returns a copy of the keys tokens are verified with, by key id
+func (ring *KeyRing) PublicKeys() map[string]ed25519.PublicKey {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	keys := make(map[string]ed25519.PublicKey, len(ring.publicKeys))
+	for keyID, publicKey := range ring.publicKeys {
+		keys[keyID] = publicKey
+	}
+	return keys
+}
+
+// signingKeyPair returns the active signing key and its id
+func (ring *KeyRing) signingKeyPair() (string, ed25519.PrivateKey, error) {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	if ring.signingKey == nil {
+		return "", nil, ErrNoSigningKey
+	}
+	return ring.signingKeyID, ring.signingKey, nil
+}
+
+// PublicKey returns the verification key of keyID
+func (ring *KeyRing) PublicKey(keyID string) (ed25519.PublicKey, error) {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	publicKey, ok := ring.publicKeys[keyID]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return publicKey, nil
+}
@@ -0,0 +1,49 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKeyRing(t *testing.T) {
+	seed := "1111111111111111111111111111111111111111111111111111111111111111"
+	rawSeed, err := hex.DecodeString(seed)
+	require.NoError(t, err)
+	publicKey := ed25519.NewKeyFromSeed(rawSeed).Public().(ed25519.PublicKey)
+	oldKey := "4242424242424242424242424242424242424242424242424242424242424242"
+
+	ring, err := ParseKeyRing("key-2", seed, " key-1:"+oldKey+" ,")
+	require.NoError(t, err)
+
+	keys := ring.PublicKeys()
+	require.Len(t, keys, 2)
+	require.Equal(t, publicKey, keys["key-2"])
+	require.Equal(t, oldKey, hex.EncodeToString(keys["key-1"]))
+
+	keyID, _, err := ring.signingKeyPair()
+	require.NoError(t, err)
+	require.Equal(t, "key-2", keyID)
+
+	// Verification keys only
+	ring, err = ParseKeyRing("", "", "key-1:"+oldKey)
+	require.NoError(t, err)
+	_, _, err = ring.signingKeyPair()
+	require.ErrorIs(t, err, ErrNoSigningKey)
+
+	for name, arg := range map[string][3]string{
+		"ShortSeed":        {"key-2", seed[:32], ""},
+		"NotHex":           {"key-2", "zz" + seed[2:], ""},
+		"NoKeyID":          {"", seed, ""},
+		"NoSeparator":      {"key-2", seed, "key-1" + oldKey},
+		"ShortPublicKey":   {"key-2", seed, "key-1:" + oldKey[:10]},
+		"KeyIDReused":      {"key-1", seed, "key-1:" + oldKey},
+		"PublicKeyNotHex":  {"key-2", seed, "key-1:xyz"},
+		"EmptyKeyIDInList": {"key-2", seed, ":" + oldKey},
+	} {
+		_, err := ParseKeyRing(arg[0], arg[1], arg[2])
+		require.Error(t, err, name)
+	}
+}
@@ -1,12 +1,60 @@
 package token
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	"github.com/October-9th/simple-bank/util"
+)
 
 // Maker í an interface for managing tokens
 type Maker interface {
-	// CreateToken creates and sign new token for specific username and valid duration
-	CreateToken(username string, duration time.Duration) (string, *Payload, error)
+	// CreateToken creates and sign new token for specific username, role and valid duration.
+	// It is an access token without any scope unless options say otherwise
+	CreateToken(username string, role string, duration time.Duration, options ...Option) (string, *Payload, error)
+
+	// VerifyToken check if the token is valid, of tokenType and grants every one of scopes
+	VerifyToken(token string, tokenType TokenType, scopes ...string) (*Payload, error)
+}
+
+// Token formats of the configuration
+const (
+	FormatPaseto = "paseto"
+	FormatJWT    = "jwt"
+)
 
-	// VerifyToken check if the token is valid or not
-	VerifyToken(token string) (*Payload, error)
+// NewMaker creates the token maker of the configuration. The format is PASETO unless config.TokenFormat is jwt,
+// the tokens are signed with public-key cryptography when a private key is configured, with the symmetric key otherwise.
+// Every maker stamps and checks the issuer and audience of the configuration
+func NewMaker(config util.Config) (Maker, error) {
+	switch config.TokenFormat {
+	case "", FormatPaseto:
+		options := PasetoOptions{
+			Issuer:   config.TokenIssuer,
+			Audience: config.TokenAudience,
+		}
+		if config.TokenPrivateKey == "" {
+			return NewPasetoMaker(config.TokenSymmectricKey, options)
+		}
+		ring, err := ParseKeyRing(config.TokenKeyID, config.TokenPrivateKey, config.TokenVerificationKeys)
+		if err != nil {
+			return nil, err
+		}
+		return NewPasetoPublicMaker(ring, options)
+	case FormatJWT:
+		options := JWTOptions{
+			Issuer:   config.TokenIssuer,
+			Audience: config.TokenAudience,
+		}
+		if config.TokenPrivateKey == "" {
+			return NewHS256JWTMaker(config.TokenSymmectricKey, options)
+		}
+		ring, err := ParseKeyRing(config.TokenKeyID, config.TokenPrivateKey, config.TokenVerificationKeys)
+		if err != nil {
+			return nil, err
+		}
+		return NewEdDSAJWTMaker(ring, options)
+	default:
+		return nil, fmt.Errorf("unsupported token format: %s", config.TokenFormat)
+	}
 }
@@ -0,0 +1,81 @@
+package token
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/October-9th/simple-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMaker(t *testing.T) {
+	seed := hex.EncodeToString(make([]byte, 32))
+
+	testCases := []struct {
+		name      string
+		config    util.Config
+		checkType func(t *testing.T, maker Maker, err error)
+	}{
+		{
+			name:   "Paseto",
+			config: util.Config{TokenSymmectricKey: util.RandomString(32)},
+			checkType: func(t *testing.T, maker Maker, err error) {
+				require.NoError(t, err)
+				require.IsType(t, &PasetoMaker{}, maker)
+			},
+		},
+		{
+			name:   "PasetoPublic",
+			config: util.Config{TokenFormat: FormatPaseto, TokenKeyID: "k1", TokenPrivateKey: seed},
+			checkType: func(t *testing.T, maker Maker, err error) {
+				require.NoError(t, err)
+				require.IsType(t, &PasetoPublicMaker{}, maker)
+			},
+		},
+		{
+			name:   "HS256JWT",
+			config: util.Config{TokenFormat: FormatJWT, TokenSymmectricKey: util.RandomString(32)},
+			checkType: func(t *testing.T, maker Maker, err error) {
+				require.NoError(t, err)
+				require.Equal(t, AlgorithmHS256, maker.(*JWTMaker).method.Alg())
+			},
+		},
+		{
+			name:   "EdDSAJWT",
+			config: util.Config{TokenFormat: FormatJWT, TokenKeyID: "k1", TokenPrivateKey: seed},
+			checkType: func(t *testing.T, maker Maker, err error) {
+				require.NoError(t, err)
+				require.Equal(t, AlgorithmEdDSA, maker.(*JWTMaker).method.Alg())
+			},
+		},
+		{
+			name:   "UnsupportedFormat",
+			config: util.Config{TokenFormat: "macaroon", TokenSymmectricKey: util.RandomString(32)},
+			checkType: func(t *testing.T, maker Maker, err error) {
+				require.Error(t, err)
+				require.Nil(t, maker)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			tc.config.TokenIssuer = "go-bank"
+			maker, err := NewMaker(tc.config)
+			tc.checkType(t, maker, err)
+			if err != nil {
+				return
+			}
+
+			// The issuer of the configuration is stamped on the tokens
+			token, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+			require.NoError(t, err)
+			payload, err := maker.VerifyToken(token, TokenTypeAccess)
+			require.NoError(t, err)
+			require.Equal(t, "go-bank", payload.Issuer)
+		})
+	}
+}
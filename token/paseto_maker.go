@@ -8,14 +8,49 @@ import (
 	"github.com/o1egl/paseto"
 )
 
+// PasetoOptions are the checks made on the PASETO tokens besides their signature or encryption
+type PasetoOptions struct {
+	// Issuer is the issuer of the tokens created, verified tokens must have the same one. Empty skips the check
+	Issuer string
+	// Audience is the audience of the tokens created, verified tokens must include it. Empty skips the check
+	Audience string
+}
+
+// stamp sets the issuer and audience of the options on payload unless it has its own
+func (options PasetoOptions) stamp(payload *Payload) {
+	if payload.Issuer == "" {
+		payload.Issuer = options.Issuer
+	}
+	if len(payload.Audience) == 0 && options.Audience != "" {
+		payload.Audience = []string{options.Audience}
+	}
+}
+
+// check returns ErrInvalidToken unless payload has the issuer and audience of the options
+func (options PasetoOptions) check(payload *Payload) error {
+	if options.Issuer != "" && payload.Issuer != options.Issuer {
+		return ErrInvalidToken
+	}
+	if options.Audience == "" {
+		return nil
+	}
+	for _, audience := range payload.Audience {
+		if audience == options.Audience {
+			return nil
+		}
+	}
+	return ErrInvalidToken
+}
+
 // PasetoMaker is a PASETO token struct
 type PasetoMaker struct {
 	paseto *paseto.V2
 	// Symetric for locally banking service
 	symmetricKey []byte
+	options      PasetoOptions
 }
 
-func NewPasetoMaker(symmetricKey string) (Maker, error) {
+func NewPasetoMaker(symmetricKey string, options PasetoOptions) (Maker, error) {
 	if len(symmetricKey) != chacha20poly1305.KeySize {
 		return nil, fmt.Errorf("invalid key size: %d, must be exactly %d character", len(symmetricKey), chacha20poly1305.KeySize)
 	}
@@ -23,22 +58,24 @@ func NewPasetoMaker(symmetricKey string) (Maker, error) {
 	maker := &PasetoMaker{
 		paseto:       paseto.NewV2(),
 		symmetricKey: []byte(symmetricKey),
+		options:      options,
 	}
 
 	return maker, nil
 }
 
-func (maker *PasetoMaker) CreateToken(username string, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewPayLoad(username, duration)
+func (maker *PasetoMaker) CreateToken(username string, role string, duration time.Duration, options ...Option) (string, *Payload, error) {
+	payload, err := NewPayLoad(username, role, duration, options...)
 	if err != nil {
 		return "", payload, err
 	}
+	maker.options.stamp(payload)
 
 	token, err := maker.paseto.Encrypt(maker.symmetricKey, payload, nil)
 	return token, payload, err
 }
 
-func (maker *PasetoMaker) VerifyToken(token string) (*Payload, error) {
+func (maker *PasetoMaker) VerifyToken(token string, tokenType TokenType, scopes ...string) (*Payload, error) {
 	payload := &Payload{}
 
 	err := maker.paseto.Decrypt(token, maker.symmetricKey, payload, nil)
@@ -50,5 +87,11 @@ func (maker *PasetoMaker) VerifyToken(token string) (*Payload, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := maker.options.check(payload); err != nil {
+		return nil, err
+	}
+	if err := payload.Check(tokenType, scopes...); err != nil {
+		return nil, err
+	}
 	return payload, nil
 }
@@ -9,44 +9,83 @@ import (
 )
 
 func TestPasetoMaker(t *testing.T) {
-	maker, err := NewPasetoMaker(util.RandomString(32))
+	maker, err := NewPasetoMaker(util.RandomString(32), PasetoOptions{})
 	require.NoError(t, err)
 
 	username := util.RandomOwner()
+	role := util.DepositorRole
 	duration := time.Minute
 
 	issuedAt := time.Now()
 	expiredAt := issuedAt.Add(duration)
 
-	token, payload, err := maker.CreateToken(username, duration)
+	token, payload, err := maker.CreateToken(username, role, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 
-	payload, err = maker.VerifyToken(token)
+	payload, err = maker.VerifyToken(token, TokenTypeAccess)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 
 	require.NotZero(t, payload.ID)
 	require.Equal(t, username, payload.Username)
+	require.Equal(t, role, payload.Role)
 	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
 	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
 
 }
 
 func TestExpiredPasetoMaker(t *testing.T) {
-	maker, err := NewJWTMaker(util.RandomString(32))
+	maker, err := NewPasetoMaker(util.RandomString(32), PasetoOptions{})
 	require.NoError(t, err)
 
 	username := util.RandomOwner()
+	role := util.DepositorRole
 	duration := time.Minute
 
-	token, payload, err := maker.CreateToken(username, -duration)
+	token, payload, err := maker.CreateToken(username, role, -duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 
-	payload, err = maker.VerifyToken(token)
+	payload, err = maker.VerifyToken(token, TokenTypeAccess)
 	require.Error(t, err)
 	require.EqualError(t, err, ErrExpiredToken.Error())
 	require.Nil(t, payload)
 
 }
+
+func TestPasetoMakerIssuerAndAudience(t *testing.T) {
+	symmetricKey := util.RandomString(32)
+	options := PasetoOptions{Issuer: "go-bank", Audience: "partners"}
+	maker, err := NewPasetoMaker(symmetricKey, options)
+	require.NoError(t, err)
+
+	token, payload, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, "go-bank", payload.Issuer)
+	require.Equal(t, []string{"partners"}, payload.Audience)
+
+	payload, err = maker.VerifyToken(token, TokenTypeAccess)
+	require.NoError(t, err)
+	require.Equal(t, "go-bank", payload.Issuer)
+
+	// A token of the same key for another issuer or audience is refused
+	other, err := NewPasetoMaker(symmetricKey, PasetoOptions{})
+	require.NoError(t, err)
+	testCases := map[string][]Option{
+		"NoIssuer":      {WithAudience("partners")},
+		"OtherIssuer":   {WithIssuer("evil"), WithAudience("partners")},
+		"NoAudience":    {WithIssuer("go-bank")},
+		"OtherAudience": {WithIssuer("go-bank"), WithAudience("other")},
+	}
+	for name, tokenOptions := range testCases {
+		t.Run(name, func(t *testing.T) {
+			token, _, err := other.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute, tokenOptions...)
+			require.NoError(t, err)
+
+			payload, err := maker.VerifyToken(token, TokenTypeAccess)
+			require.EqualError(t, err, ErrInvalidToken.Error())
+			require.Nil(t, payload)
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// pasetoPublicHeader is the header of PASETO v4.public tokens, signed with Ed25519
+const pasetoPublicHeader = "v4.public."
+
+// maxFooterSize bounds the footer parsed before the signature is checked
+const maxFooterSize = 512
+
+// pasetoFooter is the footer of the tokens, in clear text, naming the key the token was signed with
+type pasetoFooter struct {
+	KeyID string `json:"kid"`
+}
+
+// PasetoPublicMaker is a PASETO v4.public token maker. Tokens are signed with the active private key of a key ring
+// and verified with its public keys, so services holding only the public keys can check tokens but not forge them
+type PasetoPublicMaker struct {
+	// ring signs new tokens, it is nil for a verifier
+	ring    *KeyRing
+	keys    KeySource
+	options PasetoOptions
+}
+
+// NewPasetoPublicMaker creates a maker signing and verifying with the keys of ring. A ring without a signing key
+// makes a maker that only verifies tokens
+func NewPasetoPublicMaker(ring *KeyRing, options PasetoOptions) (Maker, error) {
+	if ring == nil || len(ring.PublicKeys()) == 0 {
+		return nil, fmt.Errorf("key ring must contain at least one key")
+	}
+	return &PasetoPublicMaker{ring: ring, keys: ring, options: options}, nil
+}
+
+// NewPasetoPublicVerifier creates a maker that only verifies tokens, with the public keys of keys,
+// e.g. those published by the server and fetched by a jwks.Client
+func NewPasetoPublicVerifier(keys KeySource, options PasetoOptions) Maker {
+	return &PasetoPublicMaker{keys: keys, options: options}
+}
+
+func (maker *PasetoPublicMaker) CreateToken(username string, role string, duration time.Duration, options ...Option) (string, *Payload, error) {
+	payload, err := NewPayLoad(username, role, duration, options...)
+	if err != nil {
+		return "", payload, err
+	}
+
+	if maker.ring == nil {
+		return "", payload, ErrNoSigningKey
+	}
+	keyID, privateKey, err := maker.ring.signingKeyPair()
+	if err != nil {
+		return "", payload, err
+	}
+	maker.options.stamp(payload)
+
+	message, err := json.Marshal(payload)
+	if err != nil {
+		return "", payload, err
+	}
+	footer, err := json.Marshal(pasetoFooter{KeyID: keyID})
+	if err != nil {
+		return "", payload, err
+	}
+
+	signature := ed25519.Sign(privateKey, preAuthEncode([]byte(pasetoPublicHeader), message, footer, nil))
+	token := pasetoPublicHeader +
+		base64.RawURLEncoding.EncodeToString(append(message, signature...)) + "." +
+		base64.RawURLEncoding.EncodeToString(footer)
+	return token, payload, nil
+}
+
+func (maker *PasetoPublicMaker) VerifyToken(token string, tokenType TokenType, scopes ...string) (*Payload, error) {
+	message, err := maker.verify(token)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	payload := &Payload{}
+	if err := json.Unmarshal(message, payload); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	err = payload.Valid()
+	if err != nil {
+		return nil, err
+	}
+	if err := maker.options.check(payload); err != nil {
+		return nil, err
+	}
+	if err := payload.Check(tokenType, scopes...); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// verify checks the signature of token with the key named in its footer and returns the signed message
+func (maker *PasetoPublicMaker) verify(token string) ([]byte, error) {
+	if !strings.HasPrefix(token, pasetoPublicHeader) {
+		return nil, fmt.Errorf("not a v4.public token")
+	}
+	parts := strings.Split(token[len(pasetoPublicHeader):], ".")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("token has no footer")
+	}
+
+	body, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || len(body) < ed25519.SignatureSize {
+		return nil, fmt.Errorf("malformed body")
+	}
+	if len(parts[1]) > base64.RawURLEncoding.EncodedLen(maxFooterSize) {
+		return nil, fmt.Errorf("footer is too large")
+	}
+	footer, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed footer")
+	}
+
+	var claims pasetoFooter
+	if err := json.Unmarshal(footer, &claims); err != nil {
+		return nil, fmt.Errorf("malformed footer")
+	}
+	publicKey, err := maker.keys.PublicKey(claims.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	message := body[:len(body)-ed25519.SignatureSize]
+	signature := body[len(body)-ed25519.SignatureSize:]
+	if !ed25519.Verify(publicKey, preAuthEncode([]byte(pasetoPublicHeader), message, footer, nil), signature) {
+		return nil, fmt.Errorf("invalid signature")
+	}
+	return message, nil
+}
+
+// preAuthEncode is the PAE function of the PASETO specification: the number of pieces, then each piece prefixed
+// with its length, all lengths as 64-bit little endian integers with the top bit cleared
+func preAuthEncode(pieces ...[]byte) []byte {
+	le64 := func(n int) []byte {
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, uint64(n))
+		b[7] &= 0x7f
+		return b
+	}
+
+	output := le64(len(pieces))
+	for _, piece := range pieces {
+		output = append(output, le64(len(piece))...)
+		output = append(output, piece...)
+	}
+	return output
+}
@@ -0,0 +1,185 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/October-9th/simple-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKeyRing(t *testing.T, keyID string) (*KeyRing, ed25519.PrivateKey) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ring := NewKeyRing()
+	require.NoError(t, ring.SetSigningKey(keyID, privateKey))
+	return ring, privateKey
+}
+
+func TestPasetoPublicMaker(t *testing.T) {
+	ring, _ := newTestKeyRing(t, "key-1")
+	maker, err := NewPasetoPublicMaker(ring, PasetoOptions{})
+	require.NoError(t, err)
+
+	username := util.RandomOwner()
+	role := util.DepositorRole
+	duration := time.Minute
+
+	issuedAt := time.Now()
+	expiredAt := issuedAt.Add(duration)
+
+	token, payload, err := maker.CreateToken(username, role, duration)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(token, "v4.public."))
+
+	footer, err := base64.RawURLEncoding.DecodeString(token[strings.LastIndex(token, ".")+1:])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"kid":"key-1"}`, string(footer))
+
+	payload, err = maker.VerifyToken(token, TokenTypeAccess)
+	require.NoError(t, err)
+	require.NotZero(t, payload.ID)
+	require.Equal(t, username, payload.Username)
+	require.Equal(t, role, payload.Role)
+	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
+	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
+}
+
+func TestExpiredPasetoPublicToken(t *testing.T) {
+	ring, _ := newTestKeyRing(t, "key-1")
+	maker, err := NewPasetoPublicMaker(ring, PasetoOptions{})
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, -time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token, TokenTypeAccess)
+	require.EqualError(t, err, ErrExpiredToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestPasetoPublicVerifierOnly(t *testing.T) {
+	ring, privateKey := newTestKeyRing(t, "key-1")
+	signer, err := NewPasetoPublicMaker(ring, PasetoOptions{})
+	require.NoError(t, err)
+
+	// A service given only the public key checks tokens but can't mint them
+	verifierRing := NewKeyRing()
+	require.NoError(t, verifierRing.AddVerificationKey("key-1", privateKey.Public().(ed25519.PublicKey)))
+	verifier, err := NewPasetoPublicMaker(verifierRing, PasetoOptions{})
+	require.NoError(t, err)
+
+	token, _, err := signer.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+	_, err = verifier.VerifyToken(token, TokenTypeAccess)
+	require.NoError(t, err)
+
+	_, _, err = verifier.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.ErrorIs(t, err, ErrNoSigningKey)
+}
+
+func TestPasetoPublicIssuerAndAudience(t *testing.T) {
+	ring, _ := newTestKeyRing(t, "key-1")
+	maker, err := NewPasetoPublicMaker(ring, PasetoOptions{Issuer: "go-bank"})
+	require.NoError(t, err)
+
+	// The ring signs for every service, each verifier only accepts the tokens meant for it
+	token, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute, WithAudience("payments"))
+	require.NoError(t, err)
+
+	payload, err := NewPasetoPublicVerifier(ring, PasetoOptions{Issuer: "go-bank", Audience: "payments"}).VerifyToken(token, TokenTypeAccess)
+	require.NoError(t, err)
+	require.Equal(t, "go-bank", payload.Issuer)
+	require.Equal(t, []string{"payments"}, payload.Audience)
+
+	_, err = NewPasetoPublicVerifier(ring, PasetoOptions{Issuer: "go-bank", Audience: "reports"}).VerifyToken(token, TokenTypeAccess)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	_, err = NewPasetoPublicVerifier(ring, PasetoOptions{Issuer: "other"}).VerifyToken(token, TokenTypeAccess)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+}
+
+func TestPasetoPublicKeyRotation(t *testing.T) {
+	ring, _ := newTestKeyRing(t, "key-1")
+	maker, err := NewPasetoPublicMaker(ring, PasetoOptions{})
+	require.NoError(t, err)
+
+	oldToken, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+
+	_, newKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	require.NoError(t, ring.SetSigningKey("key-2", newKey))
+	require.Error(t, ring.RemoveKey("key-2"))
+
+	newToken, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+	require.True(t, strings.HasSuffix(newToken, base64.RawURLEncoding.EncodeToString([]byte(`{"kid":"key-2"}`))))
+
+	// Both keys are accepted during the rotation
+	_, err = maker.VerifyToken(oldToken, TokenTypeAccess)
+	require.NoError(t, err)
+	_, err = maker.VerifyToken(newToken, TokenTypeAccess)
+	require.NoError(t, err)
+
+	require.NoError(t, ring.RemoveKey("key-1"))
+	_, err = maker.VerifyToken(oldToken, TokenTypeAccess)
+	require.ErrorIs(t, err, ErrInvalidToken)
+	_, err = maker.VerifyToken(newToken, TokenTypeAccess)
+	require.NoError(t, err)
+}
+
+func TestInvalidPasetoPublicToken(t *testing.T) {
+	ring, _ := newTestKeyRing(t, "key-1")
+	maker, err := NewPasetoPublicMaker(ring, PasetoOptions{})
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+	body, footer, _ := strings.Cut(strings.TrimPrefix(token, "v4.public."), ".")
+
+	// The same key id on another ring is another key
+	otherRing, _ := newTestKeyRing(t, "key-1")
+	otherMaker, err := NewPasetoPublicMaker(otherRing, PasetoOptions{})
+	require.NoError(t, err)
+	forged, _, err := otherMaker.CreateToken(util.RandomOwner(), util.AdminRole, time.Minute)
+	require.NoError(t, err)
+
+	symmetricMaker, err := NewPasetoMaker(util.RandomString(32), PasetoOptions{})
+	require.NoError(t, err)
+	localToken, _, err := symmetricMaker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+
+	for name, invalid := range map[string]string{
+		"Forged":       forged,
+		"NoFooter":     "v4.public." + body,
+		"UnknownKey":   "v4.public." + body + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"kid":"key-9"}`)),
+		"OtherFooter":  "v4.public." + body + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"kid":"key-1","x":1}`)),
+		"Tampered":     "v4.public." + "A" + body[1:] + "." + footer,
+		"LocalToken":   localToken,
+		"ShortBody":    "v4.public.AAAA." + footer,
+		"Empty":        "",
+		"BadFooterB64": "v4.public." + body + ".!!",
+	} {
+		_, err := maker.VerifyToken(invalid, TokenTypeAccess)
+		require.ErrorIs(t, err, ErrInvalidToken, name)
+	}
+}
+
+// TestPasetoPublicVector checks the signature against vector 4-S-1 of the PASETO specification
+func TestPasetoPublicVector(t *testing.T) {
+	secretKey, err := hex.DecodeString("b4cbfb43df4ce210727d953e4a713307fa19bb7d9f85041438d9e11b942a3774" +
+		"1eb9dbbbbc047c03fd70604e0071f0987e16b28b757225c11f00415d0e20b1a2")
+	require.NoError(t, err)
+	message := []byte(`{"data":"this is a signed message","exp":"2022-01-01T00:00:00+00:00"}`)
+
+	signature := ed25519.Sign(secretKey, preAuthEncode([]byte(pasetoPublicHeader), message, nil, nil))
+	token := pasetoPublicHeader + base64.RawURLEncoding.EncodeToString(append(message, signature...))
+	require.Equal(t, "v4.public.eyJkYXRhIjoidGhpcyBpcyBhIHNpZ25lZCBtZXNzYWdlIiwiZXhwIjoiMjAyMi0wMS0wMVQwMDowMDowMCswMDowMCJ9"+
+		"bg_XBBzds8lTZShVlwwKSgeKpLT3yukTw6JUz3W4h_ExsQV-P0V54zemZDcAxFaSeef1QlXEFtkqxT1ciiQEDA", token)
+}
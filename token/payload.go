@@ -10,18 +10,88 @@ import (
 var (
 	ErrInvalidToken = errors.New("token is invalid")
 	ErrExpiredToken = errors.New("token has expired")
+	// ErrWrongTokenType is returned when a token is used for what another type of token is for,
+	// e.g. an access token given as a refresh token
+	ErrWrongTokenType = errors.New("token has the wrong type")
+	// ErrMissingScope is returned when a token doesn't grant a scope the request needs
+	ErrMissingScope = errors.New("token is missing a required scope")
 )
 
+// TokenType tells what a token may be used for
+type TokenType string
+
+const (
+	// TokenTypeAccess authenticates the requests to the API
+	TokenTypeAccess TokenType = "access"
+	// TokenTypeRefresh is only exchanged for new tokens, or given to log the session out
+	TokenTypeRefresh TokenType = "refresh"
+)
+
+// Scopes granted by a token. Read-only requests need ScopeRead, every other request ScopeWrite
+const (
+	ScopeRead  = "bank:read"
+	ScopeWrite = "bank:write"
+)
+
+// DefaultScopes are the scopes of the tokens a user gets by logging in
+var DefaultScopes = []string{ScopeRead, ScopeWrite}
+
 // Payload contains the payload data of the token
 type Payload struct {
-	ID        uuid.UUID `json:"id"`
-	Username  string    `json:"username"`
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+	Role     string    `json:"role"`
+	Type     TokenType `json:"type"`
+	// Issuer and Audience tell who created the token and who it is meant for, both are optional
+	Issuer    string    `json:"issuer,omitempty"`
+	Audience  []string  `json:"audience,omitempty"`
+	Scopes    []string  `json:"scopes,omitempty"`
 	IssuedAt  time.Time `json:"issued_at"`
 	ExpiredAt time.Time `json:"expired_at"`
+	// SessionID is the login the token was issued for, the family of its refresh tokens
+	SessionID uuid.UUID `json:"session_id"`
+}
+
+// Option sets a field of the payload of a token being created
+type Option func(payload *Payload)
+
+// WithType sets the type of the token, an access token by default
+func WithType(tokenType TokenType) Option {
+	return func(payload *Payload) {
+		payload.Type = tokenType
+	}
+}
+
+// WithIssuer sets who issued the token
+func WithIssuer(issuer string) Option {
+	return func(payload *Payload) {
+		payload.Issuer = issuer
+	}
+}
+
+// WithAudience sets who the token is meant for
+func WithAudience(audience ...string) Option {
+	return func(payload *Payload) {
+		payload.Audience = audience
+	}
 }
 
-// NewPayload creates a new payload with specific username and duration
-func NewPayLoad(username string, duration time.Duration) (*Payload, error) {
+// WithScopes sets the scopes granted by the token
+func WithScopes(scopes ...string) Option {
+	return func(payload *Payload) {
+		payload.Scopes = scopes
+	}
+}
+
+// WithSessionID ties the token to the login it was issued for
+func WithSessionID(sessionID uuid.UUID) Option {
+	return func(payload *Payload) {
+		payload.SessionID = sessionID
+	}
+}
+
+// NewPayload creates a new payload with specific username, role and duration
+func NewPayLoad(username string, role string, duration time.Duration, options ...Option) (*Payload, error) {
 	tokenID, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
@@ -29,9 +99,14 @@ func NewPayLoad(username string, duration time.Duration) (*Payload, error) {
 	payload := &Payload{
 		ID:        tokenID,
 		Username:  username,
+		Role:      role,
+		Type:      TokenTypeAccess,
 		IssuedAt:  time.Now(),
 		ExpiredAt: time.Now().Add(duration),
 	}
+	for _, option := range options {
+		option(payload)
+	}
 	return payload, nil
 }
 
@@ -44,3 +119,26 @@ func (payload *Payload) Valid() error {
 	return nil
 
 }
+
+// Check returns an error unless the token is of tokenType and grants every one of scopes
+func (payload *Payload) Check(tokenType TokenType, scopes ...string) error {
+	if payload.Type != tokenType {
+		return ErrWrongTokenType
+	}
+	for _, scope := range scopes {
+		if !payload.HasScope(scope) {
+			return ErrMissingScope
+		}
+	}
+	return nil
+}
+
+// HasScope tells whether the token grants scope
+func (payload *Payload) HasScope(scope string) bool {
+	for _, granted := range payload.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
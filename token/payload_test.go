@@ -0,0 +1,61 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/October-9th/simple-bank/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenTypeAndScopes(t *testing.T) {
+	pasetoMaker, err := NewPasetoMaker(util.RandomString(32), PasetoOptions{})
+	require.NoError(t, err)
+	ring, _ := newTestKeyRing(t, "key-1")
+	pasetoPublicMaker, err := NewPasetoPublicMaker(ring, PasetoOptions{})
+	require.NoError(t, err)
+	jwtMaker, err := NewEdDSAJWTMaker(ring, JWTOptions{})
+	require.NoError(t, err)
+
+	makers := map[string]Maker{
+		"Paseto":       pasetoMaker,
+		"PasetoPublic": pasetoPublicMaker,
+		"JWT":          jwtMaker,
+	}
+	for name, maker := range makers {
+		t.Run(name, func(t *testing.T) {
+			refreshToken, created, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute,
+				WithType(TokenTypeRefresh), WithScopes(ScopeRead, ScopeWrite),
+				WithIssuer("go-bank"), WithAudience("mobile", "web"))
+			require.NoError(t, err)
+			require.Equal(t, TokenTypeRefresh, created.Type)
+
+			payload, err := maker.VerifyToken(refreshToken, TokenTypeRefresh, ScopeWrite)
+			require.NoError(t, err)
+			require.Equal(t, TokenTypeRefresh, payload.Type)
+			require.Equal(t, []string{ScopeRead, ScopeWrite}, payload.Scopes)
+			require.Equal(t, "go-bank", payload.Issuer)
+			require.Equal(t, []string{"mobile", "web"}, payload.Audience)
+
+			// A refresh token isn't an access token, and the other way round
+			_, err = maker.VerifyToken(refreshToken, TokenTypeAccess)
+			require.ErrorIs(t, err, ErrWrongTokenType)
+
+			sessionID := uuid.New()
+			accessToken, created, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute,
+				WithScopes(ScopeRead), WithSessionID(sessionID))
+			require.NoError(t, err)
+			require.Equal(t, TokenTypeAccess, created.Type)
+			require.Equal(t, uuid.Nil, payload.SessionID)
+
+			_, err = maker.VerifyToken(accessToken, TokenTypeRefresh)
+			require.ErrorIs(t, err, ErrWrongTokenType)
+			payload, err = maker.VerifyToken(accessToken, TokenTypeAccess, ScopeRead)
+			require.NoError(t, err)
+			require.Equal(t, sessionID, payload.SessionID)
+			_, err = maker.VerifyToken(accessToken, TokenTypeAccess, ScopeRead, ScopeWrite)
+			require.ErrorIs(t, err, ErrMissingScope)
+		})
+	}
+}
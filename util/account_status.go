@@ -0,0 +1,26 @@
+package util
+
+const (
+	// AccountActive is the status of a new account, it can send and receive money
+	AccountActive = "active"
+	// AccountFrozen is set while an account is under investigation, no money moves until it is unfrozen
+	AccountFrozen = "frozen"
+	// AccountClosed is final, the history of the account is kept
+	AccountClosed = "closed"
+)
+
+// accountStatusTransitions lists the statuses each status may move to
+var accountStatusTransitions = map[string][]string{
+	AccountActive: {AccountFrozen, AccountClosed},
+	AccountFrozen: {AccountActive},
+}
+
+// CanTransitionAccountStatus return true if an account may move from one status to the other
+func CanTransitionAccountStatus(from, to string) bool {
+	for _, status := range accountStatusTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
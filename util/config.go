@@ -3,12 +3,18 @@ package util
 // Config store all configuration of the application
 // The values are read by viper from config file or environment variables
 import (
+	"fmt"
 	"time"
 
 	"github.com/spf13/viper"
 )
 
+// EnvironmentDevelopment is the environment a developer runs the server in, the only one allowed to go without signing keys
+const EnvironmentDevelopment = "development"
+
 type Config struct {
+	// Environment is development on a developer's machine, anything else is treated as production
+	Environment          string        `mapstructure:"ENVIRONMENT"`
 	DBDriver             string        `mapstructure:"DB_DRIVER"`
 	DBSource             string        `mapstructure:"DB_SOURCE"`
 	HTTPServerAddress    string        `mapstructure:"HTTP_SERVER_ADDRESS"`
@@ -16,6 +22,28 @@ type Config struct {
 	TokenSymmectricKey   string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
 	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
 	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	// TokenFormat is paseto, the default, or jwt
+	TokenFormat string `mapstructure:"TOKEN_FORMAT"`
+	// TokenKeyID and TokenPrivateKey, the hex encoded 32-byte Ed25519 seed e.g. from openssl rand -hex 32,
+	// make the server sign tokens with Ed25519, public-key PASETO or EdDSA JWT, instead of using TokenSymmectricKey
+	TokenKeyID      string `mapstructure:"TOKEN_KEY_ID"`
+	TokenPrivateKey string `mapstructure:"TOKEN_PRIVATE_KEY"`
+	// TokenVerificationKeys lists other keys still accepted as keyID:hex public key pairs separated by commas,
+	// the previous signing key is kept here after a rotation until the tokens it signed have expired
+	TokenVerificationKeys string `mapstructure:"TOKEN_VERIFICATION_KEYS"`
+	// TokenIssuer and TokenAudience are stamped on the tokens the server creates and required on those it verifies,
+	// empty skips the check
+	TokenIssuer   string `mapstructure:"TOKEN_ISSUER"`
+	TokenAudience string `mapstructure:"TOKEN_AUDIENCE"`
+	// LedgerHashKey, hex encoded e.g. from openssl rand -hex 32, keys the hashes chaining the entries of each account.
+	// Entries sealed without it, or with another key, no longer verify once it is set
+	LedgerHashKey string `mapstructure:"LEDGER_HASH_KEY"`
+	// ExchangeRatesFile is an optional CSV file of exchange rates loaded at startup
+	ExchangeRatesFile string `mapstructure:"EXCHANGE_RATES_FILE"`
+	// SnapshotDelay is how long after midnight UTC the server snapshots the previous day's balances, 0 disables the schedule
+	SnapshotDelay time.Duration `mapstructure:"SNAPSHOT_DELAY"`
+	// ReconciliationDelay is how long after midnight UTC the server reconciles the ledger, 0 disables the nightly run
+	ReconciliationDelay time.Duration `mapstructure:"RECONCILIATION_DELAY"`
 }
 
 // LoadConfig read configuration from file environment variable
@@ -37,3 +65,12 @@ func LoadConfig(path string) (config Config, err error) {
 	err = viper.Unmarshal(&config)
 	return
 }
+
+// Validate checks the settings the server can't safely run without.
+// Outside development tokens have to be signed with TokenPrivateKey, which is never committed
+func (config Config) Validate() error {
+	if config.Environment != EnvironmentDevelopment && config.TokenPrivateKey == "" {
+		return fmt.Errorf("TOKEN_PRIVATE_KEY is required in the %q environment", config.Environment)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigValidate(t *testing.T) {
+	// Development may fall back to the symmetric key
+	config := Config{Environment: EnvironmentDevelopment}
+	require.NoError(t, config.Validate())
+
+	// Anything else needs a signing key, an unset environment included
+	for _, environment := range []string{"", "production", "staging"} {
+		config := Config{Environment: environment}
+		require.Error(t, config.Validate())
+
+		config.TokenPrivateKey = RandomString(64)
+		require.NoError(t, config.Validate())
+	}
+}
@@ -1,16 +1,80 @@
 package util
 
+import (
+	"sort"
+	"sync"
+)
+
 const (
 	USD = "USD"
 	EUR = "EUR"
 	CAD = "CAD"
+	JPY = "JPY"
+	KWD = "KWD"
 )
 
+// Currency describes an ISO 4217 currency of the registry
+type Currency struct {
+	Code        string
+	NumericCode string
+	// MinorUnit is the number of decimal places, amounts are stored in units of 10^-MinorUnit
+	MinorUnit int32
+	Enabled   bool
+}
+
+// currencyRegistry holds the currencies known by the application. It starts with the currencies
+// the database enables by default and is replaced by SetCurrencies once they are read from the database
+var currencyRegistry = struct {
+	sync.RWMutex
+	currencies map[string]Currency
+	enabled    []string
+}{}
+
+func init() {
+	SetCurrencies([]Currency{
+		{Code: CAD, NumericCode: "124", MinorUnit: 2, Enabled: true},
+		{Code: EUR, NumericCode: "978", MinorUnit: 2, Enabled: true},
+		{Code: JPY, NumericCode: "392", MinorUnit: 0, Enabled: true},
+		{Code: KWD, NumericCode: "414", MinorUnit: 3, Enabled: true},
+		{Code: USD, NumericCode: "840", MinorUnit: 2, Enabled: true},
+	})
+}
+
+// SetCurrencies replaces the currencies of the registry
+func SetCurrencies(currencies []Currency) {
+	registry := make(map[string]Currency, len(currencies))
+	enabled := []string{}
+	for _, currency := range currencies {
+		registry[currency.Code] = currency
+		if currency.Enabled {
+			enabled = append(enabled, currency.Code)
+		}
+	}
+	sort.Strings(enabled)
+
+	currencyRegistry.Lock()
+	defer currencyRegistry.Unlock()
+	currencyRegistry.currencies = registry
+	currencyRegistry.enabled = enabled
+}
+
+// LookupCurrency returns the currency of the registry with code, enabled or not
+func LookupCurrency(code string) (Currency, bool) {
+	currencyRegistry.RLock()
+	defer currencyRegistry.RUnlock()
+	currency, ok := currencyRegistry.currencies[code]
+	return currency, ok
+}
+
+// EnabledCurrencies returns the codes of the enabled currencies in alphabetical order
+func EnabledCurrencies() []string {
+	currencyRegistry.RLock()
+	defer currencyRegistry.RUnlock()
+	return append([]string{}, currencyRegistry.enabled...)
+}
+
 // IsSupportedCurrency return true if currency is supported else otherwise
 func IsSupportedCurrency(currency string) bool {
-	switch currency {
-	case EUR, USD, CAD:
-		return true
-	}
-	return false
+	registered, ok := LookupCurrency(currency)
+	return ok && registered.Enabled
 }
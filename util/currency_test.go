@@ -0,0 +1,36 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetCurrencies(t *testing.T) {
+	defaults := []Currency{}
+	for _, code := range EnabledCurrencies() {
+		currency, ok := LookupCurrency(code)
+		require.True(t, ok)
+		defaults = append(defaults, currency)
+	}
+	defer SetCurrencies(defaults)
+
+	SetCurrencies([]Currency{
+		{Code: USD, NumericCode: "840", MinorUnit: 2, Enabled: true},
+		{Code: "GBP", NumericCode: "826", MinorUnit: 2, Enabled: false},
+		{Code: "BHD", NumericCode: "048", MinorUnit: 3, Enabled: true},
+	})
+
+	require.Equal(t, []string{"BHD", USD}, EnabledCurrencies())
+	require.True(t, IsSupportedCurrency("BHD"))
+	require.False(t, IsSupportedCurrency("GBP"))
+	require.False(t, IsSupportedCurrency(EUR))
+
+	currency, ok := LookupCurrency("GBP")
+	require.True(t, ok)
+	require.Equal(t, "826", currency.NumericCode)
+
+	for i := 0; i < 10; i++ {
+		require.Contains(t, []string{"BHD", USD}, RandomCurrency())
+	}
+}
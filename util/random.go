@@ -40,9 +40,9 @@ func RandomMoney() int64 {
 	return RandomInt(100, 10000)
 }
 
-// RandomCurrency generates random currency
+// RandomCurrency generates random currency among the enabled ones
 func RandomCurrency() string {
-	currencies := []string{EUR, USD, CAD}
+	currencies := EnabledCurrencies()
 	n := len(currencies)
 	return currencies[rand.Intn(n)]
 }
@@ -0,0 +1,20 @@
+package util
+
+// The roles of the users, the database refuses any other
+const (
+	// DepositorRole is the role of every customer, they can only act on their own accounts
+	DepositorRole = "depositor"
+	// BankerRole is the role of support staff, they can read every account
+	BankerRole = "banker"
+	// AdminRole can do everything, including adjusting balances
+	AdminRole = "admin"
+)
+
+// IsSupportedRole returns true if role is one of the roles above
+func IsSupportedRole(role string) bool {
+	switch role {
+	case DepositorRole, BankerRole, AdminRole:
+		return true
+	}
+	return false
+}
@@ -4,11 +4,19 @@ import (
 	"fmt"
 	"net/mail"
 	"regexp"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/October-9th/simple-bank/util"
 )
 
 var (
-	isValidUsername = regexp.MustCompile(`^[a-zA-Z0-9_]+$`).MatchString
-	isValidFullname = regexp.MustCompile(`^[a-zA-Z\\s]+$`).MatchString
+	isValidUsername       = regexp.MustCompile(`^[a-zA-Z0-9_]+$`).MatchString
+	isValidFullname       = regexp.MustCompile(`^[a-zA-Z\\s]+$`).MatchString
+	isValidIdempotencyKey = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`).MatchString
+	isValidReference      = regexp.MustCompile(`^[a-zA-Z0-9._:/#-]+$`).MatchString
+	isValidMetadataKey    = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`).MatchString
 )
 
 func ValidateString(value string, minLength int, maxLength int) error {
@@ -52,3 +60,186 @@ func ValidateFullName(value string) error {
 	}
 	return nil
 }
+
+func ValidateIdempotencyKey(value string) error {
+	if err := ValidateString(value, 1, 255); err != nil {
+		return err
+	}
+	if !isValidIdempotencyKey(value) {
+		return fmt.Errorf("must contain only letters, digits, dashes or underscores")
+	}
+	return nil
+}
+
+func ValidateID(value int64) error {
+	if value < 1 {
+		return fmt.Errorf("must be a positive integer")
+	}
+	return nil
+}
+
+func ValidateCurrency(value string) error {
+	if !util.IsSupportedCurrency(value) {
+		return fmt.Errorf("unsupported currency")
+	}
+	return nil
+}
+
+func ValidateRole(value string) error {
+	if !util.IsSupportedRole(value) {
+		return fmt.Errorf("unsupported role")
+	}
+	return nil
+}
+
+func ValidateAmount(value int64) error {
+	if value <= 0 {
+		return fmt.Errorf("must be greater than 0")
+	}
+	return nil
+}
+
+// ValidateTransferAccounts checks that a transfer goes to another account than the one it comes from
+func ValidateTransferAccounts(fromAccountID, toAccountID int64) error {
+	if fromAccountID == toAccountID {
+		return fmt.Errorf("must not be the same as from_account_id")
+	}
+	return nil
+}
+
+// ValidateAdjustmentAmount accepts negative amounts, an adjustment may take money out
+func ValidateAdjustmentAmount(value int64) error {
+	if value == 0 {
+		return fmt.Errorf("must not be 0")
+	}
+	return nil
+}
+
+// ValidateOverdraftLimit accepts 0, which removes the overdraft
+func ValidateOverdraftLimit(value int64) error {
+	if value < 0 {
+		return fmt.Errorf("must not be negative")
+	}
+	return nil
+}
+
+func ValidatePageID(value int32) error {
+	if value < 1 {
+		return fmt.Errorf("must be at least 1")
+	}
+	return nil
+}
+
+func ValidatePageSize(value int32) error {
+	if value < 5 || value > 10 {
+		return fmt.Errorf("must be from 5-10")
+	}
+	return nil
+}
+
+// MaxStatementPeriod bounds a single statement, longer histories are exported in several periods
+const MaxStatementPeriod = 366 * 24 * time.Hour
+
+// ValidateStatementPeriod checks that to comes after from and that the period isn't longer than MaxStatementPeriod
+func ValidateStatementPeriod(from, to time.Time) error {
+	if !to.After(from) {
+		return fmt.Errorf("must end after it starts")
+	}
+	if to.Sub(from) > MaxStatementPeriod {
+		return fmt.Errorf("must not be longer than %d days", MaxStatementPeriod/(24*time.Hour))
+	}
+	return nil
+}
+
+// Limits of the free-form fields of a transfer
+const (
+	MaxMemoLength          = 140
+	MaxReferenceLength     = 64
+	MaxMetadataKeys        = 20
+	MaxMetadataKeyLength   = 40
+	MaxMetadataValueLength = 500
+)
+
+// validateText checks that value is valid UTF-8 of at most maxLength characters without control characters
+func validateText(value string, maxLength int) error {
+	if !utf8.ValidString(value) {
+		return fmt.Errorf("must be valid UTF-8")
+	}
+	if utf8.RuneCountInString(value) > maxLength {
+		return fmt.Errorf("must contain at most %d characters", maxLength)
+	}
+	for _, r := range value {
+		if !unicode.IsPrint(r) && r != ' ' {
+			return fmt.Errorf("must not contain control characters")
+		}
+	}
+	return nil
+}
+
+// ValidateMemo accepts an empty memo, the memo is optional
+func ValidateMemo(value string) error {
+	return validateText(value, MaxMemoLength)
+}
+
+// ValidateExternalReference checks a reference from another system, e.g. an invoice number
+func ValidateExternalReference(value string) error {
+	if err := ValidateString(value, 1, MaxReferenceLength); err != nil {
+		return err
+	}
+	if !isValidReference(value) {
+		return fmt.Errorf("must contain only letters, digits or . _ : / # -")
+	}
+	return nil
+}
+
+// ValidateMetadata checks a map of string keys to string values attached to a transfer
+func ValidateMetadata(metadata map[string]string) error {
+	if len(metadata) > MaxMetadataKeys {
+		return fmt.Errorf("must contain at most %d keys", MaxMetadataKeys)
+	}
+	for key, value := range metadata {
+		if err := ValidateString(key, 1, MaxMetadataKeyLength); err != nil {
+			return fmt.Errorf("key %q %w", key, err)
+		}
+		if !isValidMetadataKey(key) {
+			return fmt.Errorf("key %q must contain only letters, digits or . _ -", key)
+		}
+		if err := validateText(value, MaxMetadataValueLength); err != nil {
+			return fmt.Errorf("value of %q %w", key, err)
+		}
+	}
+	return nil
+}
+
+// Directions of a transfer seen from the accounts of the user searching for it
+const (
+	DirectionSent     = "sent"
+	DirectionReceived = "received"
+)
+
+// ValidateDirection accepts an empty direction, which matches both
+func ValidateDirection(value string) error {
+	if value != "" && value != DirectionSent && value != DirectionReceived {
+		return fmt.Errorf("must be %s or %s", DirectionSent, DirectionReceived)
+	}
+	return nil
+}
+
+// ValidateAmountRange checks optional bounds of an amount, zero leaves a bound out
+func ValidateAmountRange(min, max int64) error {
+	if min < 0 || max < 0 {
+		return fmt.Errorf("must not be negative")
+	}
+	if max > 0 && min > max {
+		return fmt.Errorf("minimum must not be greater than maximum")
+	}
+	return nil
+}
+
+// ValidateTimeRange checks optional bounds of a period, a zero time leaves a bound out
+func ValidateTimeRange(from, to time.Time) error {
+	if !from.IsZero() && !to.IsZero() && !to.After(from) {
+		return fmt.Errorf("must end after it starts")
+	}
+	return nil
+}